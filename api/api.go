@@ -0,0 +1,13 @@
+// Package api holds the OpenAPI document of the NGCS Local Log Server and
+// generates the client package from it.
+package api
+
+import (
+	_ "embed"
+)
+
+// OpenAPI 3 document describing every route and payload of the log server,
+// served at /openapi.json
+//
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Every payload of the model with the schema describing it
+var modelTypes = map[string]interface{}{
+	"Logs_Event":             model.Logs_Event{},
+	"Logs_Event_Type":        model.Logs_Event_Type{},
+	"Logs_Test":              model.Logs_Test{},
+	"Logs_Test_Type":         model.Logs_Test_Type{},
+	"Logs_Maintenance":       model.Logs_Maintenance{},
+	"Loop_Data":              model.Loop_Data{},
+	"Chamber":                model.Chamber{},
+	"Channel":                model.Channel{},
+	"Calibration":            model.Calibration{},
+	"Calibrated_Value":       model.Calibrated_Value{},
+	"Loop_Data_Hourly":       model.Loop_Data_Hourly{},
+	"Io_card_Info":           model.Io_card_Info{},
+	"Logs_All":               model.Logs_All{},
+	"Logs_Test_Profile_Step": model.Logs_Test_Profile_Step{},
+	"Logs_Test_Profile":      model.Logs_Test_Profile{},
+	"Profile_Step_Result":    model.Profile_Step_Result{},
+	"Profile_Evaluation":     model.Profile_Evaluation{},
+}
+
+func TestSchemasMatchModel(t *testing.T) {
+
+	var spec struct {
+		Components struct {
+			Schemas map[string]struct {
+				Required   []string                   `json:"required"`
+				Properties map[string]json.RawMessage `json:"properties"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+
+	err := json.Unmarshal(Spec, &spec)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, value := range modelTypes {
+
+		schema, ok := spec.Components.Schemas[name]
+
+		if !ok {
+			t.Fatalf("no schema %s", name)
+		}
+
+		var fields, properties, required []string
+
+		typ := reflect.TypeOf(value)
+
+		for i := 0; i < typ.NumField(); i++ {
+
+			field, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			fields = append(fields, field)
+
+			if strings.Contains(typ.Field(i).Tag.Get("binding"), "required") {
+				required = append(required, field)
+			}
+		}
+
+		for property := range schema.Properties {
+			properties = append(properties, property)
+		}
+
+		sort.Strings(fields)
+		sort.Strings(properties)
+		sort.Strings(required)
+		sort.Strings(schema.Required)
+
+		if !reflect.DeepEqual(fields, properties) || strings.Join(required, ",") != strings.Join(schema.Required, ",") {
+			t.Fatalf("schema %s has properties %v required %v, model has %v required %v", name, properties, schema.Required, fields, required)
+		}
+	}
+}
+
+func TestClientIsGenerated(t *testing.T) {
+
+	source, err := GenerateClient(Spec)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated, err := os.ReadFile("../client/client_gen.go")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(source, generated) {
+		t.Fatal("client/client_gen.go is out of date, run go generate in client")
+	}
+}
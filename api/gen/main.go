@@ -0,0 +1,28 @@
+// Command gen writes the client package generated from the OpenAPI document,
+// run by "go generate" in the client directory.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Ramcharanpakala/goprojectes/api"
+)
+
+func main() {
+
+	output := flag.String("o", "client_gen.go", "file to write")
+	flag.Parse()
+
+	source, err := api.GenerateClient(api.Spec)
+
+	if err == nil {
+		err = os.WriteFile(*output, source, 0644)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,407 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// Struct to hold the parts of an OpenAPI document the client is generated
+// from
+
+type document struct {
+	Paths map[string]map[string]operation `json:"paths"`
+}
+
+type operation struct {
+	OperationId string              `json:"operationId"`
+	Summary     string              `json:"summary"`
+	Deprecated  bool                `json:"deprecated"`
+	Parameters  []parameter         `json:"parameters"`
+	RequestBody *content            `json:"requestBody"`
+	Responses   map[string]*content `json:"responses"`
+}
+
+type parameter struct {
+	Name   string `json:"name"`
+	In     string `json:"in"`
+	Schema schema `json:"schema"`
+}
+
+type content struct {
+	Content map[string]struct {
+		Schema schema `json:"schema"`
+	} `json:"content"`
+}
+
+type schema struct {
+	Ref        string            `json:"$ref"`
+	Type       string            `json:"type"`
+	Format     string            `json:"format"`
+	Items      *schema           `json:"items"`
+	AllOf      []schema          `json:"allOf"`
+	Properties map[string]schema `json:"properties"`
+}
+
+// Struct to hold one method of the generated client
+
+type method struct {
+	name       string
+	verb       string
+	doc        []string
+	params     []string
+	path       string
+	query      []parameter
+	body       string
+	idType     string
+	dataType   string
+	usesModel  bool
+	usesFormat bool
+}
+
+// Generate the Go source of the client package from an OpenAPI document:
+// one method of Client per operation, named by its operationId. An operation
+// whose response has data returns it decoded, one whose response has an
+// integer or string id returns the id, any other returns the Response.
+func GenerateClient(spec []byte) ([]byte, error) {
+
+	var doc document
+
+	err := json.Unmarshal(spec, &doc)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var methods []method
+
+	for path, operations := range doc.Paths {
+
+		for verb, op := range operations {
+
+			m, err := newMethod(path, strings.ToUpper(verb), op)
+
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %s", strings.ToUpper(verb), path, err.Error())
+			}
+
+			methods = append(methods, m)
+		}
+	}
+
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].name < methods[j].name
+	})
+
+	var out bytes.Buffer
+
+	imports := map[string]bool{}
+
+	for _, m := range methods {
+		imports["net/url"] = imports["net/url"] || len(m.query) > 0 || strings.Contains(m.path, "url.")
+		imports["strconv"] = imports["strconv"] || m.usesFormat
+		imports["github.com/Ramcharanpakala/goprojectes/model"] = imports["github.com/Ramcharanpakala/goprojectes/model"] || m.usesModel
+	}
+
+	fmt.Fprintln(&out, "// Code generated by api/gen from api/openapi.json. DO NOT EDIT.")
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "package client")
+	fmt.Fprintln(&out)
+	fmt.Fprintln(&out, "import (")
+
+	for _, name := range []string{"net/url", "strconv", "", "github.com/Ramcharanpakala/goprojectes/model"} {
+
+		if name == "" {
+			fmt.Fprintln(&out)
+		} else if imports[name] {
+			fmt.Fprintf(&out, "\t%q\n", name)
+		}
+	}
+
+	fmt.Fprintln(&out, ")")
+
+	for _, m := range methods {
+		m.write(&out)
+	}
+
+	return format.Source(out.Bytes())
+}
+
+func newMethod(path string, verb string, op operation) (method, error) {
+
+	m := method{name: op.OperationId, verb: verb}
+
+	if m.name == "" {
+		return m, fmt.Errorf("no operationId")
+	}
+
+	m.doc = append(m.doc, wrap(op.Summary, 76)...)
+	m.doc = append(m.doc, "", "\t"+verb+" "+path)
+
+	if op.Deprecated {
+		m.doc = append(m.doc, "", "Deprecated: kept for older controllers.")
+	}
+
+	// Path parameters in the order they appear in the path
+	pathTypes := map[string]string{}
+
+	for _, p := range op.Parameters {
+
+		switch p.In {
+		case "path":
+			pathTypes[p.Name] = p.Schema.Type
+
+			if p.Schema.Format == "date-time" {
+				pathTypes[p.Name] = "date-time"
+			}
+		case "query":
+			m.query = append(m.query, p)
+		}
+	}
+
+	pathExpr := []string{}
+
+	for rest := path; rest != ""; {
+
+		start := strings.Index(rest, "{")
+
+		if start < 0 {
+			pathExpr = append(pathExpr, fmt.Sprintf("%q", rest))
+			break
+		}
+
+		end := strings.Index(rest, "}")
+
+		if start > 0 {
+			pathExpr = append(pathExpr, fmt.Sprintf("%q", rest[:start]))
+		}
+
+		name := rest[start+1 : end]
+		arg := goName(name)
+
+		switch pathTypes[name] {
+		case "integer":
+			m.params = append(m.params, arg+" int")
+			pathExpr = append(pathExpr, "strconv.Itoa("+arg+")")
+			m.usesFormat = true
+		case "string":
+			m.params = append(m.params, arg+" string")
+			pathExpr = append(pathExpr, "url.PathEscape("+arg+")")
+		case "date-time":
+			m.params = append(m.params, arg+" model.Time")
+			pathExpr = append(pathExpr, "url.PathEscape("+arg+".String())")
+			m.usesModel = true
+		default:
+			return m, fmt.Errorf("path parameter %s is not described", name)
+		}
+
+		rest = rest[end+1:]
+	}
+
+	m.path = strings.Join(pathExpr, " + ")
+
+	for _, p := range m.query {
+
+		switch {
+		case p.Schema.Type == "integer":
+			m.params = append(m.params, goName(p.Name)+" int")
+			m.usesFormat = true
+		case p.Schema.Format == "date-time":
+			m.params = append(m.params, goName(p.Name)+" model.Time")
+			m.usesModel = true
+		default:
+			return m, fmt.Errorf("query parameter %s is neither an integer nor a date time", p.Name)
+		}
+	}
+
+	if op.RequestBody != nil {
+
+		body, err := goType(op.RequestBody.Content["application/json"].Schema)
+
+		if err != nil {
+			return m, err
+		}
+
+		m.params = append(m.params, "body *"+body)
+		m.body = "body"
+		m.usesModel = true
+	}
+
+	// The data of the first 2xx code
+	var codes []string
+
+	for code := range op.Responses {
+
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+
+	sort.Strings(codes)
+
+	if len(codes) == 0 {
+		return m, fmt.Errorf("no 2xx response")
+	}
+
+	// An id is returned only when every 2xx response has one of the same type
+	idTypes := map[string]bool{}
+
+	for _, code := range codes {
+
+		idType := ""
+
+		for _, part := range op.Responses[code].Content["application/json"].Schema.AllOf {
+
+			if data, ok := part.Properties["data"]; ok && code == codes[0] {
+
+				dataType, err := goType(data)
+
+				if err != nil {
+					return m, err
+				}
+
+				m.dataType = dataType
+				m.usesModel = true
+			}
+
+			if id, ok := part.Properties["id"]; ok {
+
+				switch id.Type {
+				case "integer":
+					idType = "int64"
+				case "string":
+					idType = "string"
+				}
+			}
+		}
+
+		idTypes[idType] = true
+		m.idType = idType
+	}
+
+	if len(idTypes) > 1 {
+		m.idType = ""
+	}
+
+	return m, nil
+}
+
+func (m method) write(out *bytes.Buffer) {
+
+	fmt.Fprintln(out)
+
+	for _, line := range m.doc {
+
+		if line == "" || strings.HasPrefix(line, "\t") {
+			fmt.Fprintln(out, "//"+line)
+		} else {
+			fmt.Fprintln(out, "// "+line)
+		}
+	}
+
+	result, returned, target := "*Response", "response", ""
+
+	switch {
+	case m.dataType != "":
+		result, returned, target = m.dataType, "data", "&data"
+	case m.idType != "":
+		result, returned, target = m.idType, "id", "&rowId"
+	}
+
+	fmt.Fprintf(out, "func (c *Client) %s(%s) (%s, error) {\n\n", m.name, strings.Join(m.params, ", "), result)
+
+	query := "nil"
+
+	if len(m.query) > 0 {
+
+		query = "query"
+
+		fmt.Fprintln(out, "\tquery := url.Values{}")
+
+		for _, p := range m.query {
+
+			arg := goName(p.Name)
+
+			if p.Schema.Format == "date-time" {
+				fmt.Fprintf(out, "\n\tif !%s.IsZero() {\n\t\tquery.Set(%q, %s.String())\n\t}\n", arg, p.Name, arg)
+			} else {
+				fmt.Fprintf(out, "\n\tif %s != 0 {\n\t\tquery.Set(%q, strconv.Itoa(%s))\n\t}\n", arg, p.Name, arg)
+			}
+		}
+
+		fmt.Fprintln(out)
+	}
+
+	body := "nil"
+
+	if m.body != "" {
+		body = m.body
+	}
+
+	switch returned {
+
+	case "response":
+		fmt.Fprintf(out, "\treturn c.call(%q, %s, %s, %s, nil, nil)\n}\n", m.verb, m.path, query, body)
+
+	case "data":
+		fmt.Fprintf(out, "\tvar data %s\n\n\t_, err := c.call(%q, %s, %s, %s, nil, %s)\n\n\treturn data, err\n}\n", m.dataType, m.verb, m.path, query, body, target)
+
+	case "id":
+		fmt.Fprintf(out, "\tvar rowId %s\n\n\t_, err := c.call(%q, %s, %s, %s, %s, nil)\n\n\treturn rowId, err\n}\n", m.idType, m.verb, m.path, query, body, target)
+	}
+}
+
+// Go type of a schema; components are the model types of the same name
+func goType(s schema) (string, error) {
+
+	switch {
+
+	case strings.HasPrefix(s.Ref, "#/components/schemas/"):
+		return "model." + strings.TrimPrefix(s.Ref, "#/components/schemas/"), nil
+
+	case s.Type == "array" && s.Items != nil:
+		item, err := goType(*s.Items)
+
+		return "[]" + item, err
+	}
+
+	return "", fmt.Errorf("no Go type for schema %+v", s)
+}
+
+// Go name of a parameter, e.g. date_time_date becomes dateTimeDate
+func goName(name string) string {
+
+	parts := strings.Split(name, "_")
+
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+
+	return strings.Join(parts, "")
+}
+
+// Split text into lines of at most width
+func wrap(text string, width int) []string {
+
+	var lines []string
+	var line string
+
+	for _, word := range strings.Fields(text) {
+
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+
+		if line != "" {
+			line += " "
+		}
+
+		line += word
+	}
+
+	return append(lines, line)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	logclient "github.com/Ramcharanpakala/goprojectes/client"
+	"github.com/Ramcharanpakala/goprojectes/config"
+)
+
+// Send a request to the log server and print the response, e.g.
+//
+//	ngcslog client GET Logs_Event
+//	ngcslog client -repeat 10 -interval 2s POST Logs_All ConfigAll.json
+//	ngcslog client -key TE001-1 POST Logs_Event LogEvent.json
+//	ngcslog client PUT "Loop_Data/2019-01-15 09:05:40" LoopDataUpdate.json
+//	ngcslog client -queue outbox.jsonl POST Logs_Event LogEvent.json
+//	ngcslog client -queue outbox.jsonl -wait 5m
+func client(args []string) {
+
+	flags := flag.NewFlagSet("client", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	repeat := flags.Int("repeat", 1, "number of times to send the request")
+	interval := flags.Duration("interval", 0, "pause between repeated requests")
+	key := flags.String("key", "", "Idempotency-Key of the request, repeats of it are stored once")
+	queueFile := flags.String("queue", "", "keep the request in this queue file until the log server stored it")
+	wait := flags.Duration("wait", 30*time.Second, "with -queue, how long to wait for the queue to be sent")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ngcslog client [flags] METHOD route [file.json]")
+		fmt.Fprintln(os.Stderr, "       ngcslog client -queue file [-wait duration] [METHOD route [file.json]]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	flushOnly := *queueFile != "" && flags.NArg() == 0
+
+	if (flags.NArg() < 2 || flags.NArg() > 3) && !flushOnly {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	method := strings.ToUpper(flags.Arg(0))
+	logServer := logclient.NewLocal(loadConfig(loader, config.Config.ValidateLog).Log)
+
+	if *queueFile != "" {
+		sendQueued(logServer, *queueFile, *wait, method, flags.Args())
+		return
+	}
+
+	var body []byte
+
+	if flags.NArg() == 3 {
+
+		var err error
+
+		body, err = ioutil.ReadFile(flags.Arg(2))
+
+		if err != nil {
+			fmt.Println("Error: Unable to read the request file.")
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	failed := false
+
+	for i := 1; i <= *repeat; i++ {
+
+		if i > 1 {
+			time.Sleep(*interval)
+		}
+
+		err := sendRequest(logServer, method, flags.Arg(1), *key, body)
+
+		if err != nil {
+			fmt.Println("Error:", err.Error())
+			failed = true
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// Queue the request of args, if any, in the queue file and wait for the
+// queue to be sent. Exits with status 1 when submissions are left, they are
+// sent by the next run.
+func sendQueued(logServer *logclient.Client, queueFile string, wait time.Duration, method string, args []string) {
+
+	queue, err := logclient.OpenQueue(logServer, queueFile)
+
+	if err != nil {
+		fmt.Println("Error: Unable to open the queue file.")
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	if len(args) >= 2 {
+
+		body := json.RawMessage("null")
+
+		if len(args) == 3 {
+
+			body, err = ioutil.ReadFile(args[2])
+
+			if err != nil {
+				fmt.Println("Error: Unable to read the request file.")
+				fmt.Println(err.Error())
+				os.Exit(1)
+			}
+		}
+
+		err = queue.Enqueue(method, "/"+strings.TrimPrefix(args[1], "/"), body)
+
+		if err != nil {
+			fmt.Println("Error: Unable to queue the request.")
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	}
+
+	err = queue.Flush(wait)
+
+	backlog := queue.Backlog()
+
+	queue.Close()
+
+	if err != nil {
+		fmt.Println("Error:", err.Error())
+		fmt.Printf("%d submissions wait in %s.\n", backlog, queueFile)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Queue %s is sent.\n", queueFile)
+}
+
+func sendRequest(logServer *logclient.Client, method string, route string, key string, body []byte) error {
+
+	resp, contents, err := logServer.DoIdempotent(method, route, key, body)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("response Status:", resp.Status)
+	fmt.Printf("%s\n", string(contents))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s %s failed with %s", method, route, resp.Status)
+	}
+
+	return nil
+}
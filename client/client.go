@@ -0,0 +1,194 @@
+// Package client calls the routes of the NGCS Local Log Server. The methods
+// of Client in client_gen.go are generated from api/openapi.json; run
+// "go generate" in this directory after changing it.
+package client
+
+//go:generate go run ../api/gen -o client_gen.go
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+)
+
+// Struct to hold the address of a log server, the API key sent to it and
+// the HTTP client used to reach it
+
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// Struct to hold the response of every route, see Response in api/openapi.json
+
+type Response struct {
+	Status string          `json:"status"`
+	Id     json.RawMessage `json:"id,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Errors []FieldError    `json:"errors,omitempty"`
+}
+
+// Struct to hold what was wrong with a request
+
+type FieldError struct {
+	Field  string      `json:"field,omitempty"`
+	Value  interface{} `json:"value,omitempty"`
+	Reason string      `json:"reason"`
+}
+
+// Struct to hold a request the log server answered with a 4xx or 5xx code
+
+type Error struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Errors     []FieldError
+}
+
+func (e *Error) Error() string {
+
+	var reasons []string
+
+	for _, fieldErr := range e.Errors {
+
+		if fieldErr.Field != "" {
+			reasons = append(reasons, fieldErr.Field+" "+fieldErr.Reason)
+		} else {
+			reasons = append(reasons, fieldErr.Reason)
+		}
+	}
+
+	return fmt.Sprintf("%s %s: %d %s: %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode), strings.Join(reasons, "; "))
+}
+
+// Client of the log server at baseURL, e.g. http://127.0.0.1:8181
+func New(baseURL string) *Client {
+
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTPClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Client of the local log server of the NGCS Log Config, sending
+// LocalLogServerAPIKey
+func NewLocal(logConfig config.NGCSLogConfig) *Client {
+
+	c := New("http://" + logConfig.LocalLogServerConnectStr())
+	c.APIKey = logConfig.LocalLogServerAPIKey
+
+	return c
+}
+
+// Send a request with a raw body and return the response with its body
+// read. Unlike the generated methods it does not check the status code.
+func (c *Client) Do(method string, path string, body []byte) (*http.Response, []byte, error) {
+
+	return c.DoIdempotent(method, path, "", body)
+}
+
+// Send a request like Do with an Idempotency-Key, so the log server stores
+// it once however often it is sent. An empty key sends none.
+func (c *Client) DoIdempotent(method string, path string, key string, body []byte) (*http.Response, []byte, error) {
+
+	var reader io.Reader
+
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+"/"+strings.TrimPrefix(path, "/"), reader)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer resp.Body.Close()
+
+	contents, err := io.ReadAll(resp.Body)
+
+	return resp, contents, err
+}
+
+// Send a request and decode the id and data of its response. Returns an
+// *Error when the log server rejected the request.
+func (c *Client) call(method string, path string, query url.Values, body interface{}, id interface{}, data interface{}) (*Response, error) {
+
+	var contents []byte
+
+	if body != nil {
+
+		var err error
+
+		contents, err = json.Marshal(body)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	resp, contents, err := c.Do(method, path, contents)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var response Response
+
+	err = json.Unmarshal(contents, &response)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %d response is not JSON: %s", method, path, resp.StatusCode, err.Error())
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest || response.Status != "ok" {
+		return &response, &Error{Method: method, Path: path, StatusCode: resp.StatusCode, Errors: response.Errors}
+	}
+
+	if id != nil && len(response.Id) > 0 {
+
+		err = json.Unmarshal(response.Id, id)
+
+		if err != nil {
+			return &response, err
+		}
+	}
+
+	if data != nil && len(response.Data) > 0 {
+
+		err = json.Unmarshal(response.Data, data)
+
+		if err != nil {
+			return &response, err
+		}
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,649 @@
+// Code generated by api/gen from api/openapi.json. DO NOT EDIT.
+
+package client
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Judge the Loop_Data recorded during a test against the profile of its type
+// and store the verdict
+//
+//	POST /Logs_Test/{log_id}/Evaluate
+func (c *Client) EvaluateTestLog(logId string, version int) (model.Profile_Evaluation, error) {
+
+	query := url.Values{}
+
+	if version != 0 {
+		query.Set("version", strconv.Itoa(version))
+	}
+
+	var data model.Profile_Evaluation
+
+	_, err := c.call("POST", "/Logs_Test/"+url.PathEscape(logId)+"/Evaluate", query, nil, nil, &data)
+
+	return data, err
+}
+
+// Read a chamber
+//
+//	GET /Chamber/{id}
+func (c *Client) GetChamber(id int) (model.Chamber, error) {
+
+	var data model.Chamber
+
+	_, err := c.call("GET", "/Chamber/"+strconv.Itoa(id), nil, nil, nil, &data)
+
+	return data, err
+}
+
+// Find a channel by name
+//
+//	GET /Channel/name/{name}
+func (c *Client) GetChannelByName(name string) (model.Channel, error) {
+
+	var data model.Channel
+
+	_, err := c.call("GET", "/Channel/name/"+url.PathEscape(name), nil, nil, nil, &data)
+
+	return data, err
+}
+
+// Find an event type by name
+//
+//	GET /Logs_Event_Type/name/{name}
+func (c *Client) GetEventTypeByName(name string) (model.Logs_Event_Type, error) {
+
+	var data model.Logs_Event_Type
+
+	_, err := c.call("GET", "/Logs_Event_Type/name/"+url.PathEscape(name), nil, nil, nil, &data)
+
+	return data, err
+}
+
+// Get the stored verdict of a test
+//
+//	GET /Logs_Test/{log_id}/Evaluation
+func (c *Client) GetTestEvaluation(logId string) (model.Profile_Evaluation, error) {
+
+	var data model.Profile_Evaluation
+
+	_, err := c.call("GET", "/Logs_Test/"+url.PathEscape(logId)+"/Evaluation", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// Get a version of the profile of a test type
+//
+//	GET /Logs_Test_Type/{id}/Profile
+func (c *Client) GetTestProfile(id int, version int) (model.Logs_Test_Profile, error) {
+
+	query := url.Values{}
+
+	if version != 0 {
+		query.Set("version", strconv.Itoa(version))
+	}
+
+	var data model.Logs_Test_Profile
+
+	_, err := c.call("GET", "/Logs_Test_Type/"+strconv.Itoa(id)+"/Profile", query, nil, nil, &data)
+
+	return data, err
+}
+
+// Find a test type by name
+//
+//	GET /Logs_Test_Type/name/{name}
+func (c *Client) GetTestTypeByName(name string) (model.Logs_Test_Type, error) {
+
+	var data model.Logs_Test_Type
+
+	_, err := c.call("GET", "/Logs_Test_Type/name/"+url.PathEscape(name), nil, nil, nil, &data)
+
+	return data, err
+}
+
+// Store a test, event and maintenance log together; nothing is stored when one
+// part is rejected
+//
+//	POST /Logs_All
+func (c *Client) InsertAll(body *model.Logs_All) (*Response, error) {
+
+	return c.call("POST", "/Logs_All", nil, body, nil, nil)
+}
+
+// Record the calibration of a sensor of a chamber
+//
+//	POST /Calibration
+func (c *Client) InsertCalibration(body *model.Calibration) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Calibration", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Register a chamber and the IO cards fitted to it
+//
+//	POST /Chamber
+func (c *Client) InsertChamber(body *model.Chamber) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Chamber", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Catalogue a channel
+//
+//	POST /Channel
+func (c *Client) InsertChannel(body *model.Channel) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Channel", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Store an event log. The type is given by ZTK_Logs_Event_Type_id, events_type
+// or an embedded ZTK_Logs_Event_Type.
+//
+//	POST /Logs_Event
+func (c *Client) InsertEventLog(body *model.Logs_Event) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Logs_Event", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Store an event type
+//
+//	POST /Logs_Event_Type
+func (c *Client) InsertEventType(body *model.Logs_Event_Type) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Logs_Event_Type", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Store the info of an IO card
+//
+//	POST /set_io_card_info
+func (c *Client) InsertIocardinfo(body *model.Io_card_Info) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/set_io_card_info", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Store a Loop_Data sample; a second sample of the chamber at the same date
+// time is rejected (422), use PUT to replace it
+//
+//	POST /Loop_Data
+func (c *Client) InsertLoopData(body *model.Loop_Data) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Loop_Data", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Store a maintenance log
+//
+//	POST /Logs_Maintenance
+func (c *Client) InsertMaintenanceLog(body *model.Logs_Maintenance) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Logs_Maintenance", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Store a test log. The type is given by ZTK_Logs_Test_Type_id, test_type or
+// an embedded ZTK_Logs_Test_Type.
+//
+//	POST /Logs_Test
+func (c *Client) InsertTestLog(body *model.Logs_Test) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Logs_Test", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Store a new version of the profile of a test type
+//
+//	POST /Logs_Test_Type/{id}/Profile
+func (c *Client) InsertTestProfile(id int, body *model.Logs_Test_Profile) (model.Logs_Test_Profile, error) {
+
+	var data model.Logs_Test_Profile
+
+	_, err := c.call("POST", "/Logs_Test_Type/"+strconv.Itoa(id)+"/Profile", nil, body, nil, &data)
+
+	return data, err
+}
+
+// Store a test type
+//
+//	POST /Logs_Test_Type
+func (c *Client) InsertTestType(body *model.Logs_Test_Type) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("POST", "/Logs_Test_Type", nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// List every calibration, oldest first
+//
+//	GET /Calibration
+func (c *Client) ListCalibrations() ([]model.Calibration, error) {
+
+	var data []model.Calibration
+
+	_, err := c.call("GET", "/Calibration", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List the calibrations of a chamber, oldest first
+//
+//	GET /Chamber/{id}/Calibration
+func (c *Client) ListChamberCalibrations(id int) ([]model.Calibration, error) {
+
+	var data []model.Calibration
+
+	_, err := c.call("GET", "/Chamber/"+strconv.Itoa(id)+"/Calibration", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List the event logs of a chamber
+//
+//	GET /Chamber/{id}/Logs_Event
+func (c *Client) ListChamberEventLogs(id int) ([]model.Logs_Event, error) {
+
+	var data []model.Logs_Event
+
+	_, err := c.call("GET", "/Chamber/"+strconv.Itoa(id)+"/Logs_Event", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List the IO cards fitted to a chamber
+//
+//	GET /Chamber/{id}/Io_card_info
+func (c *Client) ListChamberIocardinfo(id int) ([]model.Io_card_Info, error) {
+
+	var data []model.Io_card_Info
+
+	_, err := c.call("GET", "/Chamber/"+strconv.Itoa(id)+"/Io_card_info", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List the Loop_Data samples of a chamber, oldest first, optionally only those
+// captured from start up to end
+//
+//	GET /Chamber/{id}/Loop_Data
+func (c *Client) ListChamberLoopData(id int, start model.Time, end model.Time) ([]model.Loop_Data, error) {
+
+	query := url.Values{}
+
+	if !start.IsZero() {
+		query.Set("start", start.String())
+	}
+
+	if !end.IsZero() {
+		query.Set("end", end.String())
+	}
+
+	var data []model.Loop_Data
+
+	_, err := c.call("GET", "/Chamber/"+strconv.Itoa(id)+"/Loop_Data", query, nil, nil, &data)
+
+	return data, err
+}
+
+// List the hourly averages of the Loop_Data of a chamber pruned by the
+// retention policy, oldest first, optionally only the hours from start up to
+// end
+//
+//	GET /Chamber/{id}/Loop_Data/hourly
+func (c *Client) ListChamberLoopDataHourly(id int, start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error) {
+
+	query := url.Values{}
+
+	if !start.IsZero() {
+		query.Set("start", start.String())
+	}
+
+	if !end.IsZero() {
+		query.Set("end", end.String())
+	}
+
+	var data []model.Loop_Data_Hourly
+
+	_, err := c.call("GET", "/Chamber/"+strconv.Itoa(id)+"/Loop_Data/hourly", query, nil, nil, &data)
+
+	return data, err
+}
+
+// List the maintenance logs of a chamber
+//
+//	GET /Chamber/{id}/Logs_Maintenance
+func (c *Client) ListChamberMaintenanceLogs(id int) ([]model.Logs_Maintenance, error) {
+
+	var data []model.Logs_Maintenance
+
+	_, err := c.call("GET", "/Chamber/"+strconv.Itoa(id)+"/Logs_Maintenance", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List the test logs of a chamber
+//
+//	GET /Chamber/{id}/Logs_Test
+func (c *Client) ListChamberTestLogs(id int) ([]model.Logs_Test, error) {
+
+	var data []model.Logs_Test
+
+	_, err := c.call("GET", "/Chamber/"+strconv.Itoa(id)+"/Logs_Test", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List every chamber
+//
+//	GET /Chamber
+func (c *Client) ListChambers() ([]model.Chamber, error) {
+
+	var data []model.Chamber
+
+	_, err := c.call("GET", "/Chamber", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List every channel of the catalogue
+//
+//	GET /Channel
+func (c *Client) ListChannels() ([]model.Channel, error) {
+
+	var data []model.Channel
+
+	_, err := c.call("GET", "/Channel", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List every event log
+//
+//	GET /Logs_Event
+func (c *Client) ListEventLogs() ([]model.Logs_Event, error) {
+
+	var data []model.Logs_Event
+
+	_, err := c.call("GET", "/Logs_Event", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List every event type
+//
+//	GET /Logs_Event_Type
+func (c *Client) ListEventTypes() ([]model.Logs_Event_Type, error) {
+
+	var data []model.Logs_Event_Type
+
+	_, err := c.call("GET", "/Logs_Event_Type", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List the calibrations in force that are past their due date
+//
+//	GET /Calibration/expired
+func (c *Client) ListExpiredCalibrations(at model.Time) ([]model.Calibration, error) {
+
+	query := url.Values{}
+
+	if !at.IsZero() {
+		query.Set("at", at.String())
+	}
+
+	var data []model.Calibration
+
+	_, err := c.call("GET", "/Calibration/expired", query, nil, nil, &data)
+
+	return data, err
+}
+
+// List the info of every IO card
+//
+//	GET /Io_card_info
+func (c *Client) ListIocardinfo() ([]model.Io_card_Info, error) {
+
+	var data []model.Io_card_Info
+
+	_, err := c.call("GET", "/Io_card_info", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// Same as GET /Io_card_info, kept for older controllers
+//
+//	GET /get_io_card_info
+//
+// Deprecated: kept for older controllers.
+func (c *Client) ListIocardinfoLegacy() ([]model.Io_card_Info, error) {
+
+	var data []model.Io_card_Info
+
+	_, err := c.call("GET", "/get_io_card_info", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List the Loop_Data samples, oldest first, optionally only those captured
+// from start up to end
+//
+//	GET /Loop_Data
+func (c *Client) ListLoopData(start model.Time, end model.Time) ([]model.Loop_Data, error) {
+
+	query := url.Values{}
+
+	if !start.IsZero() {
+		query.Set("start", start.String())
+	}
+
+	if !end.IsZero() {
+		query.Set("end", end.String())
+	}
+
+	var data []model.Loop_Data
+
+	_, err := c.call("GET", "/Loop_Data", query, nil, nil, &data)
+
+	return data, err
+}
+
+// List the hourly averages of the Loop_Data pruned by the retention policy,
+// oldest first, optionally only the hours from start up to end
+//
+//	GET /Loop_Data/hourly
+func (c *Client) ListLoopDataHourly(start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error) {
+
+	query := url.Values{}
+
+	if !start.IsZero() {
+		query.Set("start", start.String())
+	}
+
+	if !end.IsZero() {
+		query.Set("end", end.String())
+	}
+
+	var data []model.Loop_Data_Hourly
+
+	_, err := c.call("GET", "/Loop_Data/hourly", query, nil, nil, &data)
+
+	return data, err
+}
+
+// List every maintenance log
+//
+//	GET /Logs_Maintenance
+func (c *Client) ListMaintenanceLogs() ([]model.Logs_Maintenance, error) {
+
+	var data []model.Logs_Maintenance
+
+	_, err := c.call("GET", "/Logs_Maintenance", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List every test log
+//
+//	GET /Logs_Test
+func (c *Client) ListTestLogs() ([]model.Logs_Test, error) {
+
+	var data []model.Logs_Test
+
+	_, err := c.call("GET", "/Logs_Test", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List every version of the profile of a test type
+//
+//	GET /Logs_Test_Type/{id}/Profile/versions
+func (c *Client) ListTestProfiles(id int) ([]model.Logs_Test_Profile, error) {
+
+	var data []model.Logs_Test_Profile
+
+	_, err := c.call("GET", "/Logs_Test_Type/"+strconv.Itoa(id)+"/Profile/versions", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// List every test type
+//
+//	GET /Logs_Test_Type
+func (c *Client) ListTestTypes() ([]model.Logs_Test_Type, error) {
+
+	var data []model.Logs_Test_Type
+
+	_, err := c.call("GET", "/Logs_Test_Type", nil, nil, nil, &data)
+
+	return data, err
+}
+
+// Update the Loop_Data sample of a date time, or store it when there is none
+// (201)
+//
+//	PUT /Loop_Data/{date_time_date}
+func (c *Client) PutLoopData(dateTimeDate model.Time, body *model.Loop_Data) (*Response, error) {
+
+	return c.call("PUT", "/Loop_Data/"+url.PathEscape(dateTimeDate.String()), nil, body, nil, nil)
+}
+
+// Retire a channel so new Loop_Data samples can no longer use it
+//
+//	DELETE /Channel/{id}
+func (c *Client) RetireChannel(id int) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("DELETE", "/Channel/"+strconv.Itoa(id), nil, nil, &rowId, nil)
+
+	return rowId, err
+}
+
+// Retire an event type so new logs can no longer use it
+//
+//	DELETE /Logs_Event_Type/{id}
+func (c *Client) RetireEventType(id int) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("DELETE", "/Logs_Event_Type/"+strconv.Itoa(id), nil, nil, &rowId, nil)
+
+	return rowId, err
+}
+
+// Retire a test type so new logs can no longer use it
+//
+//	DELETE /Logs_Test_Type/{id}
+func (c *Client) RetireTestType(id int) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("DELETE", "/Logs_Test_Type/"+strconv.Itoa(id), nil, nil, &rowId, nil)
+
+	return rowId, err
+}
+
+// Rename, move or refit a chamber; the IO cards sent replace the ones fitted
+//
+//	PUT /Chamber/{id}
+func (c *Client) UpdateChamber(id int, body *model.Chamber) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("PUT", "/Chamber/"+strconv.Itoa(id), nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Rename a channel or change its unit, range or description
+//
+//	PUT /Channel/{id}
+func (c *Client) UpdateChannel(id int, body *model.Channel) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("PUT", "/Channel/"+strconv.Itoa(id), nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Rename an event type
+//
+//	PUT /Logs_Event_Type/{id}
+func (c *Client) UpdateEventType(id int, body *model.Logs_Event_Type) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("PUT", "/Logs_Event_Type/"+strconv.Itoa(id), nil, body, &rowId, nil)
+
+	return rowId, err
+}
+
+// Rename a test type
+//
+//	PUT /Logs_Test_Type/{id}
+func (c *Client) UpdateTestType(id int, body *model.Logs_Test_Type) (int64, error) {
+
+	var rowId int64
+
+	_, err := c.call("PUT", "/Logs_Test_Type/"+strconv.Itoa(id), nil, body, &rowId, nil)
+
+	return rowId, err
+}
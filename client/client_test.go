@@ -0,0 +1,86 @@
+package client_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/client"
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/server"
+	"github.com/Ramcharanpakala/goprojectes/store/memory"
+	"github.com/gin-gonic/gin"
+)
+
+// Client of a log server on the memory store
+func newTestClient(t *testing.T) *client.Client {
+
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	server.Setup(memory.New(), config.NGCSLogConfig{})
+
+	router := gin.New()
+
+	server.InitialiseRoutes(router)
+
+	logServer := httptest.NewServer(router)
+
+	t.Cleanup(logServer.Close)
+
+	return client.New(logServer.URL)
+}
+
+func TestClient(t *testing.T) {
+
+	c := newTestClient(t)
+
+	typeId, err := c.InsertEventType(&model.Logs_Event_Type{Levents: "trips"})
+
+	if err != nil || typeId != 1 {
+		t.Fatalf("InsertEventType: %v, %v", typeId, err)
+	}
+
+	eventType, err := c.GetEventTypeByName("trips")
+
+	if err != nil || eventType.Lid != 1 {
+		t.Fatalf("GetEventTypeByName: %+v, %v", eventType, err)
+	}
+
+	_, err = c.InsertEventLog(&model.Logs_Event{Lid: "TE001", Pname: "ABC", Pdatetime: model.NewTime(time.Date(2019, 1, 3, 4, 25, 20, 0, time.UTC)), Etypeid: 1})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logs, err := c.ListEventLogs()
+
+	if err != nil || len(logs) != 1 || logs[0].Lid != "TE001" {
+		t.Fatalf("ListEventLogs: %+v, %v", logs, err)
+	}
+
+	sample := model.Loop_Data{Dtsp: 25, Dtpv: 24.8, Ddatatime: model.NewTime(time.Date(2019, 1, 15, 6, 5, 40, 0, time.UTC))}
+
+	if _, err = c.PutLoopData(sample.Ddatatime, &sample); err != nil {
+		t.Fatal(err)
+	}
+
+	// Rejected requests return an *Error with the invalid fields
+	_, err = c.InsertEventLog(&model.Logs_Event{Pname: "ABC", Pdatetime: model.NewTime(time.Date(2019, 1, 3, 4, 25, 20, 0, time.UTC)), Etypeid: 1})
+
+	var clientErr *client.Error
+
+	if !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusBadRequest || clientErr.Errors[0].Field != "log_id" {
+		t.Fatalf("invalid log: %v", err)
+	}
+
+	_, err = c.GetTestTypeByName("XYZ")
+
+	if !errors.As(err, &clientErr) || clientErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown type: %v", err)
+	}
+}
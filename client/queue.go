@@ -0,0 +1,598 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Pause before the first retry of a submission, doubled after every failure
+// up to retryMax
+var (
+	retryMin = time.Second
+	retryMax = time.Minute
+)
+
+// Sent submissions recorded in the queue file before it is rewritten
+const compactAfter = 1000
+
+// Struct to hold a submission waiting in the queue file, one JSON line each
+
+type queueEntry struct {
+	Seq    int64           `json:"seq"`
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Key    string          `json:"key"`
+	Body   json.RawMessage `json:"body"`
+	Queued model.Time      `json:"queued"`
+}
+
+// Struct to hold the line recording that a submission was sent
+
+type queueSent struct {
+	Sent int64 `json:"sent"`
+}
+
+// Struct to hold a submission the log server rejected, kept in the rejected
+// file so it is not lost
+
+type queueRejected struct {
+	queueEntry
+
+	StatusCode int          `json:"status_code"`
+	Errors     []FieldError `json:"errors,omitempty"`
+}
+
+// Struct to hold submissions for the log server that are kept in a file
+// until the server has stored them, so a controller loses no data while the
+// server restarts or the network is down.
+//
+// Submissions are sent one at a time in the order they were queued. When
+// the server cannot be reached, fails or is busy the submission is retried,
+// waiting 1 second after the first failure and doubling up to 1 minute; the
+// later ones wait behind it. Every submission is sent with its own
+// Idempotency-Key, so a retry after a timeout is stored once. A submission
+// the server rejects as invalid can never be stored: it is moved to the file
+// named like the queue file with ".rejected" appended and the next one is
+// sent.
+
+type Queue struct {
+	client *Client
+	path   string
+
+	mu       sync.Mutex
+	file     *os.File
+	pending  []queueEntry
+	nextSeq  int64
+	sent     int
+	lastErr  error
+	changed  chan struct{}
+	wake     chan struct{}
+	closing  chan struct{}
+	finished chan struct{}
+}
+
+// Open the queue file at path, creating it when it does not exist, and start
+// sending the submissions left in it to the log server of the client.
+func OpenQueue(c *Client, path string) (*Queue, error) {
+
+	q := &Queue{
+		client:   c,
+		path:     path,
+		nextSeq:  1,
+		changed:  make(chan struct{}),
+		wake:     make(chan struct{}, 1),
+		closing:  make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+
+	err := q.load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = q.rewrite()
+
+	if err != nil {
+		return nil, err
+	}
+
+	go q.run()
+
+	return q, nil
+}
+
+// Queue an event log
+func (q *Queue) InsertEventLog(log *model.Logs_Event) error {
+	return q.Enqueue("POST", "/Logs_Event", log)
+}
+
+// Queue a test log
+func (q *Queue) InsertTestLog(log *model.Logs_Test) error {
+	return q.Enqueue("POST", "/Logs_Test", log)
+}
+
+// Queue a maintenance log
+func (q *Queue) InsertMaintenanceLog(log *model.Logs_Maintenance) error {
+	return q.Enqueue("POST", "/Logs_Maintenance", log)
+}
+
+// Queue a Loop_Data sample
+func (q *Queue) InsertLoopData(log *model.Loop_Data) error {
+	return q.Enqueue("POST", "/Loop_Data", log)
+}
+
+// Queue a Loop_Data sample that replaces the one at its date time
+func (q *Queue) PutLoopData(dateTime model.Time, log *model.Loop_Data) error {
+	return q.Enqueue("PUT", "/Loop_Data/"+url.PathEscape(dateTime.String()), log)
+}
+
+// Queue a request to a route of the log server. The body is encoded to JSON
+// and written to the queue file before Enqueue returns.
+func (q *Queue) Enqueue(method string, path string, body interface{}) error {
+
+	contents, err := json.Marshal(body)
+
+	if err != nil {
+		return err
+	}
+
+	key, err := newIdempotencyKey()
+
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.file == nil {
+		return errors.New("queue is closed")
+	}
+
+	entry := queueEntry{Seq: q.nextSeq, Method: method, Path: path, Key: key, Body: contents, Queued: model.Now()}
+
+	err = q.append(entry)
+
+	if err != nil {
+		return err
+	}
+
+	q.nextSeq++
+	q.pending = append(q.pending, entry)
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// Number of submissions not yet stored by the log server
+func (q *Queue) Backlog() int {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// Date the oldest submission not yet stored was queued, zero when there is
+// none
+func (q *Queue) Oldest() model.Time {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		return model.Time{}
+	}
+
+	return q.pending[0].Queued
+}
+
+// Why the last submission failed or was rejected, nil when it was stored
+func (q *Queue) LastError() error {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.lastErr
+}
+
+// Wait until every submission is sent, at most the timeout. Returns the
+// last error when submissions are left.
+func (q *Queue) Flush(timeout time.Duration) error {
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	for {
+
+		q.mu.Lock()
+		backlog, changed, lastErr := len(q.pending), q.changed, q.lastErr
+		q.mu.Unlock()
+
+		if backlog == 0 {
+			return nil
+		}
+
+		select {
+
+		case <-changed:
+
+		case <-deadline.C:
+			if lastErr == nil {
+				lastErr = errors.New("log server did not answer")
+			}
+
+			return fmt.Errorf("%d submissions not sent: %w", backlog, lastErr)
+		}
+	}
+}
+
+// Stop sending and close the queue file. Submissions not yet sent stay in
+// the file and are sent by the next OpenQueue.
+func (q *Queue) Close() error {
+
+	q.mu.Lock()
+
+	if q.file == nil {
+		q.mu.Unlock()
+		return nil
+	}
+
+	close(q.closing)
+
+	q.mu.Unlock()
+
+	<-q.finished
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	err := q.file.Close()
+
+	q.file = nil
+
+	return err
+}
+
+// Send the submissions until the queue is closed
+func (q *Queue) run() {
+
+	defer close(q.finished)
+
+	retry := retryMin
+
+	for {
+
+		q.mu.Lock()
+
+		backlog := len(q.pending)
+
+		var next queueEntry
+
+		if backlog > 0 {
+			next = q.pending[0]
+		}
+
+		q.mu.Unlock()
+
+		if backlog == 0 {
+
+			select {
+			case <-q.wake:
+				continue
+			case <-q.closing:
+				return
+			}
+		}
+
+		err := q.send(next)
+
+		var rejected *Error
+
+		if errors.As(err, &rejected) && isRejection(rejected.StatusCode) {
+			err = q.reject(next, rejected)
+		} else {
+			rejected = nil
+		}
+
+		if err == nil {
+			err = q.done(next, rejected)
+		}
+
+		if err == nil {
+			retry = retryMin
+			continue
+		}
+
+		q.failed(err)
+
+		select {
+		case <-time.After(retry):
+		case <-q.closing:
+			return
+		}
+
+		retry = min(2*retry, retryMax)
+	}
+}
+
+// Send a submission, returning an *Error when the log server answered it
+// with a 4xx or 5xx code
+func (q *Queue) send(entry queueEntry) error {
+
+	var body []byte
+
+	// A request without a body is queued with a null one
+	if string(entry.Body) != "null" {
+		body = entry.Body
+	}
+
+	resp, contents, err := q.client.DoIdempotent(entry.Method, entry.Path, entry.Key, body)
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < http.StatusBadRequest {
+		return nil
+	}
+
+	var response Response
+
+	json.Unmarshal(contents, &response)
+
+	return &Error{Method: entry.Method, Path: entry.Path, StatusCode: resp.StatusCode, Errors: response.Errors}
+}
+
+// Report whether a status code rejects the submission itself, so sending
+// it again cannot succeed. A server that fails, does not know the API key
+// yet, is still processing the key or is overloaded may store it later.
+func isRejection(statusCode int) bool {
+
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusConflict, http.StatusTooManyRequests:
+		return false
+	}
+
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError
+}
+
+func (q *Queue) failed(err error) {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.lastErr = err
+
+	q.notify()
+}
+
+// Keep a rejected submission in the rejected file
+func (q *Queue) reject(entry queueEntry, rejected *Error) error {
+
+	line, err := json.Marshal(queueRejected{queueEntry: entry, StatusCode: rejected.StatusCode, Errors: rejected.Errors})
+
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(q.path+".rejected", os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(line, '\n'))
+
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Remove a submission the log server stored, or rejected when rejected is
+// set, from the queue
+func (q *Queue) done(entry queueEntry, rejected *Error) error {
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.file == nil {
+		return nil
+	}
+
+	line, _ := json.Marshal(queueSent{Sent: entry.Seq})
+
+	_, err := q.file.Write(append(line, '\n'))
+
+	if err == nil {
+		err = q.file.Sync()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	q.pending = q.pending[1:]
+	q.sent++
+
+	q.lastErr = nil
+
+	if rejected != nil {
+		q.lastErr = rejected
+	}
+
+	q.notify()
+
+	if len(q.pending) == 0 || q.sent >= compactAfter {
+		return q.rewrite()
+	}
+
+	return nil
+}
+
+// Wake the callers of Flush
+func (q *Queue) notify() {
+
+	close(q.changed)
+
+	q.changed = make(chan struct{})
+}
+
+// Append a line to the queue file, synced so it survives a power cut
+func (q *Queue) append(entry queueEntry) error {
+
+	line, err := json.Marshal(entry)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = q.file.Write(append(line, '\n'))
+
+	if err != nil {
+		return err
+	}
+
+	return q.file.Sync()
+}
+
+// Read the submissions not yet sent from the queue file. A last line cut
+// short by a crash is ignored.
+func (q *Queue) load() error {
+
+	contents, err := os.ReadFile(q.path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	sent := map[int64]bool{}
+
+	var entries []queueEntry
+
+	lines := bytes.Split(contents, []byte("\n"))
+
+	// The part after the last newline is empty unless a write was cut short
+	for i, text := range lines[:len(lines)-1] {
+
+		var line struct {
+			queueEntry
+			Sent int64 `json:"sent"`
+		}
+
+		err = json.Unmarshal(text, &line)
+
+		if err != nil {
+			return fmt.Errorf("queue file %s line %d: %s", q.path, i+1, err.Error())
+		}
+
+		if line.Sent > 0 {
+			sent[line.Sent] = true
+			continue
+		}
+
+		entries = append(entries, line.queueEntry)
+
+		if line.Seq >= q.nextSeq {
+			q.nextSeq = line.Seq + 1
+		}
+	}
+
+	for _, entry := range entries {
+
+		if !sent[entry.Seq] {
+			q.pending = append(q.pending, entry)
+		}
+	}
+
+	return nil
+}
+
+// Replace the queue file with the submissions not yet sent
+func (q *Queue) rewrite() error {
+
+	temp := q.path + ".tmp"
+
+	file, err := os.OpenFile(temp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+
+	for _, entry := range q.pending {
+
+		line, _ := json.Marshal(entry)
+
+		writer.Write(append(line, '\n'))
+	}
+
+	err = writer.Flush()
+
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	err = os.Rename(temp, q.path)
+
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	if q.file != nil {
+		q.file.Close()
+	}
+
+	q.file = file
+	q.sent = 0
+
+	return nil
+}
+
+// Random key the log server tells retries of a submission by
+func newIdempotencyKey() (string, error) {
+
+	key := make([]byte, 16)
+
+	_, err := rand.Read(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(key), nil
+}
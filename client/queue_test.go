@@ -0,0 +1,223 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Struct to hold a log server that answers 503 while it is down, 400 to
+// bodies holding "bad", and records the requests it stored
+
+type fakeServer struct {
+	mu     sync.Mutex
+	down   bool
+	stored []string
+	keys   map[string]int
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.keys[r.Header.Get("Idempotency-Key")]++
+
+	switch {
+
+	case s.down:
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, `{"status":"error","errors":[{"reason":"restarting"}]}`)
+
+	case strings.Contains(string(body), "bad"):
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"status":"error","errors":[{"field":"component_name","reason":"is required"}]}`)
+
+	default:
+		s.stored = append(s.stored, r.Method+" "+r.URL.Path+" "+string(body))
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"status":"ok","id":1}`)
+	}
+}
+
+func (s *fakeServer) setDown(down bool) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down = down
+}
+
+// Start a fake log server with retries shortened for the test
+func newFakeServer(t *testing.T) (*fakeServer, *Client) {
+
+	t.Helper()
+
+	retryMin, retryMax = 5*time.Millisecond, 20*time.Millisecond
+
+	t.Cleanup(func() { retryMin, retryMax = time.Second, time.Minute })
+
+	fake := &fakeServer{keys: map[string]int{}}
+
+	logServer := httptest.NewServer(fake)
+
+	t.Cleanup(logServer.Close)
+
+	return fake, New(logServer.URL)
+}
+
+func TestQueueKeepsOrderWhileServerIsDown(t *testing.T) {
+
+	fake, c := newFakeServer(t)
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	fake.setDown(true)
+
+	q, err := OpenQueue(c, path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"fan", "door", "heater"} {
+
+		if err = q.InsertMaintenanceLog(&model.Logs_Maintenance{Mname: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = q.Flush(50 * time.Millisecond); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("Flush while down: %v", err)
+	}
+
+	// Restarting the controller keeps the backlog
+	q.Close()
+
+	q, err = OpenQueue(c, path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer q.Close()
+
+	if q.Backlog() != 3 || q.Oldest().IsZero() {
+		t.Fatalf("backlog after reopening: %d, oldest %s", q.Backlog(), q.Oldest())
+	}
+
+	fake.setDown(false)
+
+	if err = q.Flush(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.stored) != 3 || !strings.Contains(fake.stored[0], "fan") || !strings.Contains(fake.stored[2], "heater") {
+		t.Fatalf("stored %v", fake.stored)
+	}
+
+	// Every retry of a submission is sent with its key
+	if len(fake.keys) != 3 {
+		t.Fatalf("Idempotency-Keys %v", fake.keys)
+	}
+
+	if q.Backlog() != 0 || q.LastError() != nil {
+		t.Fatalf("backlog %d, last error %v", q.Backlog(), q.LastError())
+	}
+
+	if contents, _ := os.ReadFile(path); len(contents) != 0 {
+		t.Fatalf("queue file not emptied: %s", contents)
+	}
+}
+
+func TestQueueMovesRejectedSubmissions(t *testing.T) {
+
+	fake, c := newFakeServer(t)
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	q, err := OpenQueue(c, path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer q.Close()
+
+	q.InsertMaintenanceLog(&model.Logs_Maintenance{Mname: "bad"})
+	q.PutLoopData(model.NewTime(time.Date(2019, 1, 15, 5, 5, 40, 0, time.UTC)), &model.Loop_Data{Dtsp: 25})
+
+	if err = q.Flush(time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.stored) != 1 || !strings.HasPrefix(fake.stored[0], "PUT /Loop_Data/2019-01-15T05:05:40Z ") {
+		t.Fatalf("stored %v", fake.stored)
+	}
+
+	contents, err := os.ReadFile(path + ".rejected")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var rejected queueRejected
+
+	err = json.Unmarshal(contents, &rejected)
+
+	if err != nil || rejected.StatusCode != http.StatusBadRequest || !strings.Contains(string(rejected.Body), "bad") || rejected.Errors[0].Field != "component_name" {
+		t.Fatalf("rejected file %s: %v", contents, err)
+	}
+}
+
+func TestQueueIgnoresCutShortLine(t *testing.T) {
+
+	fake, c := newFakeServer(t)
+
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+
+	fake.setDown(true)
+
+	os.WriteFile(path, []byte(`{"seq":1,"method":"POST","path":"/Logs_Maintenance","key":"k1","body":{"component_name":"fan"},"queued":null}
+{"seq":2,"method":"POST","path":"/Logs_Maintenance","key":"k2","body":{"component_name":"door"},"queued":null}
+{"sent":1}
+{"seq":3,"method":"PO`), 0644)
+
+	q, err := OpenQueue(c, path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.Backlog() != 1 {
+		t.Fatalf("backlog %d, want 1", q.Backlog())
+	}
+
+	// The sent submission leaves the file, new ones follow the last one
+	q.InsertMaintenanceLog(&model.Logs_Maintenance{Mname: "heater"})
+
+	q.Close()
+
+	contents, _ := os.ReadFile(path)
+
+	if !strings.Contains(string(contents), `"seq":2,`) || !strings.Contains(string(contents), `"seq":3,`) || strings.Contains(string(contents), `"seq":1,`) {
+		t.Fatalf("queue file %s", contents)
+	}
+
+	os.WriteFile(path, []byte("{\"seq\":1,\nnot json\n"), 0644)
+
+	if _, err = OpenQueue(c, path); err == nil || !strings.Contains(err.Error(), "line 1") {
+		t.Fatalf("damaged queue file: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Keys file used when APIKeysFile is not set
+const DefaultAPIKeysFile = "apikeys.json"
+
+// Struct to hold a caller of the log server: the key it sends in the
+// X-API-Key header and the user id its records are created by. The keys file
+// holds a JSON list of them:
+//
+//	[{"Key": "3c0f...", "UserId": 12, "Name": "chamber 4 controller"}]
+
+type APIKey struct {
+	Key    string
+	UserId int
+	Name   string
+}
+
+// Keys file of the log server
+func (ngcsLogConfig NGCSLogConfig) KeysFile() string {
+
+	if ngcsLogConfig.APIKeysFile == "" {
+		return DefaultAPIKeysFile
+	}
+
+	return ngcsLogConfig.APIKeysFile
+}
+
+// Path of the keys file of the log server
+func (cfg Config) APIKeysPath() string {
+
+	return cfg.path(cfg.Log.KeysFile())
+}
+
+// Read the keys file. Without a keys file there are no keys and requests
+// are not authenticated.
+func (cfg *Config) readAPIKeys() error {
+
+	path := cfg.APIKeysPath()
+
+	fail := func(reason string) error {
+		return &FieldError{Source: cfg.source("APIKeysFile"), Field: "APIKeysFile", Value: path, Reason: reason}
+	}
+
+	contents, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		cfg.APIKeys = nil
+		return nil
+	}
+
+	if err != nil {
+		return fail("cannot be read: " + fileError(err))
+	}
+
+	var keys []APIKey
+
+	err = json.Unmarshal(contents, &keys)
+
+	if err != nil {
+		return fail("is not a list of keys: " + err.Error())
+	}
+
+	seen := map[string]bool{}
+
+	for i, key := range keys {
+
+		switch {
+		case strings.TrimSpace(key.Key) == "":
+			return fail(fmt.Sprintf("key %d has no Key", i+1))
+		case seen[key.Key]:
+			return fail(fmt.Sprintf("key %d (%s) repeats a Key", i+1, key.Name))
+		case key.UserId <= 0:
+			return fail(fmt.Sprintf("key %d (%s) needs a UserId above 0", i+1, key.Name))
+		}
+
+		seen[key.Key] = true
+	}
+
+	cfg.APIKeys = keys
+
+	return nil
+}
+
+// Print a key without the key itself, so it never ends up in a log
+func (key APIKey) String() string {
+
+	return fmt.Sprintf("%s (user %d)", key.Name, key.UserId)
+}
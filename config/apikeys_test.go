@@ -0,0 +1,47 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAPIKeys(t *testing.T) {
+
+	dir := writeConfig(t, `{"DBDriver":"memory"}`, logConfigJSON)
+
+	cfg, err := load(t, "-config", dir)
+
+	if err != nil || cfg.APIKeys != nil {
+		t.Fatalf("without keys file: %v, %v", cfg.APIKeys, err)
+	}
+
+	os.WriteFile(filepath.Join(dir, DefaultAPIKeysFile), []byte(`[{"Key":"k-4","UserId":12,"Name":"chamber 4"}]`), 0600)
+
+	cfg, err = load(t, "-config", dir)
+
+	if err != nil || len(cfg.APIKeys) != 1 || cfg.APIKeys[0].UserId != 12 {
+		t.Fatalf("keys file: %v, %v", cfg.APIKeys, err)
+	}
+
+	if strings.Contains(cfg.APIKeys[0].String(), "k-4") {
+		t.Errorf("key printed: %s", cfg.APIKeys[0])
+	}
+
+	for contents, reason := range map[string]string{
+		`{"Key":"k-4"}`:                                   "is not a list of keys",
+		`[{"Key":" ","UserId":1}]`:                        "key 1 has no Key",
+		`[{"Key":"a","UserId":1},{"Key":"a","UserId":2}]`: "key 2 () repeats a Key",
+		`[{"Key":"a","Name":"x"}]`:                        "key 1 (x) needs a UserId above 0",
+	} {
+
+		os.WriteFile(filepath.Join(dir, DefaultAPIKeysFile), []byte(contents), 0600)
+
+		_, err = load(t, "-config", dir)
+
+		if err == nil || !strings.Contains(err.Error(), "APIKeysFile") || !strings.Contains(err.Error(), reason) {
+			t.Errorf("%s: got %v, want %q", contents, err, reason)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+// Package config reads the DBConfig and NGCSLogConfig files shared by the
+// NGCS Local Log Server and its tools, see Loader for how settings are
+// layered.
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	// TimeZone is known also on controllers without a zoneinfo database
+	_ "time/tzdata"
+)
+
+// Directory holding dbconfig.json and ngcsLogConfig.json when none is given
+const DefaultDir = "../../config"
+
+// Struct to hold DBConfig
+
+type DBConfig struct {
+	DBDriver          string
+	DBPath            string
+	DBServer          string
+	DBServerPort      int
+	DBUserName        string
+	DBPassword        string
+	DBPasswordKeyFile string
+	DBName            string
+	DBTimeZone        string
+}
+
+// Print the DBConfig without its password, so it never ends up in a log
+func (dbConfiguration DBConfig) String() string {
+
+	if dbConfiguration.DBPassword != "" {
+		dbConfiguration.DBPassword = "****"
+	}
+
+	type plain DBConfig
+
+	return fmt.Sprintf("%+v", plain(dbConfiguration))
+}
+
+func (dbConfiguration DBConfig) GoString() string {
+	return dbConfiguration.String()
+}
+
+// Struct to hold NGCSLogConfig
+
+type NGCSLogConfig struct {
+	LocalLogServer        string
+	LocalLogServerPort    int
+	RemoteLogServer       string
+	RemoteLogServerPort   int
+	LogRemotely           int
+	AutoCreateTypes       int
+	LogLevel              string
+	TempAlarmBand         float64
+	HumAlarmBand          float64
+	PressAlarmBand        float64
+	TimeZone              string
+	APIKeysFile           string
+	LocalLogServerAPIKey  string
+	RemoteLogServerAPIKey string
+
+	LoopDataRetentionDays    int
+	ActivityLogRetentionDays int
+	ArchiveDir               string
+}
+
+// Print the NGCSLogConfig without its API keys
+func (ngcsLogConfig NGCSLogConfig) String() string {
+
+	for _, key := range []*string{&ngcsLogConfig.LocalLogServerAPIKey, &ngcsLogConfig.RemoteLogServerAPIKey} {
+
+		if *key != "" {
+			*key = "****"
+		}
+	}
+
+	type plain NGCSLogConfig
+
+	return fmt.Sprintf("%+v", plain(ngcsLogConfig))
+}
+
+func (ngcsLogConfig NGCSLogConfig) GoString() string {
+	return ngcsLogConfig.String()
+}
+
+// Levels of LogLevel, from the fewest server messages to the most
+var LogLevels = []string{"error", "warn", "info", "debug"}
+
+// database/sql driver of the configured DB, mysql unless DBDriver is set
+func (dbConfiguration DBConfig) Driver() string {
+
+	if dbConfiguration.DBDriver == "" {
+		return "mysql"
+	}
+
+	return dbConfiguration.DBDriver
+}
+
+// Form the dbConnectStr for the configured DB
+func (dbConfiguration DBConfig) ConnectString() string {
+
+	var dbConnectionStr string
+
+	if dbConfiguration.Driver() == "sqlite3" {
+
+		// SQLite keeps the DB in the file at DBPath
+		dbConnectionStr += dbConfiguration.DBPath
+		dbConnectionStr += "?_foreign_keys=on&_busy_timeout=5000"
+
+		return dbConnectionStr
+	}
+
+	dbConnectionStr += dbConfiguration.DBUserName
+	dbConnectionStr += ":"
+	dbConnectionStr += dbConfiguration.DBPassword
+	dbConnectionStr += "@tcp("
+	dbConnectionStr += dbConfiguration.DBServer
+	dbConnectionStr += ":"
+	dbConnectionStr += strconv.Itoa(dbConfiguration.DBServerPort)
+	dbConnectionStr += ")/"
+	dbConnectionStr += dbConfiguration.DBName
+	dbConnectionStr += "?charset=utf8"
+
+	return dbConnectionStr
+}
+
+// Level of the server messages, info unless LogLevel is set
+func (ngcsLogConfig NGCSLogConfig) Level() string {
+
+	if ngcsLogConfig.LogLevel == "" {
+		return "info"
+	}
+
+	return ngcsLogConfig.LogLevel
+}
+
+// Zone of the chamber, e.g. Europe/Berlin, in which date times without a zone
+// are given. The zone of the controller unless TimeZone is set.
+func (ngcsLogConfig NGCSLogConfig) Location() (*time.Location, error) {
+
+	if ngcsLogConfig.TimeZone == "" {
+		return time.Local, nil
+	}
+
+	return time.LoadLocation(ngcsLogConfig.TimeZone)
+}
+
+// Zone the DATETIME columns of the DB hold date times in: UTC unless
+// DBTimeZone is set, for a DB written before date times were stored in UTC,
+// to the zone its rows were written in, e.g. Europe/Berlin or Local.
+func (dbConfiguration DBConfig) Location() (*time.Location, error) {
+
+	if dbConfiguration.DBTimeZone == "" {
+		return time.UTC, nil
+	}
+
+	return time.LoadLocation(dbConfiguration.DBTimeZone)
+}
+
+// Directory the pruned rows are archived to when ArchiveDir is not set
+const DefaultArchiveDir = "archive"
+
+// Directory the pruned rows are archived to
+func (ngcsLogConfig NGCSLogConfig) Archive() string {
+
+	if ngcsLogConfig.ArchiveDir == "" {
+		return DefaultArchiveDir
+	}
+
+	return ngcsLogConfig.ArchiveDir
+}
+
+// Path of the directory the pruned rows are archived to, relative to the
+// config directory unless ArchiveDir is absolute
+func (cfg Config) ArchivePath() string {
+
+	return cfg.path(cfg.Log.Archive())
+}
+
+// Create and return the connect string for the NGCS Local Log Server
+func (ngcsLogConfig NGCSLogConfig) LocalLogServerConnectStr() string {
+
+	return ngcsLogConfig.LocalLogServer + ":" + strconv.Itoa(ngcsLogConfig.LocalLogServerPort)
+}
+
+// Create and return the connect string for the NGCS Remote Log Server
+func (ngcsLogConfig NGCSLogConfig) RemoteLogServerConnectStr() string {
+
+	return ngcsLogConfig.RemoteLogServer + ":" + strconv.Itoa(ngcsLogConfig.RemoteLogServerPort)
+}
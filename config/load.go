@@ -0,0 +1,411 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Names of the config files in the config directory
+const (
+	DBConfigFile      = "dbconfig.json"
+	NGCSLogConfigFile = "ngcsLogConfig.json"
+)
+
+// Environment variable overriding the config directory. Every setting can be
+// overridden by NGCS_ followed by its name in upper case, e.g. NGCS_DBSERVER.
+const ConfigDirEnv = "NGCS_CONFIG"
+
+const envPrefix = "NGCS_"
+
+// Struct to hold the whole configuration and where each setting came from
+
+type Config struct {
+	Dir     string
+	DB      DBConfig
+	Log     NGCSLogConfig
+	APIKeys []APIKey
+	Sources map[string]string
+}
+
+// Struct to hold a setting that is wrong, with where it was set
+
+type FieldError struct {
+	Source string
+	Field  string
+	Value  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+
+	if e.Value == "" {
+		return fmt.Sprintf("%s: %s %s", e.Source, e.Field, e.Reason)
+	}
+
+	return fmt.Sprintf("%s: %s %q %s", e.Source, e.Field, e.Value, e.Reason)
+}
+
+// Struct to hold the -config flag and the settings given as flags
+
+type Loader struct {
+	dir       string
+	overrides map[string]string
+}
+
+// Register -config and one flag per setting on flags, e.g. -DBServer. Load
+// layers the config files, the environment and these flags in that order.
+func NewLoader(flags *flag.FlagSet) *Loader {
+
+	loader := &Loader{overrides: map[string]string{}}
+
+	flags.StringVar(&loader.dir, "config", "", "directory holding "+DBConfigFile+" and "+NGCSLogConfigFile+" (default $"+ConfigDirEnv+" or "+DefaultDir+")")
+
+	var cfg Config
+
+	for _, s := range cfg.settings() {
+
+		name := s.name
+
+		flags.Func(name, "override "+name+" of "+s.file, func(value string) error {
+			loader.overrides[name] = value
+			return nil
+		})
+	}
+
+	return loader
+}
+
+// Directory the config files are read from
+func (loader *Loader) Dir() string {
+
+	if loader.dir != "" {
+		return loader.dir
+	}
+
+	if dir := os.Getenv(ConfigDirEnv); dir != "" {
+		return dir
+	}
+
+	return DefaultDir
+}
+
+// Read the config files and overlay the environment and flags, then replace
+// the settings given as a reference by their secrets and read the keys file.
+// Every setting that cannot be read is reported as a *FieldError; use Validate,
+// ValidateDB or ValidateLog to check the result.
+func (loader *Loader) Load() (Config, error) {
+
+	cfg := Config{Dir: loader.Dir(), Sources: map[string]string{}}
+
+	var errs []error
+
+	settings := cfg.settings()
+
+	for _, file := range []string{DBConfigFile, NGCSLogConfigFile} {
+		errs = append(errs, cfg.readFile(file, settings)...)
+	}
+
+	for _, s := range settings {
+
+		env := envPrefix + strings.ToUpper(s.name)
+
+		if value, ok := os.LookupEnv(env); ok {
+			errs = append(errs, cfg.set(s, env, value))
+		}
+
+		if value, ok := loader.overrides[s.name]; ok {
+			errs = append(errs, cfg.set(s, "-"+s.name, value))
+		}
+	}
+
+	err := errors.Join(errs...)
+
+	if err == nil {
+		err = cfg.resolveSecrets()
+	}
+
+	if err == nil {
+		err = cfg.readAPIKeys()
+	}
+
+	return cfg, err
+}
+
+// Settings of earlier versions that are no longer read but still accepted in
+// the config files. The log server always stores the logs it receives, so
+// LogLocally is ignored.
+var retiredSettings = map[string]string{"LogLocally": NGCSLogConfigFile}
+
+// Struct to hold one setting of a config file
+
+type setting struct {
+	file  string
+	name  string
+	value reflect.Value
+}
+
+// Every setting of both config files, pointing into cfg
+func (cfg *Config) settings() []setting {
+
+	var settings []setting
+
+	for file, v := range map[string]reflect.Value{
+		DBConfigFile:      reflect.ValueOf(&cfg.DB).Elem(),
+		NGCSLogConfigFile: reflect.ValueOf(&cfg.Log).Elem(),
+	} {
+
+		for i := 0; i < v.NumField(); i++ {
+			settings = append(settings, setting{file, v.Type().Field(i).Name, v.Field(i)})
+		}
+	}
+
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].file < settings[j].file || (settings[i].file == settings[j].file && settings[i].name < settings[j].name)
+	})
+
+	return settings
+}
+
+// Read the settings of a config file. A missing file leaves them unset.
+func (cfg *Config) readFile(file string, settings []setting) []error {
+
+	path := filepath.Join(cfg.Dir, file)
+
+	contents, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return []error{err}
+	}
+
+	var values map[string]json.RawMessage
+
+	err = json.Unmarshal(contents, &values)
+
+	if err != nil {
+		return []error{fmt.Errorf("%s: %s", path, err.Error())}
+	}
+
+	var keys []string
+
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var errs []error
+
+	for _, key := range keys {
+
+		raw := values[key]
+
+		found := false
+
+		for _, s := range settings {
+
+			if s.file != file || !strings.EqualFold(s.name, key) {
+				continue
+			}
+
+			found = true
+
+			var value interface{}
+
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.UseNumber()
+			decoder.Decode(&value)
+
+			switch value.(type) {
+			case string, json.Number:
+				errs = append(errs, cfg.set(s, path, fmt.Sprint(value)))
+			default:
+				errs = append(errs, &FieldError{Source: path, Field: s.name, Value: string(raw), Reason: "must be a string or number"})
+			}
+		}
+
+		if !found && retiredSettings[key] != file {
+			errs = append(errs, &FieldError{Source: path, Field: key, Reason: "is not a setting of " + file})
+		}
+	}
+
+	return errs
+}
+
+// Set a setting from its text, recording where it came from
+func (cfg *Config) set(s setting, source string, value string) error {
+
+	switch s.value.Kind() {
+
+	case reflect.Int:
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+
+		if err != nil {
+			return &FieldError{Source: source, Field: s.name, Value: value, Reason: "is not a whole number"}
+		}
+
+		s.value.SetInt(int64(number))
+
+	case reflect.Float64:
+		number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+
+		if err != nil {
+			return &FieldError{Source: source, Field: s.name, Value: value, Reason: "is not a number"}
+		}
+
+		s.value.SetFloat(number)
+
+	default:
+		s.value.SetString(value)
+	}
+
+	cfg.Sources[s.name] = source
+
+	return nil
+}
+
+// Where a setting was set, for messages
+func (cfg Config) source(name string) string {
+
+	if source, ok := cfg.Sources[name]; ok {
+		return source
+	}
+
+	return "not set in " + filepath.Join(cfg.Dir, DBConfigFile) + ", " + filepath.Join(cfg.Dir, NGCSLogConfigFile) + ", " + envPrefix + strings.ToUpper(name) + " or -" + name
+}
+
+// Check both the DB and the NGCS Log settings
+func (cfg Config) Validate() error {
+
+	return errors.Join(cfg.ValidateDB(), cfg.ValidateLog())
+}
+
+// Check that every setting needed by the configured DB is present and in
+// range.
+func (cfg Config) ValidateDB() error {
+
+	v := validator{cfg: cfg}
+
+	switch cfg.DB.Driver() {
+
+	case "mysql":
+		v.required("DBServer", cfg.DB.DBServer, "is required for DBDriver mysql")
+		v.port("DBServerPort", cfg.DB.DBServerPort)
+		v.required("DBUserName", cfg.DB.DBUserName, "is required for DBDriver mysql")
+		v.required("DBName", cfg.DB.DBName, "is required for DBDriver mysql")
+
+	case "sqlite3":
+		v.required("DBPath", cfg.DB.DBPath, "is required for DBDriver sqlite3")
+
+	case "memory":
+
+	default:
+		v.fail("DBDriver", cfg.DB.DBDriver, "must be mysql, sqlite3 or memory")
+	}
+
+	if _, err := cfg.DB.Location(); err != nil {
+		v.fail("DBTimeZone", cfg.DB.DBTimeZone, "is not a time zone like Europe/Berlin, Local or UTC")
+	}
+
+	return errors.Join(v.errs...)
+}
+
+// Check that the settings of the log server are present and in range.
+func (cfg Config) ValidateLog() error {
+
+	v := validator{cfg: cfg}
+
+	v.port("LocalLogServerPort", cfg.Log.LocalLogServerPort)
+	v.flag("LogRemotely", cfg.Log.LogRemotely)
+	v.flag("AutoCreateTypes", cfg.Log.AutoCreateTypes)
+	v.oneOf("LogLevel", cfg.Log.Level(), LogLevels)
+	v.band("TempAlarmBand", cfg.Log.TempAlarmBand)
+	v.band("HumAlarmBand", cfg.Log.HumAlarmBand)
+	v.band("PressAlarmBand", cfg.Log.PressAlarmBand)
+	v.days("LoopDataRetentionDays", cfg.Log.LoopDataRetentionDays)
+	v.days("ActivityLogRetentionDays", cfg.Log.ActivityLogRetentionDays)
+
+	if _, err := cfg.Log.Location(); err != nil {
+		v.fail("TimeZone", cfg.Log.TimeZone, "is not a time zone like Europe/Berlin or UTC")
+	}
+
+	if cfg.Log.LogRemotely == 1 {
+		v.required("RemoteLogServer", cfg.Log.RemoteLogServer, "is required when LogRemotely is 1")
+		v.port("RemoteLogServerPort", cfg.Log.RemoteLogServerPort)
+	}
+
+	return errors.Join(v.errs...)
+}
+
+// Struct to hold the errors found while validating a Config
+
+type validator struct {
+	cfg  Config
+	errs []error
+}
+
+func (v *validator) fail(name string, value string, reason string) {
+
+	v.errs = append(v.errs, &FieldError{Source: v.cfg.source(name), Field: name, Value: value, Reason: reason})
+}
+
+func (v *validator) required(name string, value string, reason string) {
+
+	if strings.TrimSpace(value) == "" {
+		v.fail(name, "", reason)
+	}
+}
+
+func (v *validator) port(name string, value int) {
+
+	if value < 1 || value > 65535 {
+		v.fail(name, strconv.Itoa(value), "must be a port between 1 and 65535")
+	}
+}
+
+func (v *validator) flag(name string, value int) {
+
+	if value != 0 && value != 1 {
+		v.fail(name, strconv.Itoa(value), "must be 0 or 1")
+	}
+}
+
+func (v *validator) oneOf(name string, value string, values []string) {
+
+	for _, allowed := range values {
+
+		if value == allowed {
+			return
+		}
+	}
+
+	v.fail(name, value, "must be "+strings.Join(values[:len(values)-1], ", ")+" or "+values[len(values)-1])
+}
+
+// An alarm band of 0 turns the alarm off
+func (v *validator) band(name string, value float64) {
+
+	if value < 0 {
+		v.fail(name, strconv.FormatFloat(value, 'g', -1, 64), "must not be negative")
+	}
+}
+
+// A retention of 0 days keeps the rows forever
+func (v *validator) days(name string, value int) {
+
+	if value < 0 {
+		v.fail(name, strconv.Itoa(value), "must not be negative")
+	}
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Write the config files into a new directory
+func writeConfig(t *testing.T, dbConfig string, logConfig string) string {
+
+	t.Helper()
+
+	dir := t.TempDir()
+
+	for file, contents := range map[string]string{DBConfigFile: dbConfig, NGCSLogConfigFile: logConfig} {
+
+		err := os.WriteFile(filepath.Join(dir, file), []byte(contents), 0644)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// Load the configuration with the command line args
+func load(t *testing.T, args ...string) (Config, error) {
+
+	t.Helper()
+
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	loader := NewLoader(flags)
+
+	err := flags.Parse(args)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return loader.Load()
+}
+
+func TestLoadLayers(t *testing.T) {
+
+	// ngcsLogConfig.json of an earlier version, with the retired LogLocally
+	dir := writeConfig(t,
+		`{"DBServer":"127.0.0.1","DBServerPort":3306,"DBUserName":"root","DBPassword":"superuser","DBName":"klima_chamber"}`,
+		`{"LocalLogServer":"localhost","LocalLogServerPort":8080,"LogLocally":1}`)
+
+	t.Setenv("NGCS_DBSERVER", "db.local")
+	t.Setenv("NGCS_LOCALLOGSERVERPORT", "8081")
+
+	cfg, err := load(t, "-config", dir, "-LocalLogServerPort", "8082")
+
+	if err == nil {
+		err = cfg.Validate()
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.DB.DBServer != "db.local" || cfg.DB.DBName != "klima_chamber" || cfg.Log.LocalLogServerPort != 8082 {
+		t.Fatalf("layered config %+v", cfg)
+	}
+
+	if cfg.Sources["DBServer"] != "NGCS_DBSERVER" || cfg.Sources["LocalLogServerPort"] != "-LocalLogServerPort" || cfg.Sources["DBName"] != filepath.Join(dir, DBConfigFile) {
+		t.Fatalf("sources %v", cfg.Sources)
+	}
+}
+
+func TestLoadConfigDirFromEnv(t *testing.T) {
+
+	dir := writeConfig(t, `{"DBDriver":"memory"}`, `{"LocalLogServerPort":8080}`)
+
+	t.Setenv(ConfigDirEnv, dir)
+
+	cfg, err := load(t)
+
+	if err != nil || cfg.Dir != dir || cfg.DB.Driver() != "memory" {
+		t.Fatalf("config %+v, %v", cfg, err)
+	}
+}
+
+func TestLoadReportsWrongSettings(t *testing.T) {
+
+	dir := writeConfig(t, `{"DBServer":"127.0.0.1","DBServerPort":"abc","DBHost":"x"}`, `{"LocalLogServerPort":8080}`)
+
+	_, err := load(t, "-config", dir)
+
+	var fieldErr *FieldError
+
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("want FieldError, got %v", err)
+	}
+
+	for _, want := range []string{`DBServerPort "abc" is not a whole number`, "DBHost is not a setting of dbconfig.json"} {
+
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not report %q", err.Error(), want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+
+	dir := writeConfig(t, `{"DBServer":"127.0.0.1","DBServerPort":70000,"DBName":"klima_chamber"}`, `{"LocalLogServerPort":8080,"LogRemotely":1,"LoopDataRetentionDays":-1}`)
+
+	cfg, err := load(t, "-config", dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = cfg.Validate()
+
+	if err == nil {
+		t.Fatal("invalid config accepted")
+	}
+
+	for _, want := range []string{
+		filepath.Join(dir, DBConfigFile) + `: DBServerPort "70000" must be a port between 1 and 65535`,
+		"DBUserName is required for DBDriver mysql",
+		"RemoteLogServer is required when LogRemotely is 1",
+		`LoopDataRetentionDays "-1" must not be negative`,
+	} {
+
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("error %q does not report %q", err.Error(), want)
+		}
+	}
+
+	if cfg.ValidateLog() == nil || cfg.ValidateDB() == nil {
+		t.Fatal("ValidateDB and ValidateLog must both fail")
+	}
+
+	if path := cfg.ArchivePath(); path != filepath.Join(dir, DefaultArchiveDir) {
+		t.Fatalf("ArchivePath %q", path)
+	}
+
+	cfg.DB = DBConfig{DBDriver: "sqlite3", DBPath: "klima_chamber.db", DBTimeZone: "Europe/Nowhere"}
+
+	if err = cfg.ValidateDB(); err == nil || !strings.Contains(err.Error(), "DBTimeZone") {
+		t.Fatalf("DBTimeZone Europe/Nowhere: %v", err)
+	}
+
+	cfg.DB.DBTimeZone = "Europe/Berlin"
+
+	if err = cfg.ValidateDB(); err != nil {
+		t.Fatal(err)
+	}
+}
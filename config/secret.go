@@ -0,0 +1,213 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Prefixes of a DBPassword or API key setting that refers to the secret
+// instead of holding it:
+//
+//	file:path   the first line of the file, relative to the config directory
+//	env:NAME    the value of the environment variable
+//	enc:value   the value encrypted by "ngcslog secret encrypt" with the key
+//	            in DBPasswordKeyFile
+//
+// Any other value is the secret itself.
+const (
+	SecretFilePrefix = "file:"
+	SecretEnvPrefix  = "env:"
+	SecretEncPrefix  = "enc:"
+)
+
+// Key file used for enc: passwords when DBPasswordKeyFile is not set
+const DefaultKeyFile = "dbpassword.key"
+
+// Replace the references of DBPassword and the API key settings with the
+// secrets they refer to
+func (cfg *Config) resolveSecrets() error {
+
+	return errors.Join(
+		cfg.resolveSecret("DBPassword", &cfg.DB.DBPassword),
+		cfg.resolveSecret("LocalLogServerAPIKey", &cfg.Log.LocalLogServerAPIKey),
+		cfg.resolveSecret("RemoteLogServerAPIKey", &cfg.Log.RemoteLogServerAPIKey),
+	)
+}
+
+// Replace a setting that refers to a secret with the secret
+func (cfg *Config) resolveSecret(name string, secret *string) error {
+
+	value := *secret
+
+	fail := func(shown string, reason string) error {
+		return &FieldError{Source: cfg.source(name), Field: name, Value: shown, Reason: reason}
+	}
+
+	switch {
+
+	case strings.HasPrefix(value, SecretFilePrefix):
+		contents, err := os.ReadFile(cfg.path(strings.TrimPrefix(value, SecretFilePrefix)))
+
+		if err != nil {
+			return fail(value, "cannot be read: "+fileError(err))
+		}
+
+		line, _, _ := strings.Cut(string(contents), "\n")
+
+		*secret = strings.TrimSuffix(line, "\r")
+
+	case strings.HasPrefix(value, SecretEnvPrefix):
+		env, ok := os.LookupEnv(strings.TrimPrefix(value, SecretEnvPrefix))
+
+		if !ok {
+			return fail(value, "refers to an environment variable that is not set")
+		}
+
+		*secret = env
+
+	case strings.HasPrefix(value, SecretEncPrefix):
+		key, err := ReadKey(cfg.KeyPath())
+
+		if err == nil {
+			*secret, err = Decrypt(strings.TrimPrefix(value, SecretEncPrefix), key)
+		}
+
+		if err != nil {
+			return fail("", "cannot be decrypted: "+err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Path of a file named in a setting, relative to the config directory
+func (cfg Config) path(name string) string {
+
+	if filepath.IsAbs(name) {
+		return name
+	}
+
+	return filepath.Join(cfg.Dir, name)
+}
+
+// Path of the key file for enc: passwords
+func (cfg Config) KeyPath() string {
+
+	return cfg.path(cfg.DB.KeyFile())
+}
+
+// Key file for enc: passwords
+func (dbConfiguration DBConfig) KeyFile() string {
+
+	if dbConfiguration.DBPasswordKeyFile == "" {
+		return DefaultKeyFile
+	}
+
+	return dbConfiguration.DBPasswordKeyFile
+}
+
+// Create a new random key, encoded for a key file
+func GenerateKey() (string, error) {
+
+	key := make([]byte, 32)
+
+	_, err := rand.Read(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Read the key of a key file
+func ReadKey(path string) ([]byte, error) {
+
+	contents, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("key file %s: %s", path, fileError(err))
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("key file %s does not hold a key made by \"ngcslog secret keygen\"", path)
+	}
+
+	return key, nil
+}
+
+// Encrypt a password with AES-256-GCM, returning the value to put after enc:
+func Encrypt(password string, key []byte) (string, error) {
+
+	aead, err := newAEAD(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+
+	_, err = rand.Read(nonce)
+
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(password), nil)), nil
+}
+
+// Decrypt a value made by Encrypt
+func Decrypt(value string, key []byte) (string, error) {
+
+	aead, err := newAEAD(key)
+
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(value)
+
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("value is not made by \"ngcslog secret encrypt\"")
+	}
+
+	password, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+
+	if err != nil {
+		return "", errors.New("value was encrypted with another key")
+	}
+
+	return string(password), nil
+}
+
+// Reason a file could not be read, without repeating its path
+func fileError(err error) string {
+
+	var pathErr *os.PathError
+
+	if errors.As(err, &pathErr) {
+		return pathErr.Err.Error()
+	}
+
+	return err.Error()
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+
+	block, err := aes.NewCipher(key)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
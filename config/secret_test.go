@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const logConfigJSON = `{"LocalLogServerPort":8080}`
+
+func TestPasswordReferences(t *testing.T) {
+
+	key, err := GenerateKey()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := writeConfig(t, `{"DBDriver":"memory"}`, logConfigJSON)
+
+	os.WriteFile(filepath.Join(dir, DefaultKeyFile), []byte(key+"\n"), 0600)
+	os.WriteFile(filepath.Join(dir, "dbpassword"), []byte("from-file\r\nsecond line\n"), 0600)
+
+	t.Setenv("KLIMA_DB_PASSWORD", "from-env")
+
+	rawKey, _ := ReadKey(filepath.Join(dir, DefaultKeyFile))
+
+	encrypted, err := Encrypt("from-enc", rawKey)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for value, want := range map[string]string{
+		"superuser":                 "superuser",
+		"file:dbpassword":           "from-file",
+		"env:KLIMA_DB_PASSWORD":     "from-env",
+		SecretEncPrefix + encrypted: "from-enc",
+	} {
+
+		cfg, err := load(t, "-config", dir, "-DBPassword", value)
+
+		if err != nil || cfg.DB.DBPassword != want {
+			t.Fatalf("DBPassword %q: got %q, %v", value, cfg.DB.DBPassword, err)
+		}
+	}
+}
+
+func TestPasswordErrors(t *testing.T) {
+
+	dir := writeConfig(t, `{"DBDriver":"memory"}`, logConfigJSON)
+
+	otherKey, _ := GenerateKey()
+	os.WriteFile(filepath.Join(dir, "other.key"), []byte(otherKey), 0600)
+
+	key, _ := GenerateKey()
+	os.WriteFile(filepath.Join(dir, DefaultKeyFile), []byte(key), 0600)
+
+	rawKey, _ := ReadKey(filepath.Join(dir, DefaultKeyFile))
+	encrypted, _ := Encrypt("s3cret", rawKey)
+
+	for _, args := range [][]string{
+		{"-DBPassword", "file:missing"},
+		{"-DBPassword", "env:KLIMA_NOT_SET"},
+		{"-DBPassword", SecretEncPrefix + encrypted, "-DBPasswordKeyFile", "other.key"},
+		{"-DBPassword", SecretEncPrefix + encrypted, "-DBPasswordKeyFile", "missing.key"},
+	} {
+
+		_, err := load(t, append([]string{"-config", dir}, args...)...)
+
+		if err == nil || !strings.Contains(err.Error(), "DBPassword") {
+			t.Fatalf("%v: got %v", args, err)
+		}
+
+		if strings.Contains(err.Error(), "s3cret") || strings.Contains(err.Error(), encrypted) {
+			t.Fatalf("%v: error shows the password: %v", args, err)
+		}
+	}
+}
+
+func TestDBConfigHidesPassword(t *testing.T) {
+
+	dbConfiguration := DBConfig{DBServer: "127.0.0.1", DBUserName: "root", DBPassword: "s3cret"}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+
+		if text := fmt.Sprintf(format, dbConfiguration); strings.Contains(text, "s3cret") {
+			t.Fatalf("%s shows the password: %s", format, text)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Struct to hold the Config in use and the state of the config files it was
+// loaded from
+
+type Watcher struct {
+	loader   *Loader
+	validate func(Config) error
+
+	mu      sync.Mutex
+	current Config
+	files   map[string]fileState
+}
+
+// Struct to hold what a config file looked like when it was last read
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+	exists  bool
+}
+
+// Watch the config files of loader, starting from the Config in use. Configs
+// that fail validate are rejected.
+func NewWatcher(loader *Loader, validate func(Config) error, current Config) *Watcher {
+
+	w := &Watcher{loader: loader, validate: validate, current: current}
+
+	w.files = w.stat()
+
+	return w
+}
+
+// Config in use
+func (w *Watcher) Current() Config {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.current
+}
+
+// Reload the configuration when a config file changed since it was last
+// read. Returns the settings that changed, nil when no file changed.
+func (w *Watcher) Check() (Config, []string, error) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	files := w.stat()
+
+	if reflect.DeepEqual(files, w.files) {
+		return w.current, nil, nil
+	}
+
+	w.files = files
+
+	return w.reload()
+}
+
+// Reload the configuration now, e.g. on SIGHUP. Returns the settings that
+// changed.
+func (w *Watcher) Reload() (Config, []string, error) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.files = w.stat()
+
+	return w.reload()
+}
+
+// Load and validate the configuration. An invalid one is returned with its
+// errors and the Config in use is kept.
+func (w *Watcher) reload() (Config, []string, error) {
+
+	cfg, err := w.loader.Load()
+
+	if err == nil {
+		err = w.validate(cfg)
+	}
+
+	if err != nil {
+		return w.current, nil, err
+	}
+
+	changed := Changed(w.current, cfg)
+
+	w.current = cfg
+
+	return cfg, changed, nil
+}
+
+func (w *Watcher) stat() map[string]fileState {
+
+	files := map[string]fileState{}
+
+	paths := []string{
+		filepath.Join(w.loader.Dir(), DBConfigFile),
+		filepath.Join(w.loader.Dir(), NGCSLogConfigFile),
+		w.current.APIKeysPath(),
+	}
+
+	for _, path := range paths {
+
+		info, err := os.Stat(path)
+
+		if err == nil {
+			files[path] = fileState{modTime: info.ModTime(), size: info.Size(), exists: true}
+		}
+	}
+
+	return files
+}
+
+// Names of the settings that differ between two Configs, and APIKeys when
+// the keys file changed
+func Changed(old Config, cfg Config) []string {
+
+	var changed []string
+
+	oldSettings := old.settings()
+
+	for i, s := range cfg.settings() {
+
+		if !reflect.DeepEqual(s.value.Interface(), oldSettings[i].value.Interface()) {
+			changed = append(changed, s.name)
+		}
+	}
+
+	if !reflect.DeepEqual(old.APIKeys, cfg.APIKeys) {
+		changed = append(changed, "APIKeys")
+	}
+
+	return changed
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcherReloads(t *testing.T) {
+
+	dir := writeConfig(t, `{"DBDriver":"memory"}`, `{"LocalLogServerPort":8080}`)
+
+	cfg, err := load(t, "-config", dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader := Loader{dir: dir, overrides: map[string]string{}}
+
+	watcher := NewWatcher(&loader, Config.Validate, cfg)
+
+	if _, changed, err := watcher.Check(); changed != nil || err != nil {
+		t.Fatalf("unchanged files reloaded: %v, %v", changed, err)
+	}
+
+	logConfig := filepath.Join(dir, NGCSLogConfigFile)
+
+	rewrite := func(contents string) {
+
+		os.WriteFile(logConfig, []byte(contents), 0644)
+
+		// Make the change visible on file systems with a coarse mtime
+		later := time.Now().Add(time.Duration(len(contents)) * time.Second)
+		os.Chtimes(logConfig, later, later)
+	}
+
+	rewrite(`{"LocalLogServerPort":8080,"LogRemotely":1,"RemoteLogServer":"10.0.0.2","RemoteLogServerPort":8090,"TempAlarmBand":2.5}`)
+
+	cfg, changed, err := watcher.Check()
+
+	want := []string{"LogRemotely", "RemoteLogServer", "RemoteLogServerPort", "TempAlarmBand"}
+
+	if err != nil || !reflect.DeepEqual(changed, want) || cfg.Log.TempAlarmBand != 2.5 {
+		t.Fatalf("reload: %v, %v, %+v", changed, err, cfg.Log)
+	}
+
+	rewrite(`{"LocalLogServerPort":8080,"LogRemotely":1,"LogLevel":"loud"}`)
+
+	cfg, changed, err = watcher.Check()
+
+	if err == nil || !strings.Contains(err.Error(), "RemoteLogServer is required") || !strings.Contains(err.Error(), `LogLevel "loud" must be error, warn, info or debug`) {
+		t.Fatalf("invalid config accepted: %v", err)
+	}
+
+	if changed != nil || watcher.Current().Log.RemoteLogServer != "10.0.0.2" || cfg.Log.LogLevel != "" {
+		t.Fatalf("invalid config replaced the one in use: %+v", watcher.Current().Log)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/server"
+)
+
+// Write every row of a table to stdout as JSON, e.g.
+//
+//	ngcslog export Loop_Data > loop_data.json
+func export(args []string) {
+
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ngcslog export [flags] table\n\nTables: %s\n", strings.Join(server.TableNames(), ", "))
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	cfg := loadConfig(loader, config.Config.ValidateDB)
+
+	logStore := openStore(cfg.DB)
+
+	defer logStore.Close()
+
+	server.Setup(logStore, cfg.Log)
+
+	logs, err := server.ReadTable(flags.Arg(0))
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+
+	err = encoder.Encode(logs)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(1)
+	}
+}
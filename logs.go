@@ -0,0 +1,708 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	logclient "github.com/Ramcharanpakala/goprojectes/client"
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Struct to hold a log type of the logs command: the routes it is read from
+// and posted to, the model struct of its rows, the date column -since and
+// -until filter on and whether the read route filters on it by its start
+// and end query parameters
+
+type logType struct {
+	read   string
+	post   string
+	row    interface{}
+	date   string
+	ranged bool
+}
+
+var logTypes = map[string]logType{
+	"Logs_Event":       {"/Logs_Event", "/Logs_Event", model.Logs_Event{}, "created_date", false},
+	"Logs_Event_Type":  {"/Logs_Event_Type", "/Logs_Event_Type", model.Logs_Event_Type{}, "create_date", false},
+	"Logs_Test":        {"/Logs_Test", "/Logs_Test", model.Logs_Test{}, "created_date", false},
+	"Logs_Test_Type":   {"/Logs_Test_Type", "/Logs_Test_Type", model.Logs_Test_Type{}, "create_date", false},
+	"Logs_Maintenance": {"/Logs_Maintenance", "/Logs_Maintenance", model.Logs_Maintenance{}, "created_date", false},
+	"Loop_Data":        {"/Loop_Data", "/Loop_Data", model.Loop_Data{}, "date_time_date", true},
+	"Io_card_info":     {"/Io_card_info", "/set_io_card_info", model.Io_card_Info{}, "created_date", false},
+}
+
+// Flag that may be given more than once, e.g. -where a=1 -where b=2
+
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *repeatedFlag) Set(value string) error {
+
+	*f = append(*f, value)
+
+	return nil
+}
+
+// Query and post the logs of the log server in ngcsLogConfig.json, e.g.
+//
+//	ngcslog logs list -since 24h -where events_type=trips Logs_Event
+//	ngcslog logs list -format csv -fields date_time_date,temp_pv Loop_Data > loop.csv
+//	ngcslog logs post -file LogMaintenance.json -set counter=2 Logs_Maintenance
+//	ngcslog logs tail -fields date_time_date,temp_sp,temp_pv
+func logs(args []string) {
+
+	if len(args) < 1 {
+		logsUsage()
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "list":
+		listLogs(args[1:])
+	case "post":
+		postLog(args[1:])
+	case "tail":
+		tailLoopData(args[1:])
+	default:
+		logsUsage()
+		os.Exit(2)
+	}
+}
+
+func logsUsage() {
+
+	fmt.Fprintf(os.Stderr, `Usage: ngcslog logs list [flags] type
+       ngcslog logs post [flags] type
+       ngcslog logs tail [flags]
+
+Types: %s
+
+Run "ngcslog logs <list|post|tail> -h" for the flags.
+`, strings.Join(logTypeNames(), ", "))
+}
+
+// Names of the log types, sorted
+func logTypeNames() []string {
+
+	var names []string
+
+	for name := range logTypes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// List the rows of a log type, filtered, as a table, CSV or JSON
+func listLogs(args []string) {
+
+	flags := flag.NewFlagSet("logs list", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	var where repeatedFlag
+	flags.Var(&where, "where", "only rows whose `field=value`, may be repeated")
+	since := flags.String("since", "", "only rows dated at or after this date time, or this long ago like 24h")
+	until := flags.String("until", "", "only rows dated before this date time, or this long ago")
+	limit := flags.Int("limit", 0, "only the last n rows")
+	format := flags.String("format", "table", "output format: table, csv or json")
+	fields := flags.String("fields", "", "comma separated fields to show, default all")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ngcslog logs list [flags] type\n\nTypes: %s\n", strings.Join(logTypeNames(), ", "))
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	logType := findLogType(flags.Arg(0))
+	columns := selectColumns(logType, *fields)
+	start := parseSince(*since, "-since")
+	end := parseSince(*until, "-until")
+
+	checkFormat(*format)
+
+	filters := map[string]string{}
+
+	for _, condition := range where {
+
+		field, value, ok := strings.Cut(condition, "=")
+
+		if !ok {
+			fail(2, "-where %q is not field=value.", condition)
+		}
+
+		checkField(logType, field)
+
+		filters[field] = value
+	}
+
+	logServer := logclient.NewLocal(loadConfig(loader, config.Config.ValidateLog).Log)
+
+	rows, err := readLogs(logServer, logType, start, end)
+
+	if err != nil {
+		fail(1, "%s", err.Error())
+	}
+
+	// The log server filters the types it can by date already
+	if logType.ranged {
+		start, end = model.Time{}, model.Time{}
+	}
+
+	var kept []map[string]interface{}
+
+	for _, row := range rows {
+
+		if matches(row, filters) && between(row[logType.date], start, end) {
+			kept = append(kept, row)
+		}
+	}
+
+	if *limit > 0 && len(kept) > *limit {
+		kept = kept[len(kept)-*limit:]
+	}
+
+	err = writeRows(os.Stdout, *format, columns, kept, true)
+
+	if err != nil {
+		fail(1, "%s", err.Error())
+	}
+}
+
+// Post a record from a JSON file, flags or both, the flags winning
+func postLog(args []string) {
+
+	flags := flag.NewFlagSet("logs post", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	var set repeatedFlag
+	file := flags.String("file", "", "JSON file holding the record")
+	flags.Var(&set, "set", "set `field=value` of the record, may be repeated")
+	key := flags.String("key", "", "Idempotency-Key of the request, repeats of it are stored once")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: ngcslog logs post [flags] type\n\nTypes: %s\n", strings.Join(logTypeNames(), ", "))
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 || (*file == "" && len(set) == 0) {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	logType := findLogType(flags.Arg(0))
+
+	record := map[string]interface{}{}
+
+	if *file != "" {
+
+		contents, err := os.ReadFile(*file)
+
+		if err != nil {
+			fail(1, "Unable to read the record file: %s", err.Error())
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(contents))
+		decoder.UseNumber()
+
+		err = decoder.Decode(&record)
+
+		if err != nil {
+			fail(1, "%s is not a JSON object: %s", *file, err.Error())
+		}
+	}
+
+	for _, assignment := range set {
+
+		field, value, ok := strings.Cut(assignment, "=")
+
+		if !ok {
+			fail(2, "-set %q is not field=value.", assignment)
+		}
+
+		parsed, err := fieldValue(logType, field, value)
+
+		if err != nil {
+			fail(2, "-set %s: %s", assignment, err.Error())
+		}
+
+		record[field] = parsed
+	}
+
+	body, _ := json.Marshal(record)
+
+	logServer := logclient.NewLocal(loadConfig(loader, config.Config.ValidateLog).Log)
+
+	resp, contents, err := logServer.DoIdempotent("POST", logType.post, *key, body)
+
+	if err != nil {
+		fail(1, "%s", err.Error())
+	}
+
+	var response logclient.Response
+
+	json.Unmarshal(contents, &response)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+
+		fmt.Fprintf(os.Stderr, "Error: POST %s failed with %s\n", logType.post, resp.Status)
+
+		for _, fieldErr := range response.Errors {
+
+			switch {
+			case fieldErr.Field != "" && cell(fieldErr.Value) != "":
+				fmt.Fprintf(os.Stderr, "    %s %v: %s\n", fieldErr.Field, fieldErr.Value, fieldErr.Reason)
+			case fieldErr.Field != "":
+				fmt.Fprintf(os.Stderr, "    %s: %s\n", fieldErr.Field, fieldErr.Reason)
+			default:
+				fmt.Fprintf(os.Stderr, "    %s\n", fieldErr.Reason)
+			}
+		}
+
+		os.Exit(1)
+	}
+
+	fmt.Printf("Stored %s, id %s.\n", flags.Arg(0), response.Id)
+}
+
+// Print the Loop_Data as it is captured, until interrupted
+func tailLoopData(args []string) {
+
+	flags := flag.NewFlagSet("logs tail", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	since := flags.String("since", "10m", "start with the samples captured since this date time, or this long ago")
+	interval := flags.Duration("interval", 2*time.Second, "pause between polls of the log server")
+	format := flags.String("format", "table", "output format: table, csv or json")
+	fields := flags.String("fields", "", "comma separated fields to show, default all")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ngcslog logs tail [flags]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	logType := logTypes["Loop_Data"]
+	columns := selectColumns(logType, *fields)
+	start := parseSince(*since, "-since")
+
+	checkFormat(*format)
+
+	logServer := logclient.NewLocal(loadConfig(loader, config.Config.ValidateLog).Log)
+
+	header := true
+
+	position := tailPosition{last: start}
+
+	for {
+
+		rows, err := readLogs(logServer, logType, position.last, model.Time{})
+
+		// The log server may be restarting, keep polling
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		}
+
+		if rows = position.next(rows); len(rows) > 0 {
+
+			err = writeRows(os.Stdout, *format, columns, rows, header)
+
+			if err != nil {
+				fail(1, "%s", err.Error())
+			}
+
+			header = false
+		}
+
+		time.Sleep(*interval)
+	}
+}
+
+// Struct to hold how far logs tail has printed Loop_Data: the date time of
+// the last sample printed and the chambers whose sample at that date time
+// was printed. The next poll reads from that date time on, so the samples
+// of other chambers stored at the same second later are printed too.
+
+type tailPosition struct {
+	last    model.Time
+	printed map[string]bool
+}
+
+// Samples of a poll, oldest first, that were not printed yet, moving the
+// position past them
+func (position *tailPosition) next(rows []map[string]interface{}) []map[string]interface{} {
+
+	var fresh []map[string]interface{}
+
+	for _, row := range rows {
+
+		date, err := model.ParseTime(cell(row["date_time_date"]))
+
+		if err != nil || date.Before(position.last.Time) {
+			continue
+		}
+
+		chamber := cell(row["ZTK_Chamber_id"])
+
+		if date.Equal(position.last.Time) && position.printed[chamber] {
+			continue
+		}
+
+		if !date.Equal(position.last.Time) || position.printed == nil {
+			position.last = date
+			position.printed = map[string]bool{}
+		}
+
+		position.printed[chamber] = true
+
+		fresh = append(fresh, row)
+	}
+
+	return fresh
+}
+
+func findLogType(name string) logType {
+
+	logType, ok := logTypes[name]
+
+	if !ok {
+		fail(2, "Unknown type %q, use one of %s.", name, strings.Join(logTypeNames(), ", "))
+	}
+
+	return logType
+}
+
+// Read the rows of a log type as JSON objects. A ranged type is read from
+// start up to end only, the other types are filtered by the caller.
+func readLogs(logServer *logclient.Client, logType logType, start model.Time, end model.Time) ([]map[string]interface{}, error) {
+
+	path := logType.read
+
+	if logType.ranged {
+
+		query := url.Values{}
+
+		if !start.IsZero() {
+			query.Set("start", start.String())
+		}
+
+		if !end.IsZero() {
+			query.Set("end", end.String())
+		}
+
+		if len(query) > 0 {
+			path += "?" + query.Encode()
+		}
+	}
+
+	resp, contents, err := logServer.Do("GET", path, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var response logclient.Response
+
+	err = json.Unmarshal(contents, &response)
+
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %d response is not JSON", path, resp.StatusCode)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, &logclient.Error{Method: "GET", Path: path, StatusCode: resp.StatusCode, Errors: response.Errors}
+	}
+
+	var rows []map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(response.Data))
+	decoder.UseNumber()
+
+	err = decoder.Decode(&rows)
+
+	if err == io.EOF {
+		err = nil
+	}
+
+	return rows, err
+}
+
+// Columns of a log type in the order of its model struct: the fields of
+// its JSON objects except nested records
+func typeColumns(logType logType) []string {
+
+	var columns []string
+
+	rowType := reflect.TypeOf(logType.row)
+
+	for i := 0; i < rowType.NumField(); i++ {
+
+		field := rowType.Field(i)
+
+		if field.Type.Kind() == reflect.Ptr || field.Type.Kind() == reflect.Slice {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		if name != "" && name != "-" {
+			columns = append(columns, name)
+		}
+	}
+
+	return columns
+}
+
+// Columns named by -fields, or every column of the log type
+func selectColumns(logType logType, fields string) []string {
+
+	if fields == "" {
+		return typeColumns(logType)
+	}
+
+	var columns []string
+
+	for _, field := range strings.Split(fields, ",") {
+
+		field = strings.TrimSpace(field)
+
+		checkField(logType, field)
+
+		columns = append(columns, field)
+	}
+
+	return columns
+}
+
+func checkField(logType logType, field string) {
+
+	columns := typeColumns(logType)
+
+	for _, column := range columns {
+
+		if column == field {
+			return
+		}
+	}
+
+	fail(2, "Unknown field %q, use one of %s.", field, strings.Join(columns, ", "))
+}
+
+// Value of a -set flag in the JSON type of the field
+func fieldValue(logType logType, field string, value string) (interface{}, error) {
+
+	rowType := reflect.TypeOf(logType.row)
+
+	for i := 0; i < rowType.NumField(); i++ {
+
+		name, _, _ := strings.Cut(rowType.Field(i).Tag.Get("json"), ",")
+
+		if name != field {
+			continue
+		}
+
+		switch rowType.Field(i).Type.Kind() {
+
+		case reflect.Int, reflect.Int64:
+			number, err := strconv.ParseInt(value, 10, 64)
+
+			if err != nil {
+				return nil, errors.New("is not a whole number")
+			}
+
+			return number, nil
+
+		case reflect.Float64:
+			number, err := strconv.ParseFloat(value, 64)
+
+			if err != nil {
+				return nil, errors.New("is not a number")
+			}
+
+			return number, nil
+		}
+
+		return value, nil
+	}
+
+	return nil, fmt.Errorf("unknown field, use one of %s", strings.Join(typeColumns(logType), ", "))
+}
+
+// Date time of a -since or -until flag: a date time, or a duration before now
+func parseSince(value string, name string) model.Time {
+
+	if value == "" {
+		return model.Time{}
+	}
+
+	if ago, err := time.ParseDuration(value); err == nil {
+		return model.NewTime(time.Now().Add(-ago))
+	}
+
+	t, err := model.ParseTime(value)
+
+	if err != nil {
+		fail(2, "%s %s", name, err.Error())
+	}
+
+	return t
+}
+
+// Report whether the row has every field=value of filters
+func matches(row map[string]interface{}, filters map[string]string) bool {
+
+	for field, value := range filters {
+
+		if cell(row[field]) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Report whether the date of a row is at or after start and before end. A
+// row without the date is kept only when there are no bounds.
+func between(value interface{}, start model.Time, end model.Time) bool {
+
+	if start.IsZero() && end.IsZero() {
+		return true
+	}
+
+	date, err := model.ParseTime(cell(value))
+
+	if err != nil || date.IsZero() {
+		return false
+	}
+
+	return (start.IsZero() || !date.Before(start.Time)) && (end.IsZero() || date.Before(end.Time))
+}
+
+// Text of a JSON value in a table or CSV cell
+func cell(value interface{}) string {
+
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case bool:
+		return strconv.FormatBool(value)
+	}
+
+	contents, _ := json.Marshal(value)
+
+	return string(contents)
+}
+
+func checkFormat(format string) {
+
+	if format != "table" && format != "csv" && format != "json" {
+		fail(2, "Unknown format %q, use table, csv or json.", format)
+	}
+}
+
+// Write the columns of the rows as a table, CSV or JSON. header writes the
+// column names of a table or CSV.
+func writeRows(out io.Writer, format string, columns []string, rows []map[string]interface{}, header bool) error {
+
+	switch format {
+
+	case "json":
+		selected := make([]map[string]interface{}, 0, len(rows))
+
+		for _, row := range rows {
+
+			fields := map[string]interface{}{}
+
+			for _, column := range columns {
+				fields[column] = row[column]
+			}
+
+			selected = append(selected, fields)
+		}
+
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(selected)
+
+	case "csv":
+		writer := csv.NewWriter(out)
+
+		if header {
+			writer.Write(columns)
+		}
+
+		for _, row := range rows {
+
+			record := make([]string, len(columns))
+
+			for i, column := range columns {
+				record[i] = cell(row[column])
+			}
+
+			writer.Write(record)
+		}
+
+		writer.Flush()
+
+		return writer.Error()
+
+	case "table":
+		writer := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+		if header {
+			fmt.Fprintln(writer, strings.Join(columns, "\t"))
+		}
+
+		for _, row := range rows {
+
+			record := make([]string, len(columns))
+
+			for i, column := range columns {
+				record[i] = cell(row[column])
+			}
+
+			fmt.Fprintln(writer, strings.Join(record, "\t"))
+		}
+
+		return writer.Flush()
+	}
+
+	return fmt.Errorf("unknown format %q", format)
+}
+
+// Print an error and exit with the status
+func fail(status int, format string, args ...interface{}) {
+
+	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+
+	os.Exit(status)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	logclient "github.com/Ramcharanpakala/goprojectes/client"
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/migrate"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/server"
+	"github.com/Ramcharanpakala/goprojectes/store/sqlstore"
+)
+
+func TestParseSince(t *testing.T) {
+
+	if at := parseSince("", "-since"); !at.IsZero() {
+		t.Fatalf("empty -since: %s", at)
+	}
+
+	want := time.Now().Add(-24 * time.Hour)
+
+	if at := parseSince("24h", "-since"); at.Sub(want) < -time.Second || at.Sub(want) > time.Minute {
+		t.Fatalf("-since 24h: %s, want about %s", at, want)
+	}
+
+	if at := parseSince("2019-01-15T06:05:40Z", "-until"); !at.Equal(time.Date(2019, 1, 15, 6, 5, 40, 0, time.UTC)) {
+		t.Fatalf("-until 2019-01-15T06:05:40Z: %s", at)
+	}
+}
+
+func TestFilters(t *testing.T) {
+
+	row := map[string]interface{}{"events_type": "trips", "counter": json.Number("2"), "created_date": "2019-01-15T06:05:40Z"}
+
+	for _, test := range []struct {
+		filters map[string]string
+		want    bool
+	}{
+		{map[string]string{}, true},
+		{map[string]string{"events_type": "trips", "counter": "2"}, true},
+		{map[string]string{"events_type": "alarm"}, false},
+		{map[string]string{"program_name": "trips"}, false},
+	} {
+
+		if got := matches(row, test.filters); got != test.want {
+			t.Errorf("matches %v: %v, want %v", test.filters, got, test.want)
+		}
+	}
+
+	at := func(value string) model.Time {
+		t, _ := model.ParseTime(value)
+		return t
+	}
+
+	for _, test := range []struct {
+		value      interface{}
+		start, end string
+		want       bool
+	}{
+		{"2019-01-15T06:05:40Z", "", "", true},
+		{nil, "", "", true},
+		{nil, "2019-01-15T00:00:00Z", "", false},
+		{"2019-01-15T06:05:40Z", "2019-01-15T06:05:40Z", "", true},
+		{"2019-01-15T06:05:40Z", "", "2019-01-15T06:05:40Z", false},
+		{"2019-01-15T06:05:40Z", "2019-01-15T00:00:00Z", "2019-01-16T00:00:00Z", true},
+		{"2019-01-16T06:05:40Z", "2019-01-15T00:00:00Z", "2019-01-16T00:00:00Z", false},
+	} {
+
+		if got := between(test.value, at(test.start), at(test.end)); got != test.want {
+			t.Errorf("between %v, %q, %q: %v, want %v", test.value, test.start, test.end, got, test.want)
+		}
+	}
+}
+
+func TestFilterEventType(t *testing.T) {
+
+	logStore, err := sqlstore.Open(config.DBConfig{DBDriver: "sqlite3", DBPath: filepath.Join(t.TempDir(), "klima_chamber.db")})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer logStore.Close()
+
+	if _, err = migrate.Up(logStore.DB(), string(logStore.Dialect()), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	server.Setup(logStore, config.NGCSLogConfig{AutoCreateTypes: 1})
+
+	router := gin.New()
+
+	server.InitialiseRoutes(router)
+
+	logServer := httptest.NewServer(router)
+
+	defer logServer.Close()
+
+	client := logclient.New(logServer.URL)
+
+	for _, eventType := range []string{"trips", "door", "trips"} {
+
+		event := `{"log_id":"TE001","program_name":"ABC","program_date_time_date":"2019-01-03T04:25:20Z","events_type":"` + eventType + `"}`
+
+		if resp, contents, err := client.Do("POST", "/Logs_Event", []byte(event)); err != nil || resp.StatusCode != http.StatusCreated {
+			t.Fatalf("POST /Logs_Event: %v %s", err, contents)
+		}
+	}
+
+	rows, err := readLogs(client, logTypes["Logs_Event"], model.Time{}, model.Time{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var trips int
+
+	for _, row := range rows {
+
+		if matches(row, map[string]string{"events_type": "trips"}) {
+			trips++
+		}
+	}
+
+	if len(rows) != 3 || trips != 2 {
+		t.Fatalf("%d of %v with events_type trips, want 2 of 3", trips, rows)
+	}
+}
+
+func TestWriteRows(t *testing.T) {
+
+	rows := []map[string]interface{}{
+		{"date_time_date": "2019-01-15T06:05:40Z", "temp_pv": json.Number("5.6"), "channels": map[string]interface{}{"co2": 412.5}},
+		{"date_time_date": "2019-01-15T06:05:50Z", "temp_pv": json.Number("5.8")},
+	}
+
+	columns := []string{"date_time_date", "temp_pv", "channels"}
+
+	for format, want := range map[string]string{
+		"table": "date_time_date        temp_pv  channels\n2019-01-15T06:05:40Z  5.6      {\"co2\":412.5}\n2019-01-15T06:05:50Z  5.8      \n",
+		"csv":   "date_time_date,temp_pv,channels\n2019-01-15T06:05:40Z,5.6,\"{\"\"co2\"\":412.5}\"\n2019-01-15T06:05:50Z,5.8,\n",
+	} {
+
+		var out bytes.Buffer
+
+		if err := writeRows(&out, format, columns, rows, true); err != nil || out.String() != want {
+			t.Errorf("%s:\n%s%v, want\n%s", format, out.String(), err, want)
+		}
+
+		// Without the header, as logs tail after its first poll
+		out.Reset()
+
+		if err := writeRows(&out, format, columns, rows[1:], false); err != nil || strings.Contains(out.String(), "temp_pv") {
+			t.Errorf("%s without header:\n%s%v", format, out.String(), err)
+		}
+	}
+
+	var out bytes.Buffer
+
+	if err := writeRows(&out, "json", columns[:2], rows, true); err != nil {
+		t.Fatal(err)
+	}
+
+	var selected []map[string]interface{}
+
+	if err := json.Unmarshal(out.Bytes(), &selected); err != nil || len(selected) != 2 || len(selected[0]) != 2 || selected[1]["temp_pv"] != 5.8 {
+		t.Fatalf("json: %s %v", out.String(), err)
+	}
+
+	if err := writeRows(&out, "xml", columns, rows, true); err == nil {
+		t.Fatal("unknown format written")
+	}
+}
+
+func TestTailPosition(t *testing.T) {
+
+	sample := func(chamber int, at string) map[string]interface{} {
+		return map[string]interface{}{"ZTK_Chamber_id": json.Number(strconv.Itoa(chamber)), "date_time_date": at}
+	}
+
+	start, _ := model.ParseTime("2019-01-15T06:05:00Z")
+
+	position := tailPosition{last: start}
+
+	for i, poll := range []struct {
+		rows  []map[string]interface{}
+		fresh int
+	}{
+		{[]map[string]interface{}{sample(1, "2019-01-15T06:05:00Z"), sample(1, "2019-01-15T06:05:40Z")}, 2},
+		// Read again from the last sample, with one of chamber 2 stored at
+		// the same second after the first poll
+		{[]map[string]interface{}{sample(1, "2019-01-15T06:05:40Z"), sample(2, "2019-01-15T06:05:40Z")}, 1},
+		{[]map[string]interface{}{sample(1, "2019-01-15T06:05:40Z"), sample(2, "2019-01-15T06:05:40Z")}, 0},
+		{[]map[string]interface{}{sample(1, "2019-01-15T06:05:40Z"), sample(2, "2019-01-15T06:05:40Z"), sample(2, "2019-01-15T06:05:50Z")}, 1},
+		{nil, 0},
+	} {
+
+		if fresh := position.next(poll.rows); len(fresh) != poll.fresh {
+			t.Fatalf("poll %d: printed %v, want %d samples", i, fresh, poll.fresh)
+		}
+	}
+
+	if !position.last.Equal(time.Date(2019, 1, 15, 6, 5, 50, 0, time.UTC)) {
+		t.Fatalf("position %s, want the last sample", position.last)
+	}
+}
+
+func TestReadLogsRange(t *testing.T) {
+
+	var queries []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		w.Write([]byte(`{"status":"OK","data":[{"temp_pv":5.6}]}`))
+	}))
+
+	defer server.Close()
+
+	logServer := logclient.New(server.URL)
+
+	start, _ := model.ParseTime("2019-01-15T06:00:00Z")
+	end, _ := model.ParseTime("2019-01-16T06:00:00Z")
+
+	rows, err := readLogs(logServer, logTypes["Loop_Data"], start, end)
+
+	if err != nil || len(rows) != 1 || rows[0]["temp_pv"] != json.Number("5.6") {
+		t.Fatalf("readLogs: %v %v", rows, err)
+	}
+
+	if _, err = readLogs(logServer, logTypes["Logs_Event"], start, end); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(queries) != 2 || queries[0] != "end=2019-01-16T06%3A00%3A00Z&start=2019-01-15T06%3A00%3A00Z" || queries[1] != "" {
+		t.Fatalf("queries %q", queries)
+	}
+}
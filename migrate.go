@@ -0,0 +1,127 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/migrate"
+	"github.com/Ramcharanpakala/goprojectes/store/sqlstore"
+)
+
+// Bring the schema of the configured DB up or down, e.g.
+//
+//	ngcslog migrate up          apply every pending migration
+//	ngcslog migrate up 3        apply pending migrations up to version 3
+//	ngcslog migrate down [n]    revert the last n migrations (default 1)
+//	ngcslog migrate status      show the applied and pending migrations
+func migrateSchema(args []string) {
+
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ngcslog migrate [flags] up [version] | down [n] | status")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var number int
+
+	if flags.NArg() == 2 {
+
+		var err error
+
+		number, err = strconv.Atoi(flags.Arg(1))
+
+		if err != nil || number < 0 {
+			fmt.Fprintf(os.Stderr, "Error: %q is not a migration number.\n", flags.Arg(1))
+			os.Exit(2)
+		}
+	}
+
+	logStore, ok := openStore(loadConfig(loader, config.Config.ValidateDB).DB).(*sqlstore.Store)
+
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Error: the memory store has no schema to migrate.")
+		os.Exit(1)
+	}
+
+	defer logStore.Close()
+
+	db := logStore.DB()
+	driver := string(logStore.Dialect())
+
+	var migrations []migrate.Migration
+	var err error
+
+	switch flags.Arg(0) {
+
+	case "up":
+		migrations, err = migrate.Up(db, driver, number)
+
+		for _, m := range migrations {
+			fmt.Printf("applied  %04d_%s\n", m.Version, m.Name)
+		}
+
+	case "down":
+		if number == 0 {
+			number = 1
+		}
+
+		migrations, err = migrate.Down(db, driver, number)
+
+		for _, m := range migrations {
+			fmt.Printf("reverted %04d_%s\n", m.Version, m.Name)
+		}
+
+	case "status":
+		err = printMigrationStatus(db, driver)
+
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(1)
+	}
+}
+
+func printMigrationStatus(db *sql.DB, driver string) error {
+
+	migrations, err := migrate.Migrations(driver)
+
+	if err != nil {
+		return err
+	}
+
+	current, err := migrate.Current(db)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("schema version %d\n", current)
+
+	for _, m := range migrations {
+
+		state := "pending"
+
+		if m.Version <= current {
+			state = "applied"
+		}
+
+		fmt.Printf("%-8s %04d_%s\n", state, m.Version, m.Name)
+	}
+
+	return nil
+}
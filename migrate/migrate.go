@@ -0,0 +1,280 @@
+// Package migrate holds the versioned schema of the klima_chamber DB.
+//
+// Every migration is a pair of files NNNN_name.up.sql / NNNN_name.down.sql
+// embedded into the binary, kept in one directory per database/sql driver
+// (mysql, sqlite3) as the dialects differ. The version of the schema a DB is at is kept in
+// ZTK_Schema_Version, one row per applied migration.
+//
+// A migration and its ZTK_Schema_Version row are applied in one transaction.
+// On SQLite, which runs DDL in a transaction, a failed migration leaves the
+// schema as it was. MySQL commits every DDL statement implicitly, so a MySQL
+// migration is not atomic: one that fails halfway keeps the statements run
+// before the failing one while its version is not recorded, and has to be
+// repaired by hand before it is run again.
+package migrate
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed mysql/*.sql sqlite/*.sql
+var files embed.FS
+
+// Struct to hold one migration
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Directory of the migrations of each database/sql driver
+var dialectDirs = map[string]string{
+	"mysql":   "mysql",
+	"sqlite3": "sqlite",
+}
+
+// Every embedded migration of the driver, oldest first
+func Migrations(driver string) ([]Migration, error) {
+
+	dir, ok := dialectDirs[driver]
+
+	if !ok {
+		return nil, fmt.Errorf("no migrations for driver %q", driver)
+	}
+
+	entries, err := files.ReadDir(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+
+	for _, entry := range entries {
+
+		name := entry.Name()
+
+		var direction string
+
+		switch {
+		case strings.HasSuffix(name, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(name, ".down.sql"):
+			direction = "down"
+		default:
+			continue
+		}
+
+		base := strings.TrimSuffix(name, "."+direction+".sql")
+
+		sep := strings.Index(base, "_")
+
+		if sep < 0 {
+			return nil, fmt.Errorf("migration %s: name must be NNNN_name", name)
+		}
+
+		version, err := strconv.Atoi(base[:sep])
+
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %s", name, err.Error())
+		}
+
+		contents, err := files.ReadFile(path.Join(dir, name))
+
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+
+		if !ok {
+			m = &Migration{Version: version, Name: base[sep+1:]}
+			byVersion[version] = m
+		}
+
+		if direction == "up" {
+			m.Up = string(contents)
+		} else {
+			m.Down = string(contents)
+		}
+	}
+
+	var migrations []Migration
+
+	for _, m := range byVersion {
+
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s: needs both up and down", m.Version, m.Name)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Version the DB schema is at, 0 for an empty DB. Creates ZTK_Schema_Version
+// when it does not exist yet.
+func Current(db *sql.DB) (int, error) {
+
+	var version int
+
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS ZTK_Schema_Version (version INT NOT NULL, name VARCHAR(100) NOT NULL, applied DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP, PRIMARY KEY (version))")
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = db.QueryRow("select coalesce(max(version),0) from ZTK_Schema_Version").Scan(&version)
+
+	return version, err
+}
+
+// Apply every migration after the current version up to and including
+// target, or up to the latest one when target is 0. Returns the migrations
+// applied.
+func Up(db *sql.DB, driver string, target int) ([]Migration, error) {
+
+	var applied []Migration
+
+	migrations, err := Migrations(driver)
+
+	if err != nil {
+		return applied, err
+	}
+
+	current, err := Current(db)
+
+	if err != nil {
+		return applied, err
+	}
+
+	for _, m := range migrations {
+
+		if m.Version <= current || (target != 0 && m.Version > target) {
+			continue
+		}
+
+		err = apply(db, m.Up, "insert into ZTK_Schema_Version (version,name) values(?,?)", m.Version, m.Name)
+
+		if err != nil {
+			return applied, fmt.Errorf("migration %04d_%s up: %s", m.Version, m.Name, err.Error())
+		}
+
+		applied = append(applied, m)
+	}
+
+	return applied, nil
+}
+
+// Revert the last steps applied migrations. Returns the migrations reverted.
+func Down(db *sql.DB, driver string, steps int) ([]Migration, error) {
+
+	var reverted []Migration
+
+	migrations, err := Migrations(driver)
+
+	if err != nil {
+		return reverted, err
+	}
+
+	current, err := Current(db)
+
+	if err != nil {
+		return reverted, err
+	}
+
+	for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+
+		m := migrations[i]
+
+		if m.Version > current {
+			continue
+		}
+
+		err = apply(db, m.Down, "delete from ZTK_Schema_Version where version = ?", m.Version)
+
+		if err != nil {
+			return reverted, fmt.Errorf("migration %04d_%s down: %s", m.Version, m.Name, err.Error())
+		}
+
+		reverted = append(reverted, m)
+	}
+
+	return reverted, nil
+}
+
+// Run a migration script and the statement recording its version in one
+// transaction, see the package comment for MySQL
+func apply(db *sql.DB, script string, record string, args ...interface{}) error {
+
+	tx, err := db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = execScript(tx, script)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(record, args...)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Run the statements of a migration file one by one. Statements end with a
+// ";" at the end of a line; lines starting with "--" are comments.
+func execScript(tx *sql.Tx, script string) error {
+
+	var statement strings.Builder
+
+	for _, line := range strings.Split(script, "\n") {
+
+		trimmed := strings.TrimSpace(line)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		statement.WriteString(line)
+		statement.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+
+			_, err := tx.Exec(statement.String())
+
+			if err != nil {
+				return err
+			}
+
+			statement.Reset()
+		}
+	}
+
+	if strings.TrimSpace(statement.String()) != "" {
+		return fmt.Errorf("statement not terminated by \";\"")
+	}
+
+	return nil
+}
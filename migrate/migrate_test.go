@@ -0,0 +1,148 @@
+package migrate
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Open an empty SQLite DB in a temporary file
+func openTestDB(t *testing.T) *sql.DB {
+
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "klima_chamber.db"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db.SetMaxOpenConns(1)
+
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func TestUpDownSQLite(t *testing.T) {
+
+	db := openTestDB(t)
+
+	migrations, err := Migrations("sqlite3")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range migrations {
+
+		applied, err := Up(db, "sqlite3", m.Version)
+
+		if err != nil || len(applied) != 1 {
+			t.Fatalf("Up to %04d_%s: %d applied, %v", m.Version, m.Name, len(applied), err)
+		}
+
+		if current, err := Current(db); err != nil || current != m.Version {
+			t.Fatalf("Current after %04d_%s up: %d %v", m.Version, m.Name, current, err)
+		}
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+
+		m := migrations[i]
+
+		reverted, err := Down(db, "sqlite3", 1)
+
+		if err != nil || len(reverted) != 1 || reverted[0].Version != m.Version {
+			t.Fatalf("Down from %04d_%s: %+v %v", m.Version, m.Name, reverted, err)
+		}
+
+		want := 0
+
+		if i > 0 {
+			want = migrations[i-1].Version
+		}
+
+		if current, err := Current(db); err != nil || current != want {
+			t.Fatalf("Current after %04d_%s down: %d %v, want %d", m.Version, m.Name, current, err, want)
+		}
+	}
+
+	// Down to an empty DB, and up again
+	if applied, err := Up(db, "sqlite3", 0); err != nil || len(applied) != len(migrations) {
+		t.Fatalf("Up again: %d applied, %v", len(applied), err)
+	}
+}
+
+func TestApplyFailedSQLite(t *testing.T) {
+
+	db := openTestDB(t)
+
+	if _, err := Current(db); err != nil {
+		t.Fatal(err)
+	}
+
+	err := apply(db, "CREATE TABLE ZTK_Partial (id INT);\nINSERT INTO ZTK_Missing VALUES (1);\n", "insert into ZTK_Schema_Version (version,name) values(?,?)", 1, "partial")
+
+	if err == nil {
+		t.Fatal("apply of a failing script succeeded")
+	}
+
+	if current, err := Current(db); err != nil || current != 0 {
+		t.Fatalf("Current after a failed migration: %d %v", current, err)
+	}
+
+	var tables int
+
+	db.QueryRow("select count(*) from sqlite_master where name = 'ZTK_Partial'").Scan(&tables)
+
+	if tables != 0 {
+		t.Fatal("statement of a failed migration kept")
+	}
+}
+
+func TestTypeDuplicatesSQLite(t *testing.T) {
+
+	db := openTestDB(t)
+
+	if _, err := Up(db, "sqlite3", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// A legacy DB adopted by 0001 with types stored more than once
+	for _, statement := range []string{
+		"insert into ZTK_Logs_Event_Type (events_type) values ('trips'),('Trips'),('door'),('trips')",
+		"insert into ZTK_Logs_Event (log_id,program_name,ZTK_Logs_Event_Type_id) values ('TE001','ABC',2),('TE002','ABC',3),('TE003','ABC',4)",
+		"insert into ZTK_Logs_Test_Type (test_type) values ('soak'),('SOAK')",
+		"insert into ZTK_Logs_Test (log_id,log_name,ZTK_Logs_Test_Type_id) values ('TE001','DEF',2)",
+	} {
+
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := Up(db, "sqlite3", 2); err != nil {
+		t.Fatalf("Up to 0002 with duplicate types: %v", err)
+	}
+
+	for query, want := range map[string]string{
+		"select group_concat(id || events_type) from (select * from ZTK_Logs_Event_Type order by id)": "1trips,3door",
+		"select group_concat(ZTK_Logs_Event_Type_id) from (select * from ZTK_Logs_Event order by id)": "1,3,1",
+		"select group_concat(id || test_type) from (select * from ZTK_Logs_Test_Type order by id)":    "1soak",
+		"select group_concat(ZTK_Logs_Test_Type_id) from (select * from ZTK_Logs_Test order by id)":   "1",
+	} {
+
+		var got string
+
+		if err := db.QueryRow(query).Scan(&got); err != nil || got != want {
+			t.Errorf("%s: %q %v, want %q", query, got, err, want)
+		}
+	}
+
+	if _, err := db.Exec("insert into ZTK_Logs_Event_Type (events_type) values ('DOOR')"); err == nil {
+		t.Fatal("type name differing in case only stored")
+	}
+}
@@ -0,0 +1,108 @@
+-- Tables of the klima_chamber DB as used by the NGCS Local Log Server up to
+-- rev 1.3. IF NOT EXISTS lets an existing chamber DB be adopted as is.
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Event_Type (
+    id              INT          NOT NULL AUTO_INCREMENT,
+    events_type     VARCHAR(100) NOT NULL,
+    created_by      INT          NOT NULL DEFAULT 0,
+    modified_by     INT          NOT NULL DEFAULT 0,
+    created         DATETIME     NULL,
+    modified        DATETIME     NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Event (
+    id                      INT          NOT NULL AUTO_INCREMENT,
+    log_id                  VARCHAR(50)  NOT NULL,
+    program_name            VARCHAR(100) NOT NULL,
+    program_date_time       DATETIME     NULL,
+    ZTK_Logs_Event_Type_id  INT          NOT NULL,
+    ZTK_Users_id            INT          NOT NULL DEFAULT 0,
+    created_by              INT          NOT NULL DEFAULT 0,
+    created                 DATETIME     NULL,
+    modified_by             INT          NOT NULL DEFAULT 0,
+    modified                DATETIME     NULL,
+    PRIMARY KEY (id),
+    KEY ZTK_Logs_Event_log_id (log_id),
+    KEY ZTK_Logs_Event_type (ZTK_Logs_Event_Type_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Test_Type (
+    id              INT          NOT NULL AUTO_INCREMENT,
+    test_type       VARCHAR(100) NOT NULL,
+    created         DATETIME     NULL,
+    modified        DATETIME     NULL,
+    created_by      INT          NOT NULL DEFAULT 0,
+    modified_by     INT          NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Test (
+    id                      INT          NOT NULL AUTO_INCREMENT,
+    log_id                  VARCHAR(50)  NOT NULL,
+    log_name                VARCHAR(100) NOT NULL,
+    log_date_time           DATETIME     NULL,
+    ZTK_Logs_Test_Type_id   INT          NOT NULL,
+    ZTK_Users_id            INT          NOT NULL DEFAULT 0,
+    created_by              INT          NOT NULL DEFAULT 0,
+    created                 DATETIME     NULL,
+    modified_by             INT          NOT NULL DEFAULT 0,
+    modified                DATETIME     NULL,
+    PRIMARY KEY (id),
+    KEY ZTK_Logs_Test_log_id (log_id),
+    KEY ZTK_Logs_Test_type (ZTK_Logs_Test_Type_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Maintenance (
+    id                      INT          NOT NULL AUTO_INCREMENT,
+    component_name          VARCHAR(100) NOT NULL,
+    runtime_hr              INT          NOT NULL DEFAULT 0,
+    counter                 INT          NOT NULL DEFAULT 0,
+    days_till_service       INT          NOT NULL DEFAULT 0,
+    maintenance_pending     INT          NOT NULL DEFAULT 0,
+    maintenance_status      INT          NOT NULL DEFAULT 0,
+    created                 DATETIME     NULL,
+    modified                DATETIME     NULL,
+    created_by              INT          NOT NULL DEFAULT 0,
+    modified_by             INT          NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS ZTK_Loop_Data (
+    id              INT          NOT NULL AUTO_INCREMENT,
+    temp_sp         DOUBLE       NOT NULL DEFAULT 0,
+    temp_pv         DOUBLE       NOT NULL DEFAULT 0,
+    hum_sp          DOUBLE       NOT NULL DEFAULT 0,
+    hum_pv          DOUBLE       NOT NULL DEFAULT 0,
+    press_sp        DOUBLE       NOT NULL DEFAULT 0,
+    press_pv        DOUBLE       NOT NULL DEFAULT 0,
+    date_time       DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    KEY ZTK_Loop_Data_date_time (date_time)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS ZTK_IO_Card_Info (
+    id                      INT          NOT NULL AUTO_INCREMENT,
+    card_address            VARCHAR(32)  NOT NULL,
+    card_type               VARCHAR(32)  NOT NULL,
+    card_version            VARCHAR(32)  NOT NULL,
+    card_serial_number      VARCHAR(64)  NOT NULL,
+    secret_key              VARCHAR(64)  NOT NULL,
+    customer_id             INT          NOT NULL DEFAULT 0,
+    mfg_date                DATETIME     NULL,
+    created                 DATETIME     NULL,
+    modified                DATETIME     NULL,
+    created_by              INT          NOT NULL DEFAULT 0,
+    modified_by             INT          NOT NULL DEFAULT 0,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS ZTK_Activity_Log (
+    id              INT          NOT NULL AUTO_INCREMENT,
+    ZTK_Table_Id    INT          NOT NULL,
+    action_type     VARCHAR(20)  NOT NULL,
+    new_value       TEXT         NOT NULL,
+    ZTK_Users_Id    INT          NOT NULL DEFAULT 0,
+    created         DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,7 @@
+ALTER TABLE ZTK_Logs_Test_Type
+    DROP INDEX ZTK_Logs_Test_Type_name,
+    DROP COLUMN active;
+
+ALTER TABLE ZTK_Logs_Event_Type
+    DROP INDEX ZTK_Logs_Event_Type_name,
+    DROP COLUMN active;
@@ -0,0 +1,30 @@
+-- Event and test types can be retired and their names are unique.
+
+-- Of the types of one name stored more than once before, compared without
+-- case like the unique key, the first is kept and the logs of the others
+-- point to it. The down migration does not split them again.
+UPDATE ZTK_Logs_Event e
+    JOIN ZTK_Logs_Event_Type t ON t.id = e.ZTK_Logs_Event_Type_id
+    JOIN (SELECT events_type, MIN(id) AS id FROM ZTK_Logs_Event_Type GROUP BY events_type) kept ON kept.events_type = t.events_type
+    SET e.ZTK_Logs_Event_Type_id = kept.id
+    WHERE kept.id <> t.id;
+
+DELETE t FROM ZTK_Logs_Event_Type t
+    JOIN ZTK_Logs_Event_Type kept ON kept.events_type = t.events_type AND kept.id < t.id;
+
+UPDATE ZTK_Logs_Test l
+    JOIN ZTK_Logs_Test_Type t ON t.id = l.ZTK_Logs_Test_Type_id
+    JOIN (SELECT test_type, MIN(id) AS id FROM ZTK_Logs_Test_Type GROUP BY test_type) kept ON kept.test_type = t.test_type
+    SET l.ZTK_Logs_Test_Type_id = kept.id
+    WHERE kept.id <> t.id;
+
+DELETE t FROM ZTK_Logs_Test_Type t
+    JOIN ZTK_Logs_Test_Type kept ON kept.test_type = t.test_type AND kept.id < t.id;
+
+ALTER TABLE ZTK_Logs_Event_Type
+    ADD COLUMN active TINYINT NOT NULL DEFAULT 1,
+    ADD UNIQUE KEY ZTK_Logs_Event_Type_name (events_type);
+
+ALTER TABLE ZTK_Logs_Test_Type
+    ADD COLUMN active TINYINT NOT NULL DEFAULT 1,
+    ADD UNIQUE KEY ZTK_Logs_Test_Type_name (test_type);
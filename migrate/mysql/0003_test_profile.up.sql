@@ -0,0 +1,30 @@
+-- Versioned multi-step profiles of a test type.
+
+CREATE TABLE ZTK_Logs_Test_Profile (
+    id                      INT          NOT NULL AUTO_INCREMENT,
+    ZTK_Logs_Test_Type_id   INT          NOT NULL,
+    version                 INT          NOT NULL,
+    comment                 VARCHAR(255) NOT NULL DEFAULT '',
+    created_by              INT          NOT NULL DEFAULT 0,
+    created                 DATETIME     NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY ZTK_Logs_Test_Profile_version (ZTK_Logs_Test_Type_id, version),
+    CONSTRAINT ZTK_Logs_Test_Profile_type FOREIGN KEY (ZTK_Logs_Test_Type_id) REFERENCES ZTK_Logs_Test_Type (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE ZTK_Logs_Test_Profile_Step (
+    id                          INT          NOT NULL AUTO_INCREMENT,
+    ZTK_Logs_Test_Profile_id    INT          NOT NULL,
+    step_no                     INT          NOT NULL,
+    step_type                   VARCHAR(10)  NOT NULL,
+    temp_sp                     DOUBLE       NOT NULL DEFAULT 0,
+    hum_sp                      DOUBLE       NOT NULL DEFAULT 0,
+    press_sp                    DOUBLE       NOT NULL DEFAULT 0,
+    duration_min                INT          NOT NULL,
+    temp_tol                    DOUBLE       NOT NULL DEFAULT 0,
+    hum_tol                     DOUBLE       NOT NULL DEFAULT 0,
+    press_tol                   DOUBLE       NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+    UNIQUE KEY ZTK_Logs_Test_Profile_Step_no (ZTK_Logs_Test_Profile_id, step_no),
+    CONSTRAINT ZTK_Logs_Test_Profile_Step_profile FOREIGN KEY (ZTK_Logs_Test_Profile_id) REFERENCES ZTK_Logs_Test_Profile (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
@@ -0,0 +1,4 @@
+ALTER TABLE ZTK_Logs_Test
+    DROP COLUMN ZTK_Logs_Test_Profile_id,
+    DROP COLUMN verdict_detail,
+    DROP COLUMN verdict;
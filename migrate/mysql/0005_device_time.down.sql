@@ -0,0 +1,23 @@
+ALTER TABLE ZTK_Logs_Event_Type
+    DROP COLUMN device_modified,
+    DROP COLUMN device_created;
+
+ALTER TABLE ZTK_Logs_Event
+    DROP COLUMN device_modified,
+    DROP COLUMN device_created;
+
+ALTER TABLE ZTK_Logs_Test_Type
+    DROP COLUMN device_modified,
+    DROP COLUMN device_created;
+
+ALTER TABLE ZTK_Logs_Test
+    DROP COLUMN device_modified,
+    DROP COLUMN device_created;
+
+ALTER TABLE ZTK_Logs_Maintenance
+    DROP COLUMN device_modified,
+    DROP COLUMN device_created;
+
+ALTER TABLE ZTK_IO_Card_Info
+    DROP COLUMN device_modified,
+    DROP COLUMN device_created;
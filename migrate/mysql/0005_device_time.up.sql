@@ -0,0 +1,26 @@
+-- Dates the devices sent for a record, kept apart from the created and
+-- modified dates stamped by the server.
+
+ALTER TABLE ZTK_Logs_Event_Type
+    ADD COLUMN device_created DATETIME NULL,
+    ADD COLUMN device_modified DATETIME NULL;
+
+ALTER TABLE ZTK_Logs_Event
+    ADD COLUMN device_created DATETIME NULL,
+    ADD COLUMN device_modified DATETIME NULL;
+
+ALTER TABLE ZTK_Logs_Test_Type
+    ADD COLUMN device_created DATETIME NULL,
+    ADD COLUMN device_modified DATETIME NULL;
+
+ALTER TABLE ZTK_Logs_Test
+    ADD COLUMN device_created DATETIME NULL,
+    ADD COLUMN device_modified DATETIME NULL;
+
+ALTER TABLE ZTK_Logs_Maintenance
+    ADD COLUMN device_created DATETIME NULL,
+    ADD COLUMN device_modified DATETIME NULL;
+
+ALTER TABLE ZTK_IO_Card_Info
+    ADD COLUMN device_created DATETIME NULL,
+    ADD COLUMN device_modified DATETIME NULL;
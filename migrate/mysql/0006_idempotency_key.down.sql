@@ -0,0 +1 @@
+DROP TABLE IF EXISTS ZTK_Idempotency_Key;
@@ -0,0 +1,14 @@
+-- Responses to requests sent with an Idempotency-Key, replayed to retries.
+
+CREATE TABLE ZTK_Idempotency_Key (
+    user_id         INT           NOT NULL,
+    idempotency_key VARCHAR(255)  NOT NULL,
+    method          VARCHAR(10)   NOT NULL,
+    path            VARCHAR(255)  NOT NULL,
+    request_hash    CHAR(64)      NOT NULL,
+    status_code     INT           NOT NULL,
+    response        MEDIUMBLOB    NOT NULL,
+    created         DATETIME      NOT NULL,
+    PRIMARY KEY (user_id, idempotency_key),
+    KEY ZTK_Idempotency_Key_created (created)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
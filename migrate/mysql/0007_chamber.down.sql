@@ -0,0 +1,19 @@
+ALTER TABLE ZTK_Loop_Data
+    DROP KEY ZTK_Loop_Data_chamber_date_time,
+    DROP COLUMN ZTK_Chamber_id;
+
+ALTER TABLE ZTK_Logs_Maintenance
+    DROP KEY ZTK_Logs_Maintenance_chamber,
+    DROP COLUMN ZTK_Chamber_id;
+
+ALTER TABLE ZTK_Logs_Test
+    DROP KEY ZTK_Logs_Test_chamber,
+    DROP COLUMN ZTK_Chamber_id;
+
+ALTER TABLE ZTK_Logs_Event
+    DROP KEY ZTK_Logs_Event_chamber,
+    DROP COLUMN ZTK_Chamber_id;
+
+DROP TABLE IF EXISTS ZTK_Chamber_IO_Card;
+
+DROP TABLE IF EXISTS ZTK_Chamber;
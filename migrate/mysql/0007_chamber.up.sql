@@ -0,0 +1,42 @@
+-- Registry of the chambers logging to the server and the IO cards fitted to
+-- them. Records keep ZTK_Chamber_id 0 until a chamber is given.
+
+CREATE TABLE ZTK_Chamber (
+    id              INT          NOT NULL AUTO_INCREMENT,
+    name            VARCHAR(100) NOT NULL,
+    location        VARCHAR(255) NOT NULL DEFAULT '',
+    created_by      INT          NOT NULL DEFAULT 0,
+    modified_by     INT          NOT NULL DEFAULT 0,
+    created         DATETIME     NULL,
+    modified        DATETIME     NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY ZTK_Chamber_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE ZTK_Chamber_IO_Card (
+    card_serial_number  VARCHAR(64)  NOT NULL,
+    ZTK_Chamber_id      INT          NOT NULL,
+    PRIMARY KEY (card_serial_number),
+    CONSTRAINT ZTK_Chamber_IO_Card_chamber FOREIGN KEY (ZTK_Chamber_id) REFERENCES ZTK_Chamber (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE ZTK_Logs_Event
+    ADD COLUMN ZTK_Chamber_id INT NOT NULL DEFAULT 0,
+    ADD KEY ZTK_Logs_Event_chamber (ZTK_Chamber_id);
+
+ALTER TABLE ZTK_Logs_Test
+    ADD COLUMN ZTK_Chamber_id INT NOT NULL DEFAULT 0,
+    ADD KEY ZTK_Logs_Test_chamber (ZTK_Chamber_id);
+
+ALTER TABLE ZTK_Logs_Maintenance
+    ADD COLUMN ZTK_Chamber_id INT NOT NULL DEFAULT 0,
+    ADD KEY ZTK_Logs_Maintenance_chamber (ZTK_Chamber_id);
+
+-- A chamber has one sample per date time; of samples sent twice before, the
+-- last one is kept.
+DELETE d FROM ZTK_Loop_Data d
+    JOIN ZTK_Loop_Data newer ON newer.date_time = d.date_time AND newer.id > d.id;
+
+ALTER TABLE ZTK_Loop_Data
+    ADD COLUMN ZTK_Chamber_id INT NOT NULL DEFAULT 0,
+    ADD UNIQUE KEY ZTK_Loop_Data_chamber_date_time (ZTK_Chamber_id, date_time);
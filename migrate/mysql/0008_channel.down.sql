@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS ZTK_Loop_Data_Channel;
+
+DROP TABLE IF EXISTS ZTK_Channel;
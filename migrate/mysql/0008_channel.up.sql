@@ -0,0 +1,28 @@
+-- Catalogue of the Loop_Data channels beyond the six columns and the values
+-- of those channels in each sample.
+
+CREATE TABLE ZTK_Channel (
+    id              INT          NOT NULL AUTO_INCREMENT,
+    name            VARCHAR(100) NOT NULL,
+    unit            VARCHAR(32)  NOT NULL DEFAULT '',
+    minimum         DOUBLE       NULL,
+    maximum         DOUBLE       NULL,
+    description     VARCHAR(255) NOT NULL DEFAULT '',
+    active          TINYINT      NOT NULL DEFAULT 1,
+    created_by      INT          NOT NULL DEFAULT 0,
+    modified_by     INT          NOT NULL DEFAULT 0,
+    created         DATETIME     NULL,
+    modified        DATETIME     NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY ZTK_Channel_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE ZTK_Loop_Data_Channel (
+    ZTK_Loop_Data_id    INT          NOT NULL,
+    ZTK_Channel_id      INT          NOT NULL,
+    value               DOUBLE       NOT NULL,
+    PRIMARY KEY (ZTK_Loop_Data_id, ZTK_Channel_id),
+    KEY ZTK_Loop_Data_Channel_channel (ZTK_Channel_id),
+    CONSTRAINT ZTK_Loop_Data_Channel_sample FOREIGN KEY (ZTK_Loop_Data_id) REFERENCES ZTK_Loop_Data (id) ON DELETE CASCADE,
+    CONSTRAINT ZTK_Loop_Data_Channel_channel FOREIGN KEY (ZTK_Channel_id) REFERENCES ZTK_Channel (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
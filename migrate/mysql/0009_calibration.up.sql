@@ -0,0 +1,29 @@
+-- Calibrations of the sensors of the chambers, and the raw value and
+-- calibration of every Loop_Data value corrected on ingest.
+
+CREATE TABLE ZTK_Calibration (
+    id                  INT          NOT NULL AUTO_INCREMENT,
+    ZTK_Chamber_id      INT          NOT NULL DEFAULT 0,
+    channel             VARCHAR(100) NOT NULL,
+    value_offset        DOUBLE       NOT NULL DEFAULT 0,
+    gain                DOUBLE       NOT NULL DEFAULT 1,
+    calibration_date    DATETIME     NOT NULL,
+    due_date            DATETIME     NOT NULL,
+    certificate         VARCHAR(100) NOT NULL DEFAULT '',
+    created_by          INT          NOT NULL DEFAULT 0,
+    modified_by         INT          NOT NULL DEFAULT 0,
+    created             DATETIME     NULL,
+    modified            DATETIME     NULL,
+    PRIMARY KEY (id),
+    KEY ZTK_Calibration_sensor (ZTK_Chamber_id, channel, calibration_date)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE ZTK_Loop_Data_Calibration (
+    ZTK_Loop_Data_id    INT          NOT NULL,
+    channel             VARCHAR(100) NOT NULL,
+    raw_value           DOUBLE       NOT NULL,
+    ZTK_Calibration_id  INT          NOT NULL,
+    PRIMARY KEY (ZTK_Loop_Data_id, channel),
+    CONSTRAINT ZTK_Loop_Data_Calibration_sample FOREIGN KEY (ZTK_Loop_Data_id) REFERENCES ZTK_Loop_Data (id) ON DELETE CASCADE,
+    CONSTRAINT ZTK_Loop_Data_Calibration_calibration FOREIGN KEY (ZTK_Calibration_id) REFERENCES ZTK_Calibration (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
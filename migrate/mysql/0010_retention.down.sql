@@ -0,0 +1,4 @@
+ALTER TABLE ZTK_Activity_Log
+    DROP KEY ZTK_Activity_Log_created;
+
+DROP TABLE IF EXISTS ZTK_Loop_Data_Hourly;
@@ -0,0 +1,25 @@
+-- Hourly roll-ups of the Loop_Data pruned by the retention policy, kept
+-- forever, and the index the Activity Log is pruned by.
+
+CREATE TABLE ZTK_Loop_Data_Hourly (
+    ZTK_Chamber_id  INT          NOT NULL DEFAULT 0,
+    hour            DATETIME     NOT NULL,
+    samples         INT          NOT NULL,
+    temp_sp         DOUBLE       NOT NULL,
+    temp_pv         DOUBLE       NOT NULL,
+    temp_pv_min     DOUBLE       NOT NULL,
+    temp_pv_max     DOUBLE       NOT NULL,
+    hum_sp          DOUBLE       NOT NULL,
+    hum_pv          DOUBLE       NOT NULL,
+    hum_pv_min      DOUBLE       NOT NULL,
+    hum_pv_max      DOUBLE       NOT NULL,
+    press_sp        DOUBLE       NOT NULL,
+    press_pv        DOUBLE       NOT NULL,
+    press_pv_min    DOUBLE       NOT NULL,
+    press_pv_max    DOUBLE       NOT NULL,
+    PRIMARY KEY (ZTK_Chamber_id, hour),
+    KEY ZTK_Loop_Data_Hourly_hour (hour)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+ALTER TABLE ZTK_Activity_Log
+    ADD KEY ZTK_Activity_Log_created (created);
@@ -0,0 +1,2 @@
+ALTER TABLE ZTK_Chamber
+    DROP COLUMN time_zone;
@@ -0,0 +1,5 @@
+-- Zone of the chamber's controller, for the date times it sends without one.
+-- '' takes the TimeZone of the log server.
+
+ALTER TABLE ZTK_Chamber
+    ADD COLUMN time_zone VARCHAR(64) NOT NULL DEFAULT '';
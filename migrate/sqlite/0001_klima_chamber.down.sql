@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS ZTK_Activity_Log;
+DROP TABLE IF EXISTS ZTK_IO_Card_Info;
+DROP TABLE IF EXISTS ZTK_Loop_Data;
+DROP TABLE IF EXISTS ZTK_Logs_Maintenance;
+DROP TABLE IF EXISTS ZTK_Logs_Test;
+DROP TABLE IF EXISTS ZTK_Logs_Test_Type;
+DROP TABLE IF EXISTS ZTK_Logs_Event;
+DROP TABLE IF EXISTS ZTK_Logs_Event_Type;
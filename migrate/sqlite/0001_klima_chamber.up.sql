@@ -0,0 +1,107 @@
+-- Tables of the klima_chamber DB for a standalone log server. Date and time
+-- columns hold TEXT in the "2006-01-02 15:04:05" layout. Index names differ
+-- from MySQL where they would clash with a table name, as SQLite compares
+-- them without case.
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Event_Type (
+    id              INTEGER      PRIMARY KEY AUTOINCREMENT,
+    events_type     VARCHAR(100) NOT NULL,
+    created_by      INT          NOT NULL DEFAULT 0,
+    modified_by     INT          NOT NULL DEFAULT 0,
+    created         TEXT         NULL,
+    modified        TEXT         NULL
+);
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Event (
+    id                      INTEGER      PRIMARY KEY AUTOINCREMENT,
+    log_id                  VARCHAR(50)  NOT NULL,
+    program_name            VARCHAR(100) NOT NULL,
+    program_date_time       TEXT         NULL,
+    ZTK_Logs_Event_Type_id  INT          NOT NULL,
+    ZTK_Users_id            INT          NOT NULL DEFAULT 0,
+    created_by              INT          NOT NULL DEFAULT 0,
+    created                 TEXT         NULL,
+    modified_by             INT          NOT NULL DEFAULT 0,
+    modified                TEXT         NULL
+);
+
+CREATE INDEX IF NOT EXISTS ZTK_Logs_Event_log_id ON ZTK_Logs_Event (log_id);
+
+CREATE INDEX IF NOT EXISTS ZTK_Logs_Event_by_type ON ZTK_Logs_Event (ZTK_Logs_Event_Type_id);
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Test_Type (
+    id              INTEGER      PRIMARY KEY AUTOINCREMENT,
+    test_type       VARCHAR(100) NOT NULL,
+    created         TEXT         NULL,
+    modified        TEXT         NULL,
+    created_by      INT          NOT NULL DEFAULT 0,
+    modified_by     INT          NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Test (
+    id                      INTEGER      PRIMARY KEY AUTOINCREMENT,
+    log_id                  VARCHAR(50)  NOT NULL,
+    log_name                VARCHAR(100) NOT NULL,
+    log_date_time           TEXT         NULL,
+    ZTK_Logs_Test_Type_id   INT          NOT NULL,
+    ZTK_Users_id            INT          NOT NULL DEFAULT 0,
+    created_by              INT          NOT NULL DEFAULT 0,
+    created                 TEXT         NULL,
+    modified_by             INT          NOT NULL DEFAULT 0,
+    modified                TEXT         NULL
+);
+
+CREATE INDEX IF NOT EXISTS ZTK_Logs_Test_log_id ON ZTK_Logs_Test (log_id);
+
+CREATE INDEX IF NOT EXISTS ZTK_Logs_Test_by_type ON ZTK_Logs_Test (ZTK_Logs_Test_Type_id);
+
+CREATE TABLE IF NOT EXISTS ZTK_Logs_Maintenance (
+    id                      INTEGER      PRIMARY KEY AUTOINCREMENT,
+    component_name          VARCHAR(100) NOT NULL,
+    runtime_hr              INT          NOT NULL DEFAULT 0,
+    counter                 INT          NOT NULL DEFAULT 0,
+    days_till_service       INT          NOT NULL DEFAULT 0,
+    maintenance_pending     INT          NOT NULL DEFAULT 0,
+    maintenance_status      INT          NOT NULL DEFAULT 0,
+    created                 TEXT         NULL,
+    modified                TEXT         NULL,
+    created_by              INT          NOT NULL DEFAULT 0,
+    modified_by             INT          NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS ZTK_Loop_Data (
+    id              INTEGER      PRIMARY KEY AUTOINCREMENT,
+    temp_sp         DOUBLE       NOT NULL DEFAULT 0,
+    temp_pv         DOUBLE       NOT NULL DEFAULT 0,
+    hum_sp          DOUBLE       NOT NULL DEFAULT 0,
+    hum_pv          DOUBLE       NOT NULL DEFAULT 0,
+    press_sp        DOUBLE       NOT NULL DEFAULT 0,
+    press_pv        DOUBLE       NOT NULL DEFAULT 0,
+    date_time       TEXT         NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS ZTK_Loop_Data_date_time ON ZTK_Loop_Data (date_time);
+
+CREATE TABLE IF NOT EXISTS ZTK_IO_Card_Info (
+    id                      INTEGER      PRIMARY KEY AUTOINCREMENT,
+    card_address            VARCHAR(32)  NOT NULL,
+    card_type               VARCHAR(32)  NOT NULL,
+    card_version            VARCHAR(32)  NOT NULL,
+    card_serial_number      VARCHAR(64)  NOT NULL,
+    secret_key              VARCHAR(64)  NOT NULL,
+    customer_id             INT          NOT NULL DEFAULT 0,
+    mfg_date                TEXT         NULL,
+    created                 TEXT         NULL,
+    modified                TEXT         NULL,
+    created_by              INT          NOT NULL DEFAULT 0,
+    modified_by             INT          NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS ZTK_Activity_Log (
+    id              INTEGER      PRIMARY KEY AUTOINCREMENT,
+    ZTK_Table_Id    INT          NOT NULL,
+    action_type     VARCHAR(20)  NOT NULL,
+    new_value       TEXT         NOT NULL,
+    ZTK_Users_Id    INT          NOT NULL DEFAULT 0,
+    created         TEXT         NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+DROP INDEX ZTK_Logs_Test_Type_name;
+
+ALTER TABLE ZTK_Logs_Test_Type DROP COLUMN active;
+
+DROP INDEX ZTK_Logs_Event_Type_name;
+
+ALTER TABLE ZTK_Logs_Event_Type DROP COLUMN active;
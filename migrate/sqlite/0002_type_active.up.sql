@@ -0,0 +1,37 @@
+-- Event and test types can be retired and their names are unique, compared
+-- without case as in MySQL.
+
+-- Of the types of one name stored more than once before, the first is kept
+-- and the logs of the others point to it. The down migration does not split
+-- them again.
+UPDATE ZTK_Logs_Event
+    SET ZTK_Logs_Event_Type_id = (
+        SELECT MIN(kept.id) FROM ZTK_Logs_Event_Type t
+            JOIN ZTK_Logs_Event_Type kept ON kept.events_type = t.events_type COLLATE NOCASE
+            WHERE t.id = ZTK_Logs_Event.ZTK_Logs_Event_Type_id)
+    WHERE ZTK_Logs_Event_Type_id IN (
+        SELECT t.id FROM ZTK_Logs_Event_Type t
+            JOIN ZTK_Logs_Event_Type kept ON kept.events_type = t.events_type COLLATE NOCASE AND kept.id < t.id);
+
+DELETE FROM ZTK_Logs_Event_Type
+    WHERE EXISTS (SELECT 1 FROM ZTK_Logs_Event_Type kept WHERE kept.events_type = ZTK_Logs_Event_Type.events_type COLLATE NOCASE AND kept.id < ZTK_Logs_Event_Type.id);
+
+UPDATE ZTK_Logs_Test
+    SET ZTK_Logs_Test_Type_id = (
+        SELECT MIN(kept.id) FROM ZTK_Logs_Test_Type t
+            JOIN ZTK_Logs_Test_Type kept ON kept.test_type = t.test_type COLLATE NOCASE
+            WHERE t.id = ZTK_Logs_Test.ZTK_Logs_Test_Type_id)
+    WHERE ZTK_Logs_Test_Type_id IN (
+        SELECT t.id FROM ZTK_Logs_Test_Type t
+            JOIN ZTK_Logs_Test_Type kept ON kept.test_type = t.test_type COLLATE NOCASE AND kept.id < t.id);
+
+DELETE FROM ZTK_Logs_Test_Type
+    WHERE EXISTS (SELECT 1 FROM ZTK_Logs_Test_Type kept WHERE kept.test_type = ZTK_Logs_Test_Type.test_type COLLATE NOCASE AND kept.id < ZTK_Logs_Test_Type.id);
+
+ALTER TABLE ZTK_Logs_Event_Type ADD COLUMN active TINYINT NOT NULL DEFAULT 1;
+
+CREATE UNIQUE INDEX ZTK_Logs_Event_Type_name ON ZTK_Logs_Event_Type (events_type COLLATE NOCASE);
+
+ALTER TABLE ZTK_Logs_Test_Type ADD COLUMN active TINYINT NOT NULL DEFAULT 1;
+
+CREATE UNIQUE INDEX ZTK_Logs_Test_Type_name ON ZTK_Logs_Test_Type (test_type COLLATE NOCASE);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ZTK_Logs_Test_Profile_Step;
+DROP TABLE IF EXISTS ZTK_Logs_Test_Profile;
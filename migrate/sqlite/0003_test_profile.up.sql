@@ -0,0 +1,26 @@
+-- Versioned multi-step profiles of a test type.
+
+CREATE TABLE ZTK_Logs_Test_Profile (
+    id                      INTEGER      PRIMARY KEY AUTOINCREMENT,
+    ZTK_Logs_Test_Type_id   INT          NOT NULL REFERENCES ZTK_Logs_Test_Type (id),
+    version                 INT          NOT NULL,
+    comment                 VARCHAR(255) NOT NULL DEFAULT '',
+    created_by              INT          NOT NULL DEFAULT 0,
+    created                 TEXT         NOT NULL,
+    UNIQUE (ZTK_Logs_Test_Type_id, version)
+);
+
+CREATE TABLE ZTK_Logs_Test_Profile_Step (
+    id                          INTEGER      PRIMARY KEY AUTOINCREMENT,
+    ZTK_Logs_Test_Profile_id    INT          NOT NULL REFERENCES ZTK_Logs_Test_Profile (id),
+    step_no                     INT          NOT NULL,
+    step_type                   VARCHAR(10)  NOT NULL,
+    temp_sp                     DOUBLE       NOT NULL DEFAULT 0,
+    hum_sp                      DOUBLE       NOT NULL DEFAULT 0,
+    press_sp                    DOUBLE       NOT NULL DEFAULT 0,
+    duration_min                INT          NOT NULL,
+    temp_tol                    DOUBLE       NOT NULL DEFAULT 0,
+    hum_tol                     DOUBLE       NOT NULL DEFAULT 0,
+    press_tol                   DOUBLE       NOT NULL DEFAULT 0,
+    UNIQUE (ZTK_Logs_Test_Profile_id, step_no)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE ZTK_Logs_Test DROP COLUMN ZTK_Logs_Test_Profile_id;
+
+ALTER TABLE ZTK_Logs_Test DROP COLUMN verdict_detail;
+
+ALTER TABLE ZTK_Logs_Test DROP COLUMN verdict;
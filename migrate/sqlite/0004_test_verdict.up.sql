@@ -0,0 +1,7 @@
+-- Verdict of a test evaluated against its profile.
+
+ALTER TABLE ZTK_Logs_Test ADD COLUMN verdict VARCHAR(10) NULL;
+
+ALTER TABLE ZTK_Logs_Test ADD COLUMN verdict_detail TEXT NULL;
+
+ALTER TABLE ZTK_Logs_Test ADD COLUMN ZTK_Logs_Test_Profile_id INT NULL;
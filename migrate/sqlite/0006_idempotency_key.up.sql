@@ -0,0 +1,15 @@
+-- Responses to requests sent with an Idempotency-Key, replayed to retries.
+
+CREATE TABLE ZTK_Idempotency_Key (
+    user_id         INT           NOT NULL,
+    idempotency_key VARCHAR(255)  NOT NULL,
+    method          VARCHAR(10)   NOT NULL,
+    path            VARCHAR(255)  NOT NULL,
+    request_hash    CHAR(64)      NOT NULL,
+    status_code     INT           NOT NULL,
+    response        BLOB          NOT NULL,
+    created         TEXT          NOT NULL,
+    PRIMARY KEY (user_id, idempotency_key)
+);
+
+CREATE INDEX ZTK_Idempotency_Key_created ON ZTK_Idempotency_Key (created);
@@ -0,0 +1,19 @@
+DROP INDEX IF EXISTS ZTK_Loop_Data_by_chamber;
+
+DROP INDEX IF EXISTS ZTK_Logs_Maintenance_by_chamber;
+
+DROP INDEX IF EXISTS ZTK_Logs_Test_by_chamber;
+
+DROP INDEX IF EXISTS ZTK_Logs_Event_by_chamber;
+
+ALTER TABLE ZTK_Loop_Data DROP COLUMN ZTK_Chamber_id;
+
+ALTER TABLE ZTK_Logs_Maintenance DROP COLUMN ZTK_Chamber_id;
+
+ALTER TABLE ZTK_Logs_Test DROP COLUMN ZTK_Chamber_id;
+
+ALTER TABLE ZTK_Logs_Event DROP COLUMN ZTK_Chamber_id;
+
+DROP TABLE IF EXISTS ZTK_Chamber_IO_Card;
+
+DROP TABLE IF EXISTS ZTK_Chamber;
@@ -0,0 +1,39 @@
+-- Registry of the chambers logging to the server and the IO cards fitted to
+-- them. Records keep ZTK_Chamber_id 0 until a chamber is given.
+
+CREATE TABLE ZTK_Chamber (
+    id              INTEGER      PRIMARY KEY AUTOINCREMENT,
+    name            VARCHAR(100) NOT NULL UNIQUE,
+    location        VARCHAR(255) NOT NULL DEFAULT '',
+    created_by      INT          NOT NULL DEFAULT 0,
+    modified_by     INT          NOT NULL DEFAULT 0,
+    created         TEXT         NULL,
+    modified        TEXT         NULL
+);
+
+CREATE TABLE ZTK_Chamber_IO_Card (
+    card_serial_number  VARCHAR(64)  PRIMARY KEY,
+    ZTK_Chamber_id      INT          NOT NULL REFERENCES ZTK_Chamber (id)
+);
+
+CREATE INDEX ZTK_Chamber_IO_Card_chamber ON ZTK_Chamber_IO_Card (ZTK_Chamber_id);
+
+ALTER TABLE ZTK_Logs_Event ADD COLUMN ZTK_Chamber_id INT NOT NULL DEFAULT 0;
+
+ALTER TABLE ZTK_Logs_Test ADD COLUMN ZTK_Chamber_id INT NOT NULL DEFAULT 0;
+
+ALTER TABLE ZTK_Logs_Maintenance ADD COLUMN ZTK_Chamber_id INT NOT NULL DEFAULT 0;
+
+ALTER TABLE ZTK_Loop_Data ADD COLUMN ZTK_Chamber_id INT NOT NULL DEFAULT 0;
+
+CREATE INDEX ZTK_Logs_Event_by_chamber ON ZTK_Logs_Event (ZTK_Chamber_id);
+
+CREATE INDEX ZTK_Logs_Test_by_chamber ON ZTK_Logs_Test (ZTK_Chamber_id);
+
+CREATE INDEX ZTK_Logs_Maintenance_by_chamber ON ZTK_Logs_Maintenance (ZTK_Chamber_id);
+
+-- A chamber has one sample per date time; of samples sent twice before, the
+-- last one is kept.
+DELETE FROM ZTK_Loop_Data WHERE id NOT IN (SELECT MAX(id) FROM ZTK_Loop_Data GROUP BY ZTK_Chamber_id, date_time);
+
+CREATE UNIQUE INDEX ZTK_Loop_Data_by_chamber ON ZTK_Loop_Data (ZTK_Chamber_id, date_time);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS ZTK_Loop_Data_Channel_channel;
+
+DROP TABLE IF EXISTS ZTK_Loop_Data_Channel;
+
+DROP TABLE IF EXISTS ZTK_Channel;
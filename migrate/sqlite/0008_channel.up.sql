@@ -0,0 +1,25 @@
+-- Catalogue of the Loop_Data channels beyond the six columns and the values
+-- of those channels in each sample.
+
+CREATE TABLE ZTK_Channel (
+    id              INTEGER      PRIMARY KEY AUTOINCREMENT,
+    name            VARCHAR(100) NOT NULL UNIQUE,
+    unit            VARCHAR(32)  NOT NULL DEFAULT '',
+    minimum         DOUBLE       NULL,
+    maximum         DOUBLE       NULL,
+    description     VARCHAR(255) NOT NULL DEFAULT '',
+    active          TINYINT      NOT NULL DEFAULT 1,
+    created_by      INT          NOT NULL DEFAULT 0,
+    modified_by     INT          NOT NULL DEFAULT 0,
+    created         TEXT         NULL,
+    modified        TEXT         NULL
+);
+
+CREATE TABLE ZTK_Loop_Data_Channel (
+    ZTK_Loop_Data_id    INT          NOT NULL REFERENCES ZTK_Loop_Data (id) ON DELETE CASCADE,
+    ZTK_Channel_id      INT          NOT NULL REFERENCES ZTK_Channel (id),
+    value               DOUBLE       NOT NULL,
+    PRIMARY KEY (ZTK_Loop_Data_id, ZTK_Channel_id)
+);
+
+CREATE INDEX ZTK_Loop_Data_Channel_channel ON ZTK_Loop_Data_Channel (ZTK_Channel_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ZTK_Loop_Data_Calibration;
+
+DROP INDEX IF EXISTS ZTK_Calibration_sensor;
+
+DROP TABLE IF EXISTS ZTK_Calibration;
@@ -0,0 +1,27 @@
+-- Calibrations of the sensors of the chambers, and the raw value and
+-- calibration of every Loop_Data value corrected on ingest.
+
+CREATE TABLE ZTK_Calibration (
+    id                  INTEGER      PRIMARY KEY AUTOINCREMENT,
+    ZTK_Chamber_id      INT          NOT NULL DEFAULT 0,
+    channel             VARCHAR(100) NOT NULL,
+    value_offset        DOUBLE       NOT NULL DEFAULT 0,
+    gain                DOUBLE       NOT NULL DEFAULT 1,
+    calibration_date    TEXT         NOT NULL,
+    due_date            TEXT         NOT NULL,
+    certificate         VARCHAR(100) NOT NULL DEFAULT '',
+    created_by          INT          NOT NULL DEFAULT 0,
+    modified_by         INT          NOT NULL DEFAULT 0,
+    created             TEXT         NULL,
+    modified            TEXT         NULL
+);
+
+CREATE INDEX ZTK_Calibration_sensor ON ZTK_Calibration (ZTK_Chamber_id, channel, calibration_date);
+
+CREATE TABLE ZTK_Loop_Data_Calibration (
+    ZTK_Loop_Data_id    INT          NOT NULL REFERENCES ZTK_Loop_Data (id) ON DELETE CASCADE,
+    channel             VARCHAR(100) NOT NULL,
+    raw_value           DOUBLE       NOT NULL,
+    ZTK_Calibration_id  INT          NOT NULL REFERENCES ZTK_Calibration (id),
+    PRIMARY KEY (ZTK_Loop_Data_id, channel)
+);
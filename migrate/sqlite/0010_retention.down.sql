@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS ZTK_Activity_Log_created;
+
+DROP INDEX IF EXISTS ZTK_Loop_Data_Hourly_hour;
+
+DROP TABLE IF EXISTS ZTK_Loop_Data_Hourly;
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Names of the Loop_Data columns holding process values, the columns a
+// calibration can correct
+var ProcessValueColumns = []string{"temp_pv", "hum_pv", "press_pv"}
+
+// Check that the due date of a calibration is after its calibration date
+func ValidateCalibration(calibration Calibration) error {
+
+	if !calibration.Calduedate.After(calibration.Caldate.Time) {
+		return fmt.Errorf("must be after calibration_date")
+	}
+
+	return nil
+}
+
+// Corrected value of a sensor
+func (c Calibration) Apply(raw float64) float64 {
+
+	return raw*c.Calgain + c.Caloffset
+}
+
+// Report whether the calibration is past its due date at the date time
+func (c Calibration) Expired(at Time) bool {
+
+	return c.Calduedate.Before(at.Time)
+}
+
+// Calibrations in force at the date time: of every chamber and channel the
+// one with the latest calibration date at or before it, ordered by chamber
+// and channel
+func CalibrationsAt(calibrations []Calibration, at Time) []Calibration {
+
+	type sensor struct {
+		chamberId int
+		channel   string
+	}
+
+	latest := map[sensor]Calibration{}
+
+	for _, calibration := range calibrations {
+
+		if calibration.Caldate.After(at.Time) {
+			continue
+		}
+
+		key := sensor{calibration.Calchamberid, calibration.Calchannel}
+
+		current, ok := latest[key]
+
+		if !ok || calibration.Caldate.After(current.Caldate.Time) || (calibration.Caldate.Equal(current.Caldate.Time) && calibration.Calid > current.Calid) {
+			latest[key] = calibration
+		}
+	}
+
+	inForce := []Calibration{}
+
+	for _, calibration := range latest {
+		inForce = append(inForce, calibration)
+	}
+
+	sort.Slice(inForce, func(i, j int) bool {
+
+		if inForce[i].Calchamberid != inForce[j].Calchamberid {
+			return inForce[i].Calchamberid < inForce[j].Calchamberid
+		}
+
+		return inForce[i].Calchannel < inForce[j].Calchannel
+	})
+
+	return inForce
+}
+
+// Calibrations in force at the date time that are past their due date
+func ExpiredCalibrations(calibrations []Calibration, at Time) []Calibration {
+
+	expired := []Calibration{}
+
+	for _, calibration := range CalibrationsAt(calibrations, at) {
+
+		if calibration.Expired(at) {
+			expired = append(expired, calibration)
+		}
+	}
+
+	return expired
+}
+
+// Correct the process values and channels of the sample by the calibrations
+// of its chamber in force at its date time, recording the raw value and the
+// calibration used of each corrected value in Calibrated. Values without a
+// calibration are kept as sent.
+func (log *Loop_Data) Calibrate(calibrations []Calibration) {
+
+	log.Dcalibrated = nil
+
+	columns := map[string]*float64{"temp_pv": &log.Dtpv, "hum_pv": &log.Dhpv, "press_pv": &log.Dppv}
+
+	for _, calibration := range CalibrationsAt(calibrations, log.Ddatatime) {
+
+		if calibration.Calchamberid != log.Dchamberid {
+			continue
+		}
+
+		value, isColumn := columns[calibration.Calchannel]
+
+		raw, isChannel := log.Dchannels[calibration.Calchannel]
+
+		if isColumn {
+			raw = *value
+		}
+
+		if !isColumn && !isChannel {
+			continue
+		}
+
+		if log.Dcalibrated == nil {
+			log.Dcalibrated = map[string]Calibrated_Value{}
+		}
+
+		log.Dcalibrated[calibration.Calchannel] = Calibrated_Value{Vraw: raw, Vcalibrationid: calibration.Calid}
+
+		if isColumn {
+			*value = calibration.Apply(raw)
+		} else {
+			log.Dchannels[calibration.Calchannel] = calibration.Apply(raw)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package model
+
+import "fmt"
+
+// Check that the minimum of a channel is not above its maximum
+func ValidateChannel(channel Channel) error {
+
+	if channel.Chminimum != nil && channel.Chmaximum != nil && *channel.Chminimum > *channel.Chmaximum {
+		return fmt.Errorf("must not be below minimum %v", *channel.Chminimum)
+	}
+
+	return nil
+}
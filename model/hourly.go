@@ -0,0 +1,107 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Hour a Loop_Data sample was captured in
+func (log Loop_Data) Hour() Time {
+
+	return NewTime(log.Ddatatime.Truncate(time.Hour))
+}
+
+// Roll the samples up into one Loop_Data_Hourly for every chamber and hour,
+// ordered by hour and chamber
+func RollUpLoopData(logs []Loop_Data) []Loop_Data_Hourly {
+
+	type key struct {
+		chamberId int
+		hour      int64
+	}
+
+	hours := map[key]*Loop_Data_Hourly{}
+
+	for _, log := range logs {
+
+		hour := log.Hour()
+
+		sample := Loop_Data_Hourly{
+			Hchamberid: log.Dchamberid,
+			Hhour:      hour,
+			Hsamples:   1,
+			Htsp:       log.Dtsp,
+			Htpv:       log.Dtpv,
+			Htpvmin:    log.Dtpv,
+			Htpvmax:    log.Dtpv,
+			Hhsp:       log.Dhsp,
+			Hhpv:       log.Dhpv,
+			Hhpvmin:    log.Dhpv,
+			Hhpvmax:    log.Dhpv,
+			Hpsp:       log.Dpsp,
+			Hppv:       log.Dppv,
+			Hppvmin:    log.Dppv,
+			Hppvmax:    log.Dppv,
+		}
+
+		k := key{log.Dchamberid, hour.Unix()}
+
+		if rolledUp, ok := hours[k]; ok {
+			rolledUp.Merge(sample)
+		} else {
+			hours[k] = &sample
+		}
+	}
+
+	rolledUp := []Loop_Data_Hourly{}
+
+	for _, hour := range hours {
+		rolledUp = append(rolledUp, *hour)
+	}
+
+	sort.Slice(rolledUp, func(i, j int) bool {
+
+		if !rolledUp[i].Hhour.Equal(rolledUp[j].Hhour.Time) {
+			return rolledUp[i].Hhour.Before(rolledUp[j].Hhour.Time)
+		}
+
+		return rolledUp[i].Hchamberid < rolledUp[j].Hchamberid
+	})
+
+	return rolledUp
+}
+
+// Add the samples of another roll-up of the same chamber and hour, e.g. of
+// samples that arrived after the hour was pruned
+func (h *Loop_Data_Hourly) Merge(other Loop_Data_Hourly) {
+
+	samples := h.Hsamples + other.Hsamples
+
+	if samples == 0 {
+		return
+	}
+
+	average := func(a float64, b float64) float64 {
+		return (a*float64(h.Hsamples) + b*float64(other.Hsamples)) / float64(samples)
+	}
+
+	h.Htsp = average(h.Htsp, other.Htsp)
+	h.Htpv = average(h.Htpv, other.Htpv)
+	h.Hhsp = average(h.Hhsp, other.Hhsp)
+	h.Hhpv = average(h.Hhpv, other.Hhpv)
+	h.Hpsp = average(h.Hpsp, other.Hpsp)
+	h.Hppv = average(h.Hppv, other.Hppv)
+
+	if h.Hsamples == 0 {
+		h.Htpvmin, h.Htpvmax = other.Htpvmin, other.Htpvmax
+		h.Hhpvmin, h.Hhpvmax = other.Hhpvmin, other.Hhpvmax
+		h.Hppvmin, h.Hppvmax = other.Hppvmin, other.Hppvmax
+	} else if other.Hsamples != 0 {
+		h.Htpvmin, h.Htpvmax = math.Min(h.Htpvmin, other.Htpvmin), math.Max(h.Htpvmax, other.Htpvmax)
+		h.Hhpvmin, h.Hhpvmax = math.Min(h.Hhpvmin, other.Hhpvmin), math.Max(h.Hhpvmax, other.Hhpvmax)
+		h.Hppvmin, h.Hppvmax = math.Min(h.Hppvmin, other.Hppvmin), math.Max(h.Hppvmax, other.Hppvmax)
+	}
+
+	h.Hsamples = samples
+}
@@ -0,0 +1,299 @@
+// Package model holds the records exchanged with the NGCS Local Log Server
+// and stored in the klima_chamber tables. The binding tags are the rules a
+// request must meet before the server stores it; "time" rejects a Time that
+// is not a date time. Dates are Time values in UTC.
+//
+// The created and modified fields of a record are stamped by the server, see
+// Stamp. The dates a device sends in them are kept as its device dates.
+//
+// A server may log several chambers: the records a chamber sends carry the
+// ZTK_Chamber_id it is registered under. Records with ZTK_Chamber_id 0 are
+// not assigned to a chamber, as on a server logging a single one.
+//
+// Besides its temperature, humidity and pressure columns a Loop_Data sample
+// carries the values of named channels, e.g. CO2 or a thermocouple, each
+// described by a Channel of the catalogue. The process values and channels
+// are corrected by the Calibration of their sensor when the sample is stored.
+package model
+
+// Struct to hold Logs_Event
+
+type Logs_Event struct {
+	Lid        string `json:"log_id" binding:"required"`
+	Pname      string `json:"program_name" binding:"required"`
+	Pdatetime  Time   `json:"program_date_time_date" binding:"required,time"`
+	Etypeid    int    `json:"ZTK_Logs_Event_Type_id" binding:"gte=0"`
+	Etypename  string `json:"events_type,omitempty"`
+	Echamberid int    `json:"ZTK_Chamber_id" binding:"gte=0"`
+	Eid        int    `json:"ZTK_Users_id" binding:"gte=0"`
+	Createdby  int    `json:"created_by" binding:"gte=0"`
+	Ecreated   Time   `json:"created_date" binding:"time"`
+	Modifiedby int    `json:"modified_by" binding:"gte=0"`
+	Emodified  Time   `json:"modified_date" binding:"time"`
+
+	Edevicecreated  Time `json:"device_created_date" binding:"time"`
+	Edevicemodified Time `json:"device_modified_date" binding:"time"`
+
+	LogEventType *Logs_Event_Type `json:"ZTK_Logs_Event_Type,omitempty"`
+}
+
+// Struct to hold Logs_Event_Type
+
+type Logs_Event_Type struct {
+	Lid        int    `json:"id"`
+	Lactive    int    `json:"active"`
+	Levents    string `json:"events_type"`
+	Lcreated   int    `json:"created_by"`
+	Lmodified  int    `json:"modified_by"`
+	Lcreated1  Time   `json:"create_date" binding:"time"`
+	Lmodified2 Time   `json:"modified_date" binding:"time"`
+
+	Ldevicecreated  Time `json:"device_create_date" binding:"time"`
+	Ldevicemodified Time `json:"device_modified_date" binding:"time"`
+}
+
+// Struct to hold Logs_Test
+
+type Logs_Test struct {
+	Tid         string `json:"log_id" binding:"required"`
+	Tname       string `json:"log_name" binding:"required"`
+	Tdatetime   Time   `json:"log_date_time_date" binding:"required,time"`
+	Ttypeid     int    `json:"ZTK_Logs_Test_Type_id" binding:"gte=0"`
+	Ttypename   string `json:"test_type,omitempty"`
+	Tchamberid  int    `json:"ZTK_Chamber_id" binding:"gte=0"`
+	Tuserid     int    `json:"ZTK_Users_id" binding:"gte=0"`
+	Tcreatedby  int    `json:"created_by" binding:"gte=0"`
+	Tcreated    Time   `json:"created_date" binding:"time"`
+	Tmodifiedby int    `json:"modified_by" binding:"gte=0"`
+	Tmodified   Time   `json:"modified_date" binding:"time"`
+
+	Tdevicecreated  Time `json:"device_created_date" binding:"time"`
+	Tdevicemodified Time `json:"device_modified_date" binding:"time"`
+
+	LogTestType *Logs_Test_Type `json:"ZTK_Logs_Test_Type,omitempty"`
+}
+
+// Struct to hold Logs_Test_Type
+
+type Logs_Test_Type struct {
+	Lid        int    `json:"id"`
+	Lactive    int    `json:"active"`
+	Ltesttype  string `json:"test_type"`
+	Tcreated1  Time   `json:"create_date" binding:"time"`
+	Tmodified2 Time   `json:"modified_date" binding:"time"`
+
+	Tdevicecreated1  Time `json:"device_create_date" binding:"time"`
+	Tdevicemodified2 Time `json:"device_modified_date" binding:"time"`
+	Tcreatedby1      int  `json:"created_by"`
+	Tmodifiedby2     int  `json:"modified_by"`
+}
+
+// Struct to hold Logs_Maintenance
+
+type Logs_Maintenance struct {
+	Mname       string `json:"component_name" binding:"required"`
+	Mruntime    int    `json:"runtime_hr" binding:"gte=0"`
+	Mcounter    int    `json:"counter" binding:"gte=0"`
+	Mservice    int    `json:"days_till_service"`
+	Mpending    int    `json:"maintenance_pending" binding:"oneof=0 1"`
+	Mstatus     int    `json:"maintenance_status" binding:"oneof=0 1"`
+	Mchamberid  int    `json:"ZTK_Chamber_id" binding:"gte=0"`
+	Mcreated    Time   `json:"created_date" binding:"time"`
+	Mmodified   Time   `json:"modified_date" binding:"time"`
+	Mcreatedby  int    `json:"created_by" binding:"gte=0"`
+	Mmodifiedby int    `json:"modified_by" binding:"gte=0"`
+
+	Mdevicecreated  Time `json:"device_created_date" binding:"time"`
+	Mdevicemodified Time `json:"device_modified_date" binding:"time"`
+}
+
+// Struct to hold Logs_Data. Channels holds the values of the channels
+// other than the six columns, keyed by channel name. Calibrated is set by the
+// server for every value it corrected, see Calibrate.
+
+type Loop_Data struct {
+	Dtsp       float64 `json:"temp_sp"`
+	Dtpv       float64 `json:"temp_pv"`
+	Dhsp       float64 `json:"hum_sp" binding:"gte=0,lte=100"`
+	Dhpv       float64 `json:"hum_pv" binding:"gte=0,lte=100"`
+	Dpsp       float64 `json:"press_sp" binding:"gte=0"`
+	Dppv       float64 `json:"press_pv" binding:"gte=0"`
+	Ddatatime  Time    `json:"date_time_date" binding:"required,time"`
+	Dchamberid int     `json:"ZTK_Chamber_id" binding:"gte=0"`
+
+	Dchannels   map[string]float64          `json:"channels,omitempty"`
+	Dcalibrated map[string]Calibrated_Value `json:"calibrated,omitempty"`
+}
+
+// Struct to hold Calibrated_Value: the value a sensor sent before it was
+// corrected, and the Calibration that corrected it
+
+type Calibrated_Value struct {
+	Vraw           float64 `json:"raw"`
+	Vcalibrationid int     `json:"ZTK_Calibration_id"`
+}
+
+// Struct to hold Loop_Data_Hourly: the Loop_Data a chamber captured in one
+// hour, rolled up before the samples are pruned. The setpoints and process
+// values are averages of the samples; the channel values are not rolled up.
+
+type Loop_Data_Hourly struct {
+	Hchamberid int     `json:"ZTK_Chamber_id"`
+	Hhour      Time    `json:"hour"`
+	Hsamples   int     `json:"samples"`
+	Htsp       float64 `json:"temp_sp"`
+	Htpv       float64 `json:"temp_pv"`
+	Htpvmin    float64 `json:"temp_pv_min"`
+	Htpvmax    float64 `json:"temp_pv_max"`
+	Hhsp       float64 `json:"hum_sp"`
+	Hhpv       float64 `json:"hum_pv"`
+	Hhpvmin    float64 `json:"hum_pv_min"`
+	Hhpvmax    float64 `json:"hum_pv_max"`
+	Hpsp       float64 `json:"press_sp"`
+	Hppv       float64 `json:"press_pv"`
+	Hppvmin    float64 `json:"press_pv_min"`
+	Hppvmax    float64 `json:"press_pv_max"`
+}
+
+// Names of the channels stored in the Loop_Data columns. They are sent as
+// fields of the sample, not in its channels.
+var LoopDataColumns = []string{"temp_sp", "temp_pv", "hum_sp", "hum_pv", "press_sp", "press_pv"}
+
+// Struct to hold Channel: a channel of the catalogue, with the unit of its
+// values and the range a value must be in. A minimum or maximum left out
+// leaves that side of the range open.
+
+type Channel struct {
+	Chid          int      `json:"id"`
+	Chactive      int      `json:"active"`
+	Chname        string   `json:"name" binding:"required"`
+	Chunit        string   `json:"unit"`
+	Chminimum     *float64 `json:"minimum"`
+	Chmaximum     *float64 `json:"maximum"`
+	Chdescription string   `json:"description"`
+	Chcreatedby   int      `json:"created_by"`
+	Chcreated     Time     `json:"created_date" binding:"time"`
+	Chmodifiedby  int      `json:"modified_by"`
+	Chmodified    Time     `json:"modified_date" binding:"time"`
+}
+
+// Struct to hold Calibration: the calibration of the sensor of a process
+// value or channel of a chamber, as on its certificate. A sensor value is
+// corrected to value * gain + offset; the server takes a gain of 0 as 1. A
+// calibration is in force from its calibration date until the sensor is
+// calibrated again, and expired after its due date.
+
+type Calibration struct {
+	Calid          int     `json:"id"`
+	Calchamberid   int     `json:"ZTK_Chamber_id" binding:"gte=0"`
+	Calchannel     string  `json:"channel" binding:"required"`
+	Caloffset      float64 `json:"offset"`
+	Calgain        float64 `json:"gain"`
+	Caldate        Time    `json:"calibration_date" binding:"required,time"`
+	Calduedate     Time    `json:"due_date" binding:"required,time"`
+	Calcertificate string  `json:"certificate"`
+	Calcreatedby   int     `json:"created_by"`
+	Calcreated     Time    `json:"created_date" binding:"time"`
+	Calmodifiedby  int     `json:"modified_by"`
+	Calmodified    Time    `json:"modified_date" binding:"time"`
+}
+
+// Struct to hold Chamber: a climate chamber logging to the server, with the
+// serial numbers of the IO cards fitted to it. A card is fitted to one
+// chamber at a time. TimeZone is the zone of its controller, for the date
+// times it sends without one; "" is the TimeZone of the server.
+
+type Chamber struct {
+	Cid         int      `json:"id"`
+	Cname       string   `json:"name" binding:"required"`
+	Clocation   string   `json:"location"`
+	Ctimezone   string   `json:"time_zone" binding:"omitempty,timezone"`
+	Ciocards    []string `json:"io_cards"`
+	Ccreatedby  int      `json:"created_by"`
+	Ccreated    Time     `json:"created_date" binding:"time"`
+	Cmodifiedby int      `json:"modified_by"`
+	Cmodified   Time     `json:"modified_date" binding:"time"`
+}
+
+// Struct to hold Io_card_Info
+
+type Io_card_Info struct {
+	Iaddress    string `json:"card_address" binding:"required"`
+	Itype       string `json:"card_type" binding:"required"`
+	Iversion    string `json:"card_version"`
+	Inumber     string `json:"card_serial_number" binding:"required"`
+	Ikey        string `json:"secret_key"`
+	Iid         int    `json:"customer_id" binding:"gte=0"`
+	Idate       Time   `json:"mfg_date_date" binding:"time"`
+	Icreated    Time   `json:"created_date" binding:"time"`
+	Imodified   Time   `json:"modified_date" binding:"time"`
+	Icreatedby  int    `json:"created_by" binding:"gte=0"`
+	Imodifiedby int    `json:"modified_by" binding:"gte=0"`
+
+	Idevicecreated  Time `json:"device_created_date" binding:"time"`
+	Idevicemodified Time `json:"device_modified_date" binding:"time"`
+}
+
+// Struct to hold the combined document posted to /Logs_All. Every part is
+// optional; the Logs_Event and Logs_Test may embed their type instead of
+// referring to it by id.
+
+type Logs_All struct {
+	Test        *Logs_Test        `json:"ZTK_Logs_Test"`
+	Event       *Logs_Event       `json:"ZTK_Logs_Event"`
+	Maintenance *Logs_Maintenance `json:"Logs_Maintenance"`
+}
+
+// Struct to hold one step of a Logs_Test_Profile. A "ramp" step moves the
+// setpoints linearly from the previous step to the values given here over
+// duration_min, a "soak" step holds them for duration_min.
+
+type Logs_Test_Profile_Step struct {
+	Sno       int     `json:"step_no"`
+	Stype     string  `json:"step_type"`
+	Stsp      float64 `json:"temp_sp"`
+	Shsp      float64 `json:"hum_sp"`
+	Spsp      float64 `json:"press_sp"`
+	Sduration int     `json:"duration_min"`
+	Sttol     float64 `json:"temp_tol"`
+	Shtol     float64 `json:"hum_tol"`
+	Sptol     float64 `json:"press_tol"`
+}
+
+// Struct to hold Logs_Test_Profile. Every POST for a test type stores a new
+// version; older versions are kept so past tests can be traced to the exact
+// profile they ran.
+
+type Logs_Test_Profile struct {
+	Pid        int                      `json:"id"`
+	Ptypeid    int                      `json:"ZTK_Logs_Test_Type_id"`
+	Pversion   int                      `json:"version"`
+	Pcomment   string                   `json:"comment"`
+	Psteps     []Logs_Test_Profile_Step `json:"steps"`
+	Pcreatedby int                      `json:"created_by"`
+	Pcreated   Time                     `json:"created_date" binding:"time"`
+}
+
+// Struct to hold the conformance result of one profile step. Times are in
+// seconds; settling_time_sec is -1 when the step never settled.
+
+type Profile_Step_Result struct {
+	Sno      int     `json:"step_no"`
+	Spass    bool    `json:"pass"`
+	Ssamples int     `json:"samples"`
+	Sout     float64 `json:"time_out_of_tolerance_sec"`
+	Stover   float64 `json:"temp_overshoot"`
+	Shover   float64 `json:"hum_overshoot"`
+	Spover   float64 `json:"press_overshoot"`
+	Ssettle  float64 `json:"settling_time_sec"`
+}
+
+// Struct to hold the verdict of a Logs_Test against its profile
+
+type Profile_Evaluation struct {
+	Elogid     string                `json:"log_id"`
+	Eprofileid int                   `json:"ZTK_Logs_Test_Profile_id"`
+	Eversion   int                   `json:"version"`
+	Everdict   string                `json:"verdict"`
+	Esteps     []Profile_Step_Result `json:"steps"`
+}
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Check that a profile can be run by a chamber: at least one step, known
+// step types and positive durations.
+func ValidateTestProfile(profile Logs_Test_Profile) error {
+
+	if len(profile.Psteps) == 0 {
+		return fmt.Errorf("profile has no steps")
+	}
+
+	for i, step := range profile.Psteps {
+
+		if step.Stype != "ramp" && step.Stype != "soak" {
+			return fmt.Errorf("step %d: step_type must be ramp or soak", i+1)
+		}
+
+		if step.Sduration <= 0 {
+			return fmt.Errorf("step %d: duration_min must be positive", i+1)
+		}
+
+		if step.Sttol < 0 || step.Shtol < 0 || step.Sptol < 0 {
+			return fmt.Errorf("step %d: tolerances must not be negative", i+1)
+		}
+	}
+
+	return nil
+}
+
+// Part of a step's duration its checked channels may take to settle
+const maxSettle = 0.5
+
+// Total run time of all the steps of a profile
+func ProfileDuration(profile Logs_Test_Profile) time.Duration {
+
+	var total time.Duration
+
+	for _, step := range profile.Psteps {
+		total += time.Duration(step.Sduration) * time.Minute
+	}
+
+	return total
+}
+
+// Judge the recorded samples against the profile, which started at start.
+//
+// Each step covers its own window of duration_min. The expected value of a
+// soak step is its setpoint; a ramp step moves linearly from the previous
+// step's setpoint (or the first measured value for the first step). A channel
+// whose tolerance is 0 is not checked. A sample is out of tolerance when any
+// checked channel deviates by more than its tolerance, and counts until the
+// next sample. A step passes when it has settled within the first half of
+// its duration: all checked channels are within tolerance from a sample in
+// that half until the step ends. A step out of tolerance for more than half
+// of its duration, or without samples in its first half, fails.
+func EvaluateTestProfile(profile Logs_Test_Profile, start time.Time, samples []Loop_Data) Profile_Evaluation {
+
+	type sample struct {
+		at  time.Time
+		tpv float64
+		hpv float64
+		ppv float64
+	}
+
+	var points []sample
+
+	for _, log := range samples {
+
+		if log.Ddatatime.IsZero() {
+			continue
+		}
+
+		points = append(points, sample{log.Ddatatime.Time, log.Dtpv, log.Dhpv, log.Dppv})
+	}
+
+	evaluation := Profile_Evaluation{
+		Eprofileid: profile.Pid,
+		Eversion:   profile.Pversion,
+		Everdict:   "PASS",
+		Esteps:     []Profile_Step_Result{},
+	}
+
+	var prevT, prevH, prevP float64
+
+	if len(points) > 0 {
+		prevT, prevH, prevP = points[0].tpv, points[0].hpv, points[0].ppv
+	} else if len(profile.Psteps) > 0 {
+		prevT, prevH, prevP = profile.Psteps[0].Stsp, profile.Psteps[0].Shsp, profile.Psteps[0].Spsp
+	}
+
+	stepStart := start
+
+	for i, step := range profile.Psteps {
+
+		stepLen := time.Duration(step.Sduration) * time.Minute
+		stepEnd := stepStart.Add(stepLen)
+
+		result := Profile_Step_Result{Sno: i + 1, Ssettle: -1}
+
+		var window []sample
+
+		for _, p := range points {
+			if !p.at.Before(stepStart) && p.at.Before(stepEnd) {
+				window = append(window, p)
+			}
+		}
+
+		result.Ssamples = len(window)
+
+		var settledAt time.Time
+		settled := false
+
+		for j, p := range window {
+
+			expT, expH, expP := step.Stsp, step.Shsp, step.Spsp
+
+			if step.Stype == "ramp" {
+				frac := float64(p.at.Sub(stepStart)) / float64(stepLen)
+				expT = prevT + (step.Stsp-prevT)*frac
+				expH = prevH + (step.Shsp-prevH)*frac
+				expP = prevP + (step.Spsp-prevP)*frac
+			}
+
+			result.Stover = math.Max(result.Stover, overshoot(p.tpv-expT, step.Stsp-prevT))
+			result.Shover = math.Max(result.Shover, overshoot(p.hpv-expH, step.Shsp-prevH))
+			result.Spover = math.Max(result.Spover, overshoot(p.ppv-expP, step.Spsp-prevP))
+
+			out := outOfTolerance(p.tpv-expT, step.Sttol) ||
+				outOfTolerance(p.hpv-expH, step.Shtol) ||
+				outOfTolerance(p.ppv-expP, step.Sptol)
+
+			if out {
+
+				next := stepEnd
+
+				if j+1 < len(window) {
+					next = window[j+1].at
+				}
+
+				result.Sout += next.Sub(p.at).Seconds()
+				settled = false
+
+			} else if !settled {
+
+				settled = true
+				settledAt = p.at
+			}
+		}
+
+		if settled {
+			result.Ssettle = settledAt.Sub(stepStart).Seconds()
+		}
+
+		result.Spass = settled && settledAt.Sub(stepStart) <= time.Duration(float64(stepLen)*maxSettle)
+
+		if !result.Spass {
+			evaluation.Everdict = "FAIL"
+		}
+
+		evaluation.Esteps = append(evaluation.Esteps, result)
+
+		prevT, prevH, prevP = step.Stsp, step.Shsp, step.Spsp
+		stepStart = stepEnd
+	}
+
+	return evaluation
+}
+
+// Amount by which a deviation goes past the target in the direction the
+// setpoint was moving. For a constant setpoint any deviation counts.
+func overshoot(deviation float64, direction float64) float64 {
+
+	if direction > 0 {
+		return math.Max(deviation, 0)
+	}
+
+	if direction < 0 {
+		return math.Max(-deviation, 0)
+	}
+
+	return math.Abs(deviation)
+}
+
+func outOfTolerance(deviation float64, tolerance float64) bool {
+
+	return tolerance > 0 && math.Abs(deviation) > tolerance
+}
@@ -0,0 +1,199 @@
+package model
+
+// Record whose creation and modification the server stamps with the user it
+// was sent by and the server time. The dates a device sent in the created
+// and modified fields are moved to its device dates, unless it sent those
+// itself.
+
+type Stamped interface {
+	StampCreated(userId int, at Time)
+	StampModified(userId int, at Time)
+}
+
+// Date the device sent for a device date, taken from the stamped field when
+// the device date itself was not sent
+func deviceTime(device Time, sent Time) Time {
+
+	if device.IsZero() {
+		return sent
+	}
+
+	return device
+}
+
+func (log *Logs_Event) StampCreated(userId int, at Time) {
+
+	log.Edevicecreated = deviceTime(log.Edevicecreated, log.Ecreated)
+	log.Createdby, log.Ecreated = userId, at
+
+	log.StampModified(userId, at)
+
+	if log.LogEventType != nil {
+		log.LogEventType.StampCreated(userId, at)
+	}
+}
+
+func (log *Logs_Event) StampModified(userId int, at Time) {
+
+	log.Edevicemodified = deviceTime(log.Edevicemodified, log.Emodified)
+	log.Modifiedby, log.Emodified = userId, at
+}
+
+func (t *Logs_Event_Type) StampCreated(userId int, at Time) {
+
+	t.Ldevicecreated = deviceTime(t.Ldevicecreated, t.Lcreated1)
+	t.Lcreated, t.Lcreated1 = userId, at
+
+	t.StampModified(userId, at)
+}
+
+func (t *Logs_Event_Type) StampModified(userId int, at Time) {
+
+	t.Ldevicemodified = deviceTime(t.Ldevicemodified, t.Lmodified2)
+	t.Lmodified, t.Lmodified2 = userId, at
+}
+
+func (log *Logs_Test) StampCreated(userId int, at Time) {
+
+	log.Tdevicecreated = deviceTime(log.Tdevicecreated, log.Tcreated)
+	log.Tcreatedby, log.Tcreated = userId, at
+
+	log.StampModified(userId, at)
+
+	if log.LogTestType != nil {
+		log.LogTestType.StampCreated(userId, at)
+	}
+}
+
+func (log *Logs_Test) StampModified(userId int, at Time) {
+
+	log.Tdevicemodified = deviceTime(log.Tdevicemodified, log.Tmodified)
+	log.Tmodifiedby, log.Tmodified = userId, at
+}
+
+func (t *Logs_Test_Type) StampCreated(userId int, at Time) {
+
+	t.Tdevicecreated1 = deviceTime(t.Tdevicecreated1, t.Tcreated1)
+	t.Tcreatedby1, t.Tcreated1 = userId, at
+
+	t.StampModified(userId, at)
+}
+
+func (t *Logs_Test_Type) StampModified(userId int, at Time) {
+
+	t.Tdevicemodified2 = deviceTime(t.Tdevicemodified2, t.Tmodified2)
+	t.Tmodifiedby2, t.Tmodified2 = userId, at
+}
+
+func (log *Logs_Maintenance) StampCreated(userId int, at Time) {
+
+	log.Mdevicecreated = deviceTime(log.Mdevicecreated, log.Mcreated)
+	log.Mcreatedby, log.Mcreated = userId, at
+
+	log.StampModified(userId, at)
+}
+
+func (log *Logs_Maintenance) StampModified(userId int, at Time) {
+
+	log.Mdevicemodified = deviceTime(log.Mdevicemodified, log.Mmodified)
+	log.Mmodifiedby, log.Mmodified = userId, at
+}
+
+func (log *Io_card_Info) StampCreated(userId int, at Time) {
+
+	log.Idevicecreated = deviceTime(log.Idevicecreated, log.Icreated)
+	log.Icreatedby, log.Icreated = userId, at
+
+	log.StampModified(userId, at)
+}
+
+func (log *Io_card_Info) StampModified(userId int, at Time) {
+
+	log.Idevicemodified = deviceTime(log.Idevicemodified, log.Imodified)
+	log.Imodifiedby, log.Imodified = userId, at
+}
+
+// A chamber is registered on the server, it has no device dates
+func (c *Chamber) StampCreated(userId int, at Time) {
+
+	c.Ccreatedby, c.Ccreated = userId, at
+
+	c.StampModified(userId, at)
+}
+
+func (c *Chamber) StampModified(userId int, at Time) {
+
+	c.Cmodifiedby, c.Cmodified = userId, at
+}
+
+// A channel is catalogued on the server, it has no device dates
+func (c *Channel) StampCreated(userId int, at Time) {
+
+	c.Chcreatedby, c.Chcreated = userId, at
+
+	c.StampModified(userId, at)
+}
+
+func (c *Channel) StampModified(userId int, at Time) {
+
+	c.Chmodifiedby, c.Chmodified = userId, at
+}
+
+// A calibration is recorded on the server, it has no device dates
+func (c *Calibration) StampCreated(userId int, at Time) {
+
+	c.Calcreatedby, c.Calcreated = userId, at
+
+	c.StampModified(userId, at)
+}
+
+func (c *Calibration) StampModified(userId int, at Time) {
+
+	c.Calmodifiedby, c.Calmodified = userId, at
+}
+
+// Stamp every part of the document
+func (log *Logs_All) StampCreated(userId int, at Time) {
+
+	for _, part := range log.parts() {
+		part.StampCreated(userId, at)
+	}
+}
+
+func (log *Logs_All) StampModified(userId int, at Time) {
+
+	for _, part := range log.parts() {
+		part.StampModified(userId, at)
+	}
+}
+
+// Parts of the document that are present
+func (log *Logs_All) parts() []Stamped {
+
+	var parts []Stamped
+
+	if log.Event != nil {
+		parts = append(parts, log.Event)
+	}
+
+	if log.Test != nil {
+		parts = append(parts, log.Test)
+	}
+
+	if log.Maintenance != nil {
+		parts = append(parts, log.Maintenance)
+	}
+
+	return parts
+}
+
+// A profile is never modified, every change is a new version
+func (profile *Logs_Test_Profile) StampCreated(userId int, at Time) {
+
+	profile.Pcreatedby, profile.Pcreated = userId, at
+}
+
+func (profile *Logs_Test_Profile) StampModified(userId int, at Time) {
+
+	profile.StampCreated(userId, at)
+}
@@ -0,0 +1,266 @@
+package model
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Struct to hold a date time of the log tables, always in UTC to the second.
+//
+// In JSON it is read from RFC 3339, e.g. "2019-01-10T04:00:55+01:00", or from
+// the layout of the chamber controllers, "2019-01-10 04:00:55", which has no
+// zone and is taken to be in the zone set by SetLocation, or in the zone of
+// the chamber that sent it, see InZone. Spaces around the
+// value are ignored and "" or null is the zero Time. A value that is not a
+// date time is kept as Invalid for the "time" binding rule of the server to
+// reject. It is written as RFC 3339 in UTC, "2019-01-10T03:00:55Z", or null
+// when zero. The DB columns hold it in DateTimeLayout in the zone set by
+// SetDBLocation, UTC by default.
+
+type Time struct {
+	time.Time
+
+	invalid string
+	wall    string
+}
+
+// Layout of the date times stored in the klima_chamber tables, and of the
+// chamber controllers
+const DateTimeLayout = "2006-01-02 15:04:05"
+
+// Date times Time accepts, for error messages
+const TimeExample = "2019-01-10T04:00:55Z or 2019-01-10 04:00:55"
+
+var (
+	locationMu sync.RWMutex
+	location   = time.Local
+	dbLocation = time.UTC
+)
+
+// Set the zone of the chamber, used for date times given without one
+func SetLocation(loc *time.Location) {
+
+	locationMu.Lock()
+	defer locationMu.Unlock()
+
+	location = loc
+}
+
+// Zone of the chamber
+func Location() *time.Location {
+
+	locationMu.RLock()
+	defer locationMu.RUnlock()
+
+	return location
+}
+
+// Set the zone the DB columns hold date times in. Only a DB written before
+// date times were stored in UTC is in another zone, see DBConfig.DBTimeZone.
+func SetDBLocation(loc *time.Location) {
+
+	locationMu.Lock()
+	defer locationMu.Unlock()
+
+	dbLocation = loc
+}
+
+// Zone the DB columns hold date times in
+func DBLocation() *time.Location {
+
+	locationMu.RLock()
+	defer locationMu.RUnlock()
+
+	return dbLocation
+}
+
+// Time of t in UTC to the second
+func NewTime(t time.Time) Time {
+
+	if t.IsZero() {
+		return Time{}
+	}
+
+	return Time{Time: t.UTC().Truncate(time.Second)}
+}
+
+// Current time of the server
+func Now() Time {
+	return NewTime(time.Now())
+}
+
+// Parse a date time in RFC 3339 or DateTimeLayout, see Time
+func ParseTime(value string) (Time, error) {
+
+	value = strings.TrimSpace(value)
+
+	if value == "" {
+		return Time{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+
+	if err == nil {
+		return NewTime(t), nil
+	}
+
+	zoned, err := Time{wall: value}.inLocation(Location())
+
+	if err != nil {
+		return Time{}, fmt.Errorf("%q is not a date time like %s", value, TimeExample)
+	}
+
+	return zoned, nil
+}
+
+// Date time given in DateTimeLayout taken to be in loc, the zone of the
+// chamber that sent it, instead of the zone set by SetLocation. A date time
+// given with a zone is returned as it is.
+func (t Time) InZone(loc *time.Location) Time {
+
+	if t.wall == "" {
+		return t
+	}
+
+	zoned, err := t.inLocation(loc)
+
+	if err != nil {
+		return t
+	}
+
+	return zoned
+}
+
+func (t Time) inLocation(loc *time.Location) (Time, error) {
+
+	parsed, err := time.ParseInLocation(DateTimeLayout, t.wall, loc)
+
+	if err != nil {
+		return t, err
+	}
+
+	zoned := NewTime(parsed)
+	zoned.wall = t.wall
+
+	return zoned, nil
+}
+
+// Time in DateTimeLayout in the zone of the DB, as stored in the DB
+func (t Time) DB() string {
+
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.In(DBLocation()).Format(DateTimeLayout)
+}
+
+func (t Time) String() string {
+
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.String())
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+
+	if bytes.Equal(data, []byte("null")) {
+		*t = Time{}
+		return nil
+	}
+
+	var value string
+
+	err := json.Unmarshal(data, &value)
+
+	if err == nil {
+		*t, err = ParseTime(value)
+	}
+
+	if err != nil {
+		*t = Time{invalid: string(data)}
+	}
+
+	return nil
+}
+
+// JSON value read into t that is not a date time, "" when t is valid
+func (t Time) Invalid() string {
+	return t.invalid
+}
+
+// Value stored in the DB, NULL when zero
+func (t Time) Value() (driver.Value, error) {
+
+	if t.invalid != "" {
+		return nil, fmt.Errorf("%s is not a date time like %s", t.invalid, TimeExample)
+	}
+
+	if t.IsZero() {
+		return nil, nil
+	}
+
+	return t.DB(), nil
+}
+
+// Read a DB column. MySQL returns DATETIME columns as text, SQLite returns
+// TEXT columns as a string; both are in the zone of the DB.
+func (t *Time) Scan(src interface{}) error {
+
+	switch value := src.(type) {
+
+	case nil:
+		*t = Time{}
+		return nil
+
+	case time.Time:
+		*t = NewTime(value)
+		return nil
+
+	case []byte:
+		return t.scanText(string(value))
+
+	case string:
+		return t.scanText(value)
+	}
+
+	return fmt.Errorf("cannot read %T as a date time", src)
+}
+
+func (t *Time) scanText(value string) error {
+
+	if value == "" || strings.HasPrefix(value, "0000-00-00") {
+		*t = Time{}
+		return nil
+	}
+
+	parsed, err := time.ParseInLocation(DateTimeLayout, value, DBLocation())
+
+	if err != nil {
+		parsed, err = time.Parse(time.RFC3339, value)
+	}
+
+	if err != nil {
+		return fmt.Errorf("cannot read %q as a date time", value)
+	}
+
+	*t = NewTime(parsed)
+
+	return nil
+}
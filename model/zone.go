@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+// Record a chamber sends, whose date times given without a zone are in the
+// zone of the chamber. ChamberInZone takes them to be in the zone returned
+// for the ZTK_Chamber_id of the record, see Time.InZone.
+
+type ChamberZoned interface {
+	ChamberInZone(zone func(chamberId int) *time.Location)
+}
+
+func (log *Logs_Event) ChamberInZone(zone func(chamberId int) *time.Location) {
+
+	loc := zone(log.Echamberid)
+
+	log.Pdatetime = log.Pdatetime.InZone(loc)
+	log.Ecreated = log.Ecreated.InZone(loc)
+	log.Emodified = log.Emodified.InZone(loc)
+	log.Edevicecreated = log.Edevicecreated.InZone(loc)
+	log.Edevicemodified = log.Edevicemodified.InZone(loc)
+}
+
+func (log *Logs_Test) ChamberInZone(zone func(chamberId int) *time.Location) {
+
+	loc := zone(log.Tchamberid)
+
+	log.Tdatetime = log.Tdatetime.InZone(loc)
+	log.Tcreated = log.Tcreated.InZone(loc)
+	log.Tmodified = log.Tmodified.InZone(loc)
+	log.Tdevicecreated = log.Tdevicecreated.InZone(loc)
+	log.Tdevicemodified = log.Tdevicemodified.InZone(loc)
+}
+
+func (log *Logs_Maintenance) ChamberInZone(zone func(chamberId int) *time.Location) {
+
+	loc := zone(log.Mchamberid)
+
+	log.Mcreated = log.Mcreated.InZone(loc)
+	log.Mmodified = log.Mmodified.InZone(loc)
+	log.Mdevicecreated = log.Mdevicecreated.InZone(loc)
+	log.Mdevicemodified = log.Mdevicemodified.InZone(loc)
+}
+
+func (log *Loop_Data) ChamberInZone(zone func(chamberId int) *time.Location) {
+
+	log.Ddatatime = log.Ddatatime.InZone(zone(log.Dchamberid))
+}
+
+func (all *Logs_All) ChamberInZone(zone func(chamberId int) *time.Location) {
+
+	if all.Test != nil {
+		all.Test.ChamberInZone(zone)
+	}
+
+	if all.Event != nil {
+		all.Event.ChamberInZone(zone)
+	}
+
+	if all.Maintenance != nil {
+		all.Maintenance.ChamberInZone(zone)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/retention"
+)
+
+// Archive and prune the rows older than their retention once, e.g.
+//
+//	ngcslog prune -dry-run
+//
+// serve does the same every hour.
+func prune(args []string) {
+
+	flags := flag.NewFlagSet("prune", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	dryRun := flags.Bool("dry-run", false, "list what would be archived and pruned, changing nothing")
+	flags.Parse(args)
+
+	cfg := loadConfig(loader, config.Config.Validate)
+
+	policy := retention.PolicyOf(cfg)
+
+	if !policy.Prunes() {
+		fmt.Println("Nothing to prune, LoopDataRetentionDays and ActivityLogRetentionDays are 0.")
+		return
+	}
+
+	logStore := openStore(cfg.DB)
+
+	defer logStore.Close()
+
+	pruned, err := retention.Run(logStore, policy, time.Now(), *dryRun)
+
+	reportPruned(pruned, *dryRun)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+		os.Exit(1)
+	}
+}
+
+// Print how many rows of each table were pruned and where they were archived
+func reportPruned(pruned []retention.Pruned, dryRun bool) {
+
+	verb := "Pruned"
+
+	if dryRun {
+		verb = "Would prune"
+	}
+
+	for _, table := range pruned {
+
+		if table.Rows == 0 {
+			continue
+		}
+
+		fmt.Printf("%s %d rows of %s before %s, archived to %d files in %s\n", verb, table.Rows, table.Table, table.Before, len(table.Files), filepath.Dir(table.Files[0]))
+	}
+}
@@ -0,0 +1,330 @@
+// Package retention keeps the high-volume klima_chamber tables from filling
+// the disk of the chamber PC: rows older than the retention of their table
+// are archived to compressed files and then pruned, Loop_Data after it was
+// rolled up into hourly averages that are kept forever.
+package retention
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+// Struct to hold how many days the rows of each table are kept, 0 keeping
+// them forever, and the directory they are archived to before they are
+// pruned
+
+type Policy struct {
+	LoopDataDays    int
+	ActivityLogDays int
+	Dir             string
+}
+
+// Policy set by the LoopDataRetentionDays, ActivityLogRetentionDays and
+// ArchiveDir settings
+func PolicyOf(cfg config.Config) Policy {
+
+	return Policy{
+		LoopDataDays:    cfg.Log.LoopDataRetentionDays,
+		ActivityLogDays: cfg.Log.ActivityLogRetentionDays,
+		Dir:             cfg.ArchivePath(),
+	}
+}
+
+// Report whether the policy prunes any table
+func (policy Policy) Prunes() bool {
+
+	return policy.LoopDataDays > 0 || policy.ActivityLogDays > 0
+}
+
+// Struct to hold what Run pruned of a table: the rows older than Before and
+// the archive files they were written to
+
+type Pruned struct {
+	Table  string
+	Before model.Time
+	Rows   int64
+	Files  []string
+}
+
+// Struct to hold a table pruned by the policy. list returns the rows a dry
+// run reports, prune passes them to archive and deletes them.
+
+type table struct {
+	name   string
+	days   int
+	oldest func() (model.Time, error)
+	list   func(start model.Time, end model.Time) ([]interface{}, error)
+	prune  func(start model.Time, end model.Time, archive func([]interface{}) error) (int64, error)
+}
+
+// Archive and prune the rows of every table older than its retention at now,
+// one day at a time, oldest first. Loop_Data is pruned up to a whole hour,
+// so no hour is rolled up in parts. A dry run reports what would be pruned
+// and changes nothing.
+func Run(logStore store.Store, policy Policy, now time.Time, dryRun bool) ([]Pruned, error) {
+
+	tables := []table{
+		{
+			name:   "Loop_Data",
+			days:   policy.LoopDataDays,
+			oldest: logStore.OldestLoopData,
+			list: func(start model.Time, end model.Time) ([]interface{}, error) {
+				logs, err := logStore.ListLoopDataBetween(start, end)
+				rows := make([]interface{}, len(logs))
+				for i := range logs {
+					rows[i] = logs[i]
+				}
+				return rows, err
+			},
+			prune: func(start model.Time, end model.Time, archive func([]interface{}) error) (int64, error) {
+				return logStore.PruneLoopData(start, end, func(logs []model.Loop_Data) error {
+					rows := make([]interface{}, len(logs))
+					for i := range logs {
+						rows[i] = logs[i]
+					}
+					return archive(rows)
+				})
+			},
+		},
+		{
+			name:   "Activity_Log",
+			days:   policy.ActivityLogDays,
+			oldest: logStore.OldestActivity,
+			list: func(start model.Time, end model.Time) ([]interface{}, error) {
+				activities, err := logStore.ListActivities(start, end)
+				rows := make([]interface{}, len(activities))
+				for i := range activities {
+					rows[i] = activities[i]
+				}
+				return rows, err
+			},
+			prune: func(start model.Time, end model.Time, archive func([]interface{}) error) (int64, error) {
+				return logStore.PruneActivities(start, end, func(activities []store.Activity) error {
+					rows := make([]interface{}, len(activities))
+					for i := range activities {
+						rows[i] = activities[i]
+					}
+					return archive(rows)
+				})
+			},
+		},
+	}
+
+	var pruned []Pruned
+
+	for _, t := range tables {
+
+		if t.days <= 0 {
+			continue
+		}
+
+		before := model.NewTime(now.AddDate(0, 0, -t.days).Truncate(time.Hour))
+
+		result, err := t.run(policy.Dir, before, dryRun)
+
+		pruned = append(pruned, result)
+
+		if err != nil {
+			return pruned, fmt.Errorf("pruning %s: %w", t.name, err)
+		}
+	}
+
+	return pruned, nil
+}
+
+// Archive and prune the rows of the table older than before. The rows of a
+// day are archived and deleted by the store in one transaction, so a row
+// stored meanwhile is left for the next run, and the archive written is
+// removed again when the store fails to delete them.
+func (t table) run(dir string, before model.Time, dryRun bool) (Pruned, error) {
+
+	pruned := Pruned{Table: t.name, Before: before}
+
+	oldest, err := t.oldest()
+
+	if err == store.ErrNotFound || (err == nil && !oldest.Before(before.Time)) {
+		return pruned, nil
+	}
+
+	if err != nil {
+		return pruned, err
+	}
+
+	for day := oldest.Truncate(24 * time.Hour); day.Before(before.Time); day = day.Add(24 * time.Hour) {
+
+		start := model.NewTime(day)
+
+		end := before
+
+		if next := day.Add(24 * time.Hour); next.Before(before.Time) {
+			end = model.NewTime(next)
+		}
+
+		path := filepath.Join(dir, t.name+"-"+day.Format("2006-01-02")+".jsonl.gz")
+
+		if dryRun {
+
+			rows, err := t.list(start, end)
+
+			if err != nil {
+				return pruned, err
+			}
+
+			if len(rows) > 0 {
+				pruned.Files = append(pruned.Files, path)
+				pruned.Rows += int64(len(rows))
+			}
+
+			continue
+		}
+
+		var undo func()
+
+		count, err := t.prune(start, end, func(rows []interface{}) error {
+
+			var err error
+
+			undo, err = appendArchive(path, rows)
+
+			return err
+		})
+
+		if err != nil {
+
+			if undo != nil {
+				undo()
+			}
+
+			return pruned, err
+		}
+
+		if count > 0 {
+			pruned.Files = append(pruned.Files, path)
+			pruned.Rows += count
+		}
+	}
+
+	return pruned, nil
+}
+
+// Append the rows to the archive at path as JSON lines in a gzip member of
+// their own, creating the file and its directory when missing. A file
+// archived to again, e.g. by rows that arrived late, reads as all of its
+// rows with zcat or gzip.Reader. A failed write leaves the file as it was,
+// and so does undo, called when the rows were not pruned after all.
+func appendArchive(path string, rows []interface{}) (undo func(), err error) {
+
+	var archived bytes.Buffer
+
+	zipper := gzip.NewWriter(&archived)
+
+	encoder := json.NewEncoder(zipper)
+
+	for _, row := range rows {
+
+		err = encoder.Encode(row)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = zipper.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+
+	if err == nil {
+		_, err = file.Write(archived.Bytes())
+	}
+
+	if err == nil {
+		err = file.Sync()
+	}
+
+	if err != nil && info != nil {
+		file.Truncate(info.Size())
+	}
+
+	closeErr := file.Close()
+
+	if err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	undo = func() {
+
+		if info.Size() == 0 {
+			os.Remove(path)
+			return
+		}
+
+		os.Truncate(path, info.Size())
+	}
+
+	return undo, nil
+}
+
+// Struct to hold the policy applied by the log server, which may be replaced
+// while it runs
+
+type Job struct {
+	logStore store.Store
+
+	mu     sync.Mutex
+	policy Policy
+}
+
+// Create a Job applying the policy to the store
+func NewJob(logStore store.Store, policy Policy) *Job {
+
+	return &Job{logStore: logStore, policy: policy}
+}
+
+// Replace the policy applied from the next Run on
+func (job *Job) SetPolicy(policy Policy) {
+
+	job.mu.Lock()
+	defer job.mu.Unlock()
+
+	job.policy = policy
+}
+
+// Archive and prune the tables by the policy in use, see Run
+func (job *Job) Run(now time.Time) ([]Pruned, error) {
+
+	job.mu.Lock()
+	policy := job.policy
+	job.mu.Unlock()
+
+	return Run(job.logStore, policy, now, false)
+}
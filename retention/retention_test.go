@@ -0,0 +1,220 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store/memory"
+)
+
+// Struct to hold a store that archives Loop_Data and then fails to delete it
+
+type failingStore struct {
+	*memory.Store
+}
+
+func (s failingStore) PruneLoopData(start model.Time, end model.Time, archive func([]model.Loop_Data) error) (int64, error) {
+
+	logs, err := s.ListLoopDataBetween(start, end)
+
+	if err == nil {
+		err = archive(logs)
+	}
+
+	if err == nil {
+		err = errors.New("lock wait timeout exceeded")
+	}
+
+	return 0, err
+}
+
+// Read the rows of a gzip file of JSON lines
+func readArchive(t *testing.T, path string) []model.Loop_Data {
+
+	t.Helper()
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer file.Close()
+
+	unzipper, err := gzip.NewReader(file)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var logs []model.Loop_Data
+
+	lines := bufio.NewScanner(unzipper)
+
+	for lines.Scan() {
+
+		var log model.Loop_Data
+
+		if err = json.Unmarshal(lines.Bytes(), &log); err != nil {
+			t.Fatal(err)
+		}
+
+		logs = append(logs, log)
+	}
+
+	if err = lines.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return logs
+}
+
+func TestRun(t *testing.T) {
+
+	logStore := memory.New()
+
+	now := time.Date(2019, 4, 20, 10, 30, 0, 0, time.UTC)
+
+	// Two days older than 90 days, and one sample kept
+	for _, at := range []time.Time{
+		time.Date(2019, 1, 15, 23, 10, 0, 0, time.UTC),
+		time.Date(2019, 1, 15, 23, 50, 0, 0, time.UTC),
+		time.Date(2019, 1, 16, 0, 10, 0, 0, time.UTC),
+		now.AddDate(0, 0, -10),
+	} {
+
+		_, err := logStore.InsertLoopData(&model.Loop_Data{Dtpv: 20, Ddatatime: model.NewTime(at)}, false)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logStore.InsertActivity(5, "INSERT", "{}", 0)
+
+	policy := Policy{LoopDataDays: 90, ActivityLogDays: 0, Dir: filepath.Join(t.TempDir(), "archive")}
+
+	pruned, err := Run(logStore, policy, now, true)
+
+	if err != nil || len(pruned) != 1 || pruned[0].Rows != 3 || len(pruned[0].Files) != 2 {
+		t.Fatalf("dry run: %+v %v", pruned, err)
+	}
+
+	if logs, _ := logStore.ListLoopData(); len(logs) != 4 {
+		t.Fatalf("dry run pruned %d samples", 4-len(logs))
+	}
+
+	if _, err = os.Stat(policy.Dir); !os.IsNotExist(err) {
+		t.Fatalf("dry run wrote the archive: %v", err)
+	}
+
+	pruned, err = Run(logStore, policy, now, false)
+
+	if err != nil || len(pruned) != 1 || pruned[0].Rows != 3 {
+		t.Fatalf("Run: %+v %v", pruned, err)
+	}
+
+	if !pruned[0].Before.Equal(time.Date(2019, 1, 20, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("pruned before %s, want a whole hour", pruned[0].Before)
+	}
+
+	first := filepath.Join(policy.Dir, "Loop_Data-2019-01-15.jsonl.gz")
+
+	if logs := readArchive(t, first); len(logs) != 2 || !logs[0].Ddatatime.Equal(time.Date(2019, 1, 15, 23, 10, 0, 0, time.UTC)) {
+		t.Fatalf("archive %s holds %+v", first, logs)
+	}
+
+	if logs, _ := logStore.ListLoopData(); len(logs) != 1 {
+		t.Fatalf("%d samples kept, want 1", len(logs))
+	}
+
+	hours, _ := logStore.ListLoopDataHourly(model.Time{}, model.Time{})
+
+	if len(hours) != 2 || hours[0].Hsamples != 2 || hours[1].Hsamples != 1 {
+		t.Fatalf("rolled up %+v", hours)
+	}
+
+	// A sample of a day archived already is appended to its archive
+	_, err = logStore.InsertLoopData(&model.Loop_Data{Dtpv: 21, Ddatatime: model.NewTime(time.Date(2019, 1, 15, 12, 0, 0, 0, time.UTC))}, false)
+
+	if err == nil {
+		_, err = Run(logStore, policy, now, false)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if logs := readArchive(t, first); len(logs) != 3 || logs[2].Dtpv != 21 {
+		t.Fatalf("archive %s holds %+v", first, logs)
+	}
+
+	if activities := logStore.Activities(); len(activities) != 1 {
+		t.Fatal("Activity Log pruned with a retention of 0 days")
+	}
+}
+
+func TestRunFailedPrune(t *testing.T) {
+
+	logStore := memory.New()
+
+	now := time.Date(2019, 4, 20, 10, 30, 0, 0, time.UTC)
+
+	for _, at := range []time.Time{
+		time.Date(2019, 1, 15, 23, 10, 0, 0, time.UTC),
+		time.Date(2019, 1, 16, 0, 10, 0, 0, time.UTC),
+	} {
+
+		_, err := logStore.InsertLoopData(&model.Loop_Data{Dtpv: 20, Ddatatime: model.NewTime(at)}, false)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	policy := Policy{LoopDataDays: 90, Dir: filepath.Join(t.TempDir(), "archive")}
+
+	first := filepath.Join(policy.Dir, "Loop_Data-2019-01-15.jsonl.gz")
+
+	// The archive of rows that were not pruned is removed
+	if _, err := Run(failingStore{logStore}, policy, now, false); err == nil {
+		t.Fatal("Run with a failing prune succeeded")
+	}
+
+	if _, err := os.Stat(first); !os.IsNotExist(err) {
+		t.Fatalf("archive of rows not pruned kept: %v", err)
+	}
+
+	// and archived once when they are
+	pruned, err := Run(logStore, policy, now, false)
+
+	if err != nil || len(pruned) != 1 || pruned[0].Rows != 2 {
+		t.Fatalf("Run: %+v %v", pruned, err)
+	}
+
+	if logs := readArchive(t, first); len(logs) != 1 {
+		t.Fatalf("archive %s holds %+v", first, logs)
+	}
+
+	// A failing prune of a day archived already leaves its archive as it was
+	_, err = logStore.InsertLoopData(&model.Loop_Data{Dtpv: 21, Ddatatime: model.NewTime(time.Date(2019, 1, 15, 12, 0, 0, 0, time.UTC))}, false)
+
+	if err == nil {
+		_, err = Run(failingStore{logStore}, policy, now, false)
+	}
+
+	if err == nil {
+		t.Fatal("Run with a failing prune succeeded")
+	}
+
+	if logs := readArchive(t, first); len(logs) != 1 {
+		t.Fatalf("archive %s holds %+v after a failing prune", first, logs)
+	}
+}
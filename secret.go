@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+)
+
+// Create the key and encrypted value of an enc: DBPassword, e.g.
+//
+//	ngcslog secret keygen > ../../config/dbpassword.key
+//	ngcslog secret encrypt < password.txt
+//
+// encrypt reads the password from the first line of stdin, so it never shows
+// up in the shell history or the process list.
+func secret(args []string) {
+
+	flags := flag.NewFlagSet("secret", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ngcslog secret [flags] keygen | encrypt")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	switch flags.Arg(0) {
+
+	case "keygen":
+		key, err := config.GenerateKey()
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Println(key)
+
+	case "encrypt":
+		// Only DBPasswordKeyFile is needed, the rest may still be incomplete
+		cfg, _ := loader.Load()
+
+		key, err := config.ReadKey(cfg.KeyPath())
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err.Error())
+			os.Exit(1)
+		}
+
+		password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+
+		password = strings.TrimRight(password, "\r\n")
+
+		if password == "" || (err != nil && err != io.EOF) {
+			fmt.Fprintln(os.Stderr, "Error: No password on stdin.")
+			os.Exit(1)
+		}
+
+		value, err := config.Encrypt(password, key)
+
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Println(config.SecretEncPrefix + value)
+
+	default:
+		flags.Usage()
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Warn about every process value of the Loop Data that is further from its
+// setpoint than the alarm band of the NGCS Log Config. A band of 0 turns the
+// alarm off. Returns the names of the values in alarm.
+func checkAlarms(log *model.Loop_Data) []string {
+
+	logConfig := currentLogConfig()
+
+	var alarms []string
+
+	for _, value := range []struct {
+		name string
+		sp   float64
+		pv   float64
+		band float64
+	}{
+		{"temp_pv", log.Dtsp, log.Dtpv, logConfig.TempAlarmBand},
+		{"hum_pv", log.Dhsp, log.Dhpv, logConfig.HumAlarmBand},
+		{"press_pv", log.Dpsp, log.Dppv, logConfig.PressAlarmBand},
+	} {
+
+		deviation := value.pv - value.sp
+
+		if deviation < 0 {
+			deviation = -deviation
+		}
+
+		if value.band > 0 && deviation > value.band {
+
+			logf("warn", "Alarm at %s: %s %v is %v from its setpoint %v, more than %v.", log.Ddatatime, value.name, value.pv, deviation, value.sp, value.band)
+
+			alarms = append(alarms, value.name)
+		}
+	}
+
+	return alarms
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+// Header carrying the API key of a request
+const APIKeyHeader = "X-API-Key"
+
+// Context key of the caller of a request
+const callerKey = "caller"
+
+var apiKeys []config.APIKey
+
+var apiKeysMu sync.RWMutex
+
+// Replace the API keys while the router is serving requests. Without keys
+// requests are not authenticated and are stamped with user 0.
+func SetAPIKeys(keys []config.APIKey) {
+
+	apiKeysMu.Lock()
+	defer apiKeysMu.Unlock()
+
+	apiKeys = keys
+}
+
+// Caller of the API key, false when the key is unknown
+func lookupAPIKey(key string) (config.APIKey, bool) {
+
+	apiKeysMu.RLock()
+	defer apiKeysMu.RUnlock()
+
+	for _, apiKey := range apiKeys {
+
+		if subtle.ConstantTimeCompare([]byte(apiKey.Key), []byte(key)) == 1 {
+			return apiKey, true
+		}
+	}
+
+	return config.APIKey{}, false
+}
+
+func authenticationEnabled() bool {
+
+	apiKeysMu.RLock()
+	defer apiKeysMu.RUnlock()
+
+	return len(apiKeys) > 0
+}
+
+// Find the caller of a request from its X-API-Key header, or an
+// "Authorization: Bearer" header. Responds 401 when keys are configured and
+// the request has no known key; the OpenAPI document is served to anyone.
+func authenticate(c *gin.Context) {
+
+	if !authenticationEnabled() || c.Request.URL.Path == "/openapi.json" {
+		c.Next()
+		return
+	}
+
+	key := c.GetHeader(APIKeyHeader)
+
+	if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && key == "" {
+		key = strings.TrimSpace(bearer)
+	}
+
+	caller, ok := lookupAPIKey(key)
+
+	if !ok {
+
+		logf("warn", "Rejected %s %s from %s: API key missing or unknown.", c.Request.Method, c.Request.URL.Path, c.ClientIP())
+
+		respondError(c, http.StatusUnauthorized, FieldError{Field: APIKeyHeader, Reason: "API key missing or unknown"})
+		c.Abort()
+		return
+	}
+
+	c.Set(callerKey, caller)
+
+	c.Next()
+}
+
+// User id the request was sent by, 0 when requests are not authenticated
+func callerId(c *gin.Context) int {
+
+	if caller, ok := c.Get(callerKey); ok {
+		return caller.(config.APIKey).UserId
+	}
+
+	return 0
+}
+
+// Stamp a new record with the caller and the server time
+func stampCreated(c *gin.Context, record model.Stamped) {
+
+	record.StampCreated(callerId(c), model.Now())
+}
+
+// Stamp a changed record with the caller and the server time
+func stampModified(c *gin.Context, record model.Stamped) {
+
+	record.StampModified(callerId(c), model.Now())
+}
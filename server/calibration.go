@@ -0,0 +1,127 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+func processCalibrationInsert(c *gin.Context) {
+
+	var calibration model.Calibration
+
+	if !bindLog(c, &calibration) {
+		return
+	}
+
+	if calibration.Calgain == 0 {
+		calibration.Calgain = 1
+	}
+
+	err := model.ValidateCalibration(calibration)
+
+	if err != nil {
+		respondError(c, http.StatusBadRequest, FieldError{Field: "due_date", Value: calibration.Calduedate, Reason: err.Error()})
+		return
+	}
+
+	stampCreated(c, &calibration)
+
+	id, err := logStore.InsertCalibration(&calibration)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"ZTK_Chamber_id":   calibration.Calchamberid,
+		"channel":          calibration.Calchannel,
+		"offset":           calibration.Caloffset,
+		"gain":             calibration.Calgain,
+		"calibration_date": calibration.Caldate,
+		"due_date":         calibration.Calduedate,
+		"certificate":      calibration.Calcertificate,
+		"created":          calibration.Calcreated,
+		"created_by":       calibration.Calcreatedby,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "INSERT", string(datat))
+}
+
+// List the calibrations in force at the query parameter at, default now,
+// that are past their due date
+func processCalibrationExpired(c *gin.Context) {
+
+	at, err := model.ParseTime(c.Query("at"))
+
+	if err != nil {
+		respondError(c, http.StatusBadRequest, FieldError{Field: "at", Value: c.Query("at"), Reason: "must be a date time like " + model.TimeExample})
+		return
+	}
+
+	if at.IsZero() {
+		at = model.Now()
+	}
+
+	calibrations, err := logStore.ListCalibrations()
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, nil, model.ExpiredCalibrations(calibrations, at))
+}
+
+// Correct the values of a Loop_Data sample by the calibrations of its
+// chamber. Responds and returns false when they cannot be read, or with 422
+// when a corrected value is outside the range of its column.
+func calibrateLoopData(c *gin.Context, log *model.Loop_Data) bool {
+
+	calibrations, err := logStore.ListChamberCalibrations(log.Dchamberid)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return false
+	}
+
+	log.Calibrate(calibrations)
+
+	err = binding.Validator.ValidateStruct(log)
+
+	if err != nil {
+
+		fieldErrors := requestErrors(err)
+
+		for i := range fieldErrors {
+			fieldErrors[i].Reason = "calibrated " + fieldErrors[i].Reason
+		}
+
+		logf("warn", "Invalid %s %s: %v", c.Request.Method, c.Request.URL.Path, fieldErrors)
+
+		respondError(c, http.StatusUnprocessableEntity, fieldErrors...)
+
+		return false
+	}
+
+	for channel, value := range log.Dcalibrated {
+
+		for _, calibration := range calibrations {
+
+			if calibration.Calid == value.Vcalibrationid && calibration.Expired(log.Ddatatime) {
+				logf("warn", "Calibration %d of %s of ZTK_Chamber_id %d expired on %s.", calibration.Calid, channel, log.Dchamberid, calibration.Calduedate)
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Readers of the records of one chamber, keyed by the route name under
+// /Chamber/:id
+var chamberReaders = map[string]func(id int) (interface{}, error){
+	"Logs_Event":       func(id int) (interface{}, error) { return logStore.ListChamberEventLogs(id) },
+	"Logs_Test":        func(id int) (interface{}, error) { return logStore.ListChamberTestLogs(id) },
+	"Logs_Maintenance": func(id int) (interface{}, error) { return logStore.ListChamberMaintenanceLogs(id) },
+	"Io_card_info":     func(id int) (interface{}, error) { return logStore.ListChamberIocardinfo(id) },
+	"Calibration":      func(id int) (interface{}, error) { return logStore.ListChamberCalibrations(id) },
+}
+
+func processChamberInsert(c *gin.Context) {
+
+	var chamber model.Chamber
+
+	if !bindLog(c, &chamber) {
+		return
+	}
+
+	stampCreated(c, &chamber)
+
+	id, err := logStore.InsertChamber(&chamber)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"name":       chamber.Cname,
+		"location":   chamber.Clocation,
+		"time_zone":  chamber.Ctimezone,
+		"io_cards":   chamber.Ciocards,
+		"created":    chamber.Ccreated,
+		"created_by": chamber.Ccreatedby,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "INSERT", string(datat))
+}
+
+func processChamberGet(c *gin.Context) {
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	chamber, err := logStore.GetChamber(id)
+
+	if !respondChamberFound(c, id, err) {
+		return
+	}
+
+	respondOK(c, http.StatusOK, nil, chamber)
+}
+
+// Rename, move or refit a chamber. The IO cards sent replace the ones fitted.
+func processChamberUpdate(c *gin.Context) {
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	var chamber model.Chamber
+
+	if !bindLog(c, &chamber) {
+		return
+	}
+
+	stampModified(c, &chamber)
+
+	err := logStore.UpdateChamber(id, &chamber)
+
+	if !respondTypeChange(c, "chamber", id, err) {
+		return
+	}
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"id":          id,
+		"name":        chamber.Cname,
+		"location":    chamber.Clocation,
+		"time_zone":   chamber.Ctimezone,
+		"io_cards":    chamber.Ciocards,
+		"modified":    chamber.Cmodified,
+		"modified_by": chamber.Cmodifiedby,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "UPDATE", string(datat))
+}
+
+// Read the records of one chamber from the table served at /name, chamber 0
+// being the records not assigned to one
+func processChamberRead(name string) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		id, ok := typeId(c)
+
+		if !ok {
+			return
+		}
+
+		if !respondChamberReadable(c, id) {
+			return
+		}
+
+		logs, err := chamberReaders[name](id)
+
+		if err != nil {
+			respondStoreError(c, err)
+			return
+		}
+
+		respondOK(c, http.StatusOK, nil, logs)
+	}
+}
+
+// List the Loop_Data of one chamber captured at or after the query parameter
+// start and before end, oldest first; either may be left out. start and end
+// given without a zone are in the zone of the chamber.
+func processChamberLoopData(c *gin.Context) {
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	start, end, ok := loopDataBounds(c)
+
+	if !ok {
+		return
+	}
+
+	if !respondChamberReadable(c, id) {
+		return
+	}
+
+	loc := chamberLocation(id)
+	start, end = start.InZone(loc), end.InZone(loc)
+
+	logs, err := logStore.ListChamberLoopData(id, start, end)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, nil, logs)
+}
+
+// List the hourly Loop_Data of one chamber between start and end, as
+// processLoopDataHourly
+func processChamberLoopDataHourly(c *gin.Context) {
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	start, end, ok := loopDataBounds(c)
+
+	if !ok {
+		return
+	}
+
+	if !respondChamberReadable(c, id) {
+		return
+	}
+
+	loc := chamberLocation(id)
+	start, end = start.InZone(loc), end.InZone(loc)
+
+	hours, err := logStore.ListChamberLoopDataHourly(id, start, end)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, nil, hours)
+}
+
+// Respond 404 when the records of the chamber cannot be read as it is not
+// registered, and report whether they can. Chamber 0 holds the records not
+// assigned to a chamber and is not registered.
+func respondChamberReadable(c *gin.Context, id int) bool {
+
+	if id == 0 {
+		return true
+	}
+
+	_, err := logStore.GetChamber(id)
+
+	return respondChamberFound(c, id, err)
+}
+
+// Respond 404 when the chamber read from the store does not exist, and
+// report whether it was found.
+func respondChamberFound(c *gin.Context, id int, err error) bool {
+
+	if err == store.ErrNotFound {
+		respondError(c, http.StatusNotFound, FieldError{Field: "id", Value: id, Reason: "no such chamber"})
+		return false
+	}
+
+	if err != nil {
+		respondStoreError(c, err)
+		return false
+	}
+
+	return true
+}
+
+// Zone of the date times the chamber sends without one: its TimeZone, or the
+// TimeZone of the server for chamber 0 and for a chamber without one or not
+// registered
+func chamberLocation(id int) *time.Location {
+
+	if id == 0 {
+		return model.Location()
+	}
+
+	chamber, err := logStore.GetChamber(id)
+
+	if err != nil || chamber.Ctimezone == "" {
+		return model.Location()
+	}
+
+	loc, err := time.LoadLocation(chamber.Ctimezone)
+
+	if err != nil {
+		return model.Location()
+	}
+
+	return loc
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+func processChannelInsert(c *gin.Context) {
+
+	var channel model.Channel
+
+	if !bindChannel(c, &channel) {
+		return
+	}
+
+	stampCreated(c, &channel)
+
+	id, err := logStore.InsertChannel(&channel)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"name":        channel.Chname,
+		"unit":        channel.Chunit,
+		"minimum":     channel.Chminimum,
+		"maximum":     channel.Chmaximum,
+		"description": channel.Chdescription,
+		"created":     channel.Chcreated,
+		"created_by":  channel.Chcreatedby,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "INSERT", string(datat))
+}
+
+// Bind a channel and check its range, responding 400 when it is invalid
+func bindChannel(c *gin.Context, channel *model.Channel) bool {
+
+	if !bindLog(c, channel) {
+		return false
+	}
+
+	err := model.ValidateChannel(*channel)
+
+	if err != nil {
+		respondError(c, http.StatusBadRequest, FieldError{Field: "maximum", Value: *channel.Chmaximum, Reason: err.Error()})
+		return false
+	}
+
+	return true
+}
+
+func processChannelByName(c *gin.Context) {
+
+	channel, err := logStore.GetChannelByName(c.Params.ByName("name"))
+
+	respondTypeByName(c, "channel", channel, err)
+}
+
+// Rename a channel or change its unit, range or description
+func processChannelUpdate(c *gin.Context) {
+
+	var channel model.Channel
+
+	if !bindChannel(c, &channel) {
+		return
+	}
+
+	stampModified(c, &channel)
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	err := logStore.UpdateChannel(id, &channel)
+
+	if respondTypeChange(c, "channel", id, err) {
+
+		datat, _ := json.Marshal(channel)
+
+		recordActivity(c, "UPDATE", string(datat))
+	}
+}
+
+func processChannelRetire(c *gin.Context) {
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	err := logStore.RetireChannel(id)
+
+	if respondTypeChange(c, "channel", id, err) {
+		recordActivity(c, "RETIRE", fmt.Sprintf("{\"ZTK_Channel_id\":%d}", id))
+	}
+}
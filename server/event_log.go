@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+func processEvent_Log(c *gin.Context) {
+
+	var log model.Logs_Event
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampCreated(c, &log)
+
+	id, err := logStore.InsertEventLog(&log, currentLogConfig().AutoCreateTypes == 1)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"log_id":                 log.Lid,
+		"program_name":           log.Pname,
+		"program_date_time":      log.Pdatetime,
+		"ZTK_Logs_Event_Type_id": log.Etypeid,
+		"ZTK_Chamber_id":         log.Echamberid,
+		"ZTK_Users_id":           log.Eid,
+		"created_by":             log.Createdby,
+		"created":                log.Ecreated,
+		"modified_by":            log.Modifiedby,
+		"modified ":              log.Emodified,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "INSERT", string(datat))
+}
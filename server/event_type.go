@@ -0,0 +1,90 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+func processEvent_typeLog(c *gin.Context) {
+
+	var log model.Logs_Event_Type
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampCreated(c, &log)
+
+	id, err := logStore.InsertEventType(&log)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	// Activity log
+	totaldata := map[string]interface{}{
+		"events_type": log.Levents,
+		"created":     log.Lcreated,
+		"modified":    log.Lmodified,
+		"created_by":  log.Lcreated1,
+		"modified_by": log.Lmodified2,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "INSERT", string(datat))
+}
+
+func processEvent_typeByName(c *gin.Context) {
+
+	log, err := logStore.GetEventTypeByName(c.Params.ByName("name"))
+
+	respondTypeByName(c, "events_type", log, err)
+}
+
+func processEvent_typeUpdate(c *gin.Context) {
+
+	var log model.Logs_Event_Type
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampModified(c, &log)
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	err := logStore.UpdateEventType(id, &log)
+
+	if respondTypeChange(c, "events_type", id, err) {
+
+		datat, _ := json.Marshal(log)
+
+		recordActivity(c, "UPDATE", string(datat))
+	}
+}
+
+func processEvent_typeRetire(c *gin.Context) {
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	err := logStore.RetireEventType(id)
+
+	if respondTypeChange(c, "events_type", id, err) {
+		recordActivity(c, "RETIRE", fmt.Sprintf("{\"ZTK_Logs_Event_Type_id\":%d}", id))
+	}
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/client"
+	"github.com/gin-gonic/gin"
+)
+
+// Struct to hold a request stored locally that is still to be sent to the
+// NGCS Remote Log Server
+
+type forwardRequest struct {
+	server string
+	apiKey string
+	method string
+	path   string
+	body   []byte
+}
+
+// Requests waiting to be forwarded, sent one at a time in the order they were
+// stored. When the remote server falls this far behind requests are dropped.
+var forwardQueue = make(chan forwardRequest, 1000)
+
+var forwardOnce sync.Once
+
+// Context keys of the request body and path to forward in place of the ones
+// received
+const (
+	forwardBodyKey = "forwardBody"
+	forwardPathKey = "forwardPath"
+)
+
+// Send every request that changes the store on to the NGCS Remote Log Server
+// when LogRemotely is 1. Read requests and requests that failed are not sent.
+func forwardRemotely(c *gin.Context) {
+
+	logConfig := currentLogConfig()
+
+	if logConfig.LogRemotely != 1 || c.Request.Method == http.MethodGet {
+		c.Next()
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+
+	if err != nil {
+		respondError(c, http.StatusBadRequest, FieldError{Reason: "request body cannot be read"})
+		c.Abort()
+		return
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	c.Next()
+
+	if c.Writer.Status() >= http.StatusBadRequest {
+		return
+	}
+
+	if bound, ok := c.Get(forwardBodyKey); ok {
+		body = bound.([]byte)
+	}
+
+	path := c.Request.URL.RequestURI()
+
+	if rewritten, ok := c.Get(forwardPathKey); ok {
+		path = rewritten.(string)
+	}
+
+	forwardOnce.Do(func() {
+		go forwardRequests()
+	})
+
+	request := forwardRequest{
+		server: "http://" + logConfig.RemoteLogServerConnectStr(),
+		apiKey: logConfig.RemoteLogServerAPIKey,
+		method: c.Request.Method,
+		path:   path,
+		body:   body,
+	}
+
+	select {
+	case forwardQueue <- request:
+	default:
+		logf("error", "Forwarding queue is full, %s %s is not sent to the Remote Log Server.", request.method, request.server+request.path)
+	}
+}
+
+func forwardRequests() {
+
+	for request := range forwardQueue {
+
+		err := sendRemotely(request)
+
+		if err != nil {
+			logf("error", "Forwarding %s %s to the Remote Log Server: %s", request.method, request.server+request.path, err.Error())
+			continue
+		}
+
+		logf("debug", "Forwarded %s %s to the Remote Log Server.", request.method, request.server+request.path)
+	}
+}
+
+func sendRemotely(request forwardRequest) error {
+
+	remote := client.New(request.server)
+	remote.APIKey = request.apiKey
+	remote.HTTPClient.Timeout = 10 * time.Second
+
+	resp, _, err := remote.Do(request.method, request.path, request.body)
+
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Header of a request that must change the store only once, however often
+// it is sent
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Header set on a response replayed for an Idempotency-Key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// How long the response to an Idempotency-Key is replayed
+const idempotencyKeyTTL = 24 * time.Hour
+
+// Longest Idempotency-Key, the size of its column
+const maxIdempotencyKey = 255
+
+var (
+	idempotencyMu sync.Mutex
+
+	// Keys of the requests being processed, by user
+	inProgress = map[string]bool{}
+
+	lastIdempotencyPrune time.Time
+)
+
+// Struct to hold a gin.ResponseWriter that keeps a copy of the body written
+
+type recordingWriter struct {
+	gin.ResponseWriter
+
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+
+	w.body.Write(data)
+
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+
+	w.body.WriteString(s)
+
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Answer a request sent again with the Idempotency-Key of a request that
+// succeeded with the response to the first one, without storing it again.
+// A key is per caller and is kept for 24 hours. A key sent with a different
+// request is answered 422, and a key whose first request is still being
+// processed 409. Failed requests stored nothing and are processed again.
+func idempotent(c *gin.Context) {
+
+	key := c.GetHeader(IdempotencyKeyHeader)
+
+	if key == "" || c.Request.Method == http.MethodGet {
+		c.Next()
+		return
+	}
+
+	if len(key) > maxIdempotencyKey {
+		respondError(c, http.StatusBadRequest, FieldError{Field: IdempotencyKeyHeader, Reason: "must be at most " + strconv.Itoa(maxIdempotencyKey) + " characters"})
+		c.Abort()
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+
+	if err != nil {
+		respondError(c, http.StatusBadRequest, FieldError{Reason: "request body cannot be read"})
+		c.Abort()
+		return
+	}
+
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	userId := callerId(c)
+	path := c.Request.URL.RequestURI()
+	hash := requestHash(c.Request.Method, path, body)
+
+	pruneIdempotentResponses()
+
+	if !claimIdempotencyKey(userId, key) {
+		respondError(c, http.StatusConflict, FieldError{Field: IdempotencyKeyHeader, Value: key, Reason: "is used by a request still being processed"})
+		c.Abort()
+		return
+	}
+
+	defer releaseIdempotencyKey(userId, key)
+
+	stored, err := logStore.GetIdempotentResponse(userId, key)
+
+	switch {
+
+	case err == nil && stored.RequestHash != hash:
+		respondError(c, http.StatusUnprocessableEntity, FieldError{Field: IdempotencyKeyHeader, Value: key, Reason: "was sent before with a different request, " + stored.Method + " " + stored.Path})
+		c.Abort()
+		return
+
+	case err == nil:
+		logf("info", "Replayed %s %s for Idempotency-Key %q.", c.Request.Method, path, key)
+
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(stored.StatusCode, "application/json; charset=utf-8", stored.Body)
+		c.Abort()
+		return
+
+	case err != store.ErrNotFound:
+		respondStoreError(c, err)
+		c.Abort()
+		return
+	}
+
+	recorder := &recordingWriter{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	c.Next()
+
+	if recorder.Status() >= http.StatusMultipleChoices {
+		return
+	}
+
+	err = logStore.InsertIdempotentResponse(&store.IdempotentResponse{
+		UserId:      userId,
+		Key:         key,
+		Method:      c.Request.Method,
+		Path:        path,
+		RequestHash: hash,
+		StatusCode:  recorder.Status(),
+		Body:        recorder.body.Bytes(),
+		Created:     model.Now(),
+	})
+
+	// The request is stored already, a retry will store it again
+	if err != nil {
+		logf("error", "Storing the response to Idempotency-Key %q: %s", key, err.Error())
+	}
+}
+
+// Hash telling a retry of a request from another request with the same key
+func requestHash(method string, path string, body []byte) string {
+
+	hash := sha256.New()
+
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Mark the key of the user as being processed, false when it is already
+func claimIdempotencyKey(userId int, key string) bool {
+
+	idempotencyMu.Lock()
+	defer idempotencyMu.Unlock()
+
+	id := strconv.Itoa(userId) + " " + key
+
+	if inProgress[id] {
+		return false
+	}
+
+	inProgress[id] = true
+
+	return true
+}
+
+func releaseIdempotencyKey(userId int, key string) {
+
+	idempotencyMu.Lock()
+	defer idempotencyMu.Unlock()
+
+	delete(inProgress, strconv.Itoa(userId)+" "+key)
+}
+
+// Forget the responses of expired keys, at most once an hour
+func pruneIdempotentResponses() {
+
+	idempotencyMu.Lock()
+
+	due := time.Since(lastIdempotencyPrune) >= time.Hour
+
+	if due {
+		lastIdempotencyPrune = time.Now()
+	}
+
+	idempotencyMu.Unlock()
+
+	if !due {
+		return
+	}
+
+	deleted, err := logStore.DeleteIdempotentResponses(model.NewTime(time.Now().Add(-idempotencyKeyTTL)))
+
+	if err != nil {
+		logf("error", "Deleting expired Idempotency-Keys: %s", err.Error())
+		return
+	}
+
+	logf("debug", "Deleted %d expired Idempotency-Keys.", deleted)
+}
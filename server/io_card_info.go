@@ -0,0 +1,50 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+func processIocardinfo(c *gin.Context) {
+
+	var log model.Io_card_Info
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampCreated(c, &log)
+
+	logf("debug", "%v", log)
+
+	id, err := logStore.InsertIocardinfo(&log)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"card_address":       log.Iaddress,
+		"card_type":          log.Itype,
+		"card_version":       log.Iversion,
+		"card_serial_number": log.Inumber,
+		"secret_key":         log.Ikey,
+		"customer_id":        log.Iid,
+		"mfg_date":           log.Idate,
+		"created":            log.Icreated,
+		"modified ":          log.Imodified,
+		"created_by ":        log.Icreatedby,
+		"modified_by ":       log.Imodifiedby,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "INSERT", string(datat))
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/gin-gonic/gin"
+)
+
+// Prefix of the server messages of each LogLevel
+var logPrefixes = map[string]string{
+	"error": "Error: ",
+	"warn":  "Warning: ",
+	"info":  "",
+	"debug": "Debug: ",
+}
+
+// Whether messages of level are printed at the LogLevel in use
+func logEnabled(level string) bool {
+
+	current := currentLogConfig().Level()
+
+	for _, l := range config.LogLevels {
+
+		if l == level {
+			return true
+		}
+
+		if l == current {
+			return false
+		}
+	}
+
+	return false
+}
+
+// Print a server message when its level is enabled
+func logf(level string, format string, args ...interface{}) {
+
+	if logEnabled(level) {
+		fmt.Println(logPrefixes[level] + fmt.Sprintf(format, args...))
+	}
+}
+
+// Request log of the router, printed at LogLevel info and debug
+func Logger() gin.HandlerFunc {
+
+	return gin.LoggerWithConfig(gin.LoggerConfig{
+		Skip: func(c *gin.Context) bool {
+			return !logEnabled("info")
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+	"github.com/gin-gonic/gin"
+)
+
+func processAll_Logs(c *gin.Context) {
+
+	var log model.Logs_All
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampCreated(c, &log)
+
+	ids, err := logStore.InsertAll(&log, currentLogConfig().AutoCreateTypes == 1)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, allIds(ids), nil)
+
+	// Activity log
+
+	datat, _ := json.Marshal(log)
+
+	recordActivity(c, "INSERT", string(datat))
+}
+
+// Ids generated for the parts of a Logs_All document that were present
+func allIds(ids store.AllIds) gin.H {
+
+	response := gin.H{}
+
+	if ids.EventId != 0 {
+		response["ZTK_Logs_Event_Type_id"] = ids.EventTypeId
+		response["ZTK_Logs_Event_id"] = ids.EventId
+	}
+
+	if ids.TestId != 0 {
+		response["ZTK_Logs_Test_Type_id"] = ids.TestTypeId
+		response["ZTK_Logs_Test_id"] = ids.TestId
+	}
+
+	if ids.MaintenanceId != 0 {
+		response["ZTK_Logs_Maintenance_id"] = ids.MaintenanceId
+	}
+
+	return response
+}
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+// Store the sample of the chamber at the date time, replacing the one it
+// sent before for that date time
+func processLoopDataCreateOrUpdate(c *gin.Context) {
+
+	dateTime, err := model.ParseTime(c.Params.ByName("date_time_date"))
+
+	if err != nil {
+		respondError(c, http.StatusBadRequest, FieldError{Field: "date_time_date", Value: c.Params.ByName("date_time_date"), Reason: "must be a date time like " + model.TimeExample})
+		return
+	}
+
+	var log model.Loop_Data
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	dateTime = dateTime.InZone(chamberLocation(log.Dchamberid))
+
+	logf("debug", "Loop Data at %s", dateTime)
+
+	c.Set(forwardPathKey, "/Loop_Data/"+url.PathEscape(dateTime.String()))
+
+	if !log.Ddatatime.Equal(dateTime.Time) {
+		respondError(c, http.StatusBadRequest, FieldError{Field: "date_time_date", Value: log.Ddatatime.String(), Reason: "must be the date time of the URL, " + dateTime.String()})
+		return
+	}
+
+	logf("debug", "%v", log)
+
+	if !calibrateLoopData(c, &log) {
+		return
+	}
+
+	id, created, err := logStore.UpsertLoopData(&log, currentLogConfig().AutoCreateTypes == 1)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	checkAlarms(&log)
+
+	if !created {
+		respondOK(c, http.StatusOK, log.Ddatatime, nil)
+
+		recordLoopDataActivity(c, "UPDATE", &log)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	recordLoopDataActivity(c, "INSERT", &log)
+}
+
+func processLoopDataInsert(c *gin.Context) {
+
+	var log model.Loop_Data
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	insertLoopData(c, &log)
+}
+
+func insertLoopData(c *gin.Context, log *model.Loop_Data) {
+
+	logf("debug", "%v", *log)
+
+	if !calibrateLoopData(c, log) {
+		return
+	}
+
+	id, err := logStore.InsertLoopData(log, currentLogConfig().AutoCreateTypes == 1)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	checkAlarms(log)
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	recordLoopDataActivity(c, "INSERT", log)
+}
+
+// Record the sample in the activity log, as an INSERT or as an UPDATE of the
+// sample it replaced
+func recordLoopDataActivity(c *gin.Context, action string, log *model.Loop_Data) {
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"temp_sp":        log.Dtsp,
+		"temp_pv":        log.Dtpv,
+		"hum_sp":         log.Dhsp,
+		"hum_pv":         log.Dhpv,
+		"press_sp":       log.Dpsp,
+		"press_pv":       log.Dppv,
+		"date_time":      log.Ddatatime,
+		"ZTK_Chamber_id": log.Dchamberid,
+		"channels":       log.Dchannels,
+		"calibrated":     log.Dcalibrated,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, action, string(datat))
+}
+
+// List the Loop_Data captured at or after the query parameter start and
+// before end, oldest first; either may be left out.
+func processLoopDataList(c *gin.Context) {
+
+	start, end, ok := loopDataBounds(c)
+
+	if !ok {
+		return
+	}
+
+	logs, err := logStore.ListLoopDataBetween(start, end)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, nil, logs)
+}
+
+// List the hourly Loop_Data rolled up by the retention policy of the hours at
+// or after the query parameter start and before end, oldest first; either
+// may be left out.
+func processLoopDataHourly(c *gin.Context) {
+
+	start, end, ok := loopDataBounds(c)
+
+	if !ok {
+		return
+	}
+
+	hours, err := logStore.ListLoopDataHourly(start, end)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, nil, hours)
+}
+
+// Dates of the query parameters start and end, zero when left out. Responds
+// 400 and returns false when one is not a date time.
+func loopDataBounds(c *gin.Context) (model.Time, model.Time, bool) {
+
+	var bounds [2]model.Time
+
+	for i, name := range []string{"start", "end"} {
+
+		value, err := model.ParseTime(c.Query(name))
+
+		if err != nil {
+			respondError(c, http.StatusBadRequest, FieldError{Field: name, Value: c.Query(name), Reason: "must be a date time like " + model.TimeExample})
+			return model.Time{}, model.Time{}, false
+		}
+
+		bounds[i] = value
+	}
+
+	return bounds[0], bounds[1], true
+}
+
+// Load the Loop_Data the chamber captured between start and end, oldest
+// first. Chamber 0 is a server logging a single chamber, all of its
+// Loop_Data is used.
+func getLoopData(chamberId int, start time.Time, end time.Time) ([]model.Loop_Data, error) {
+
+	if chamberId == 0 {
+		return logStore.ListLoopDataBetween(model.NewTime(start), model.NewTime(end))
+	}
+
+	return logStore.ListChamberLoopData(chamberId, model.NewTime(start), model.NewTime(end))
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+func processMaintenance_Log(c *gin.Context) {
+
+	var log model.Logs_Maintenance
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampCreated(c, &log)
+
+	id, err := logStore.InsertMaintenanceLog(&log)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"component_name":      log.Mname,
+		"runtime_hr":          log.Mruntime,
+		"counter":             log.Mcounter,
+		"days_till_service":   log.Mservice,
+		"maintenance_pending": log.Mpending,
+		"maintenance_status":  log.Mstatus,
+		"ZTK_Chamber_id":      log.Mchamberid,
+		"created":             log.Mcreated,
+		"modified":            log.Mmodified,
+		"created_by ":         log.Mcreatedby,
+		"modified_by ":        log.Mmodifiedby,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "INSERT", string(datat))
+}
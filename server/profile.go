@@ -0,0 +1,101 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+func processTest_ProfileInsert(c *gin.Context) {
+
+	typeId, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	var profile model.Logs_Test_Profile
+
+	if !bindLog(c, &profile) {
+		return
+	}
+
+	stampCreated(c, &profile)
+
+	profile.Ptypeid = typeId
+
+	err := model.ValidateTestProfile(profile)
+
+	if err != nil {
+		respondError(c, http.StatusBadRequest, FieldError{Field: "steps", Reason: err.Error()})
+		return
+	}
+
+	profile.Pid, profile.Pversion, err = logStore.InsertTestProfile(&profile)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, profile.Pid, profile)
+
+	// Activity log
+
+	datat, _ := json.Marshal(profile)
+
+	recordActivity(c, "INSERT", string(datat))
+}
+
+func processTest_ProfileGet(c *gin.Context) {
+
+	var version int
+
+	typeId, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	if c.Query("version") != "" {
+
+		var err error
+
+		version, err = strconv.Atoi(c.Query("version"))
+
+		if err != nil {
+			respondError(c, http.StatusBadRequest, FieldError{Field: "version", Value: c.Query("version"), Reason: "is not a number"})
+			return
+		}
+	}
+
+	profile, err := logStore.GetTestProfile(typeId, version)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, profile.Pid, profile)
+}
+
+func processTest_ProfileVersions(c *gin.Context) {
+
+	typeId, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	logs, err := logStore.ListTestProfiles(typeId)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, nil, logs)
+}
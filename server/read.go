@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Readers for every table served by the read routes, keyed by route name
+var tableReaders = map[string]func() (interface{}, error){
+	"Logs_Event":       func() (interface{}, error) { return logStore.ListEventLogs() },
+	"Logs_Event_Type":  func() (interface{}, error) { return logStore.ListEventTypes() },
+	"Logs_Test":        func() (interface{}, error) { return logStore.ListTestLogs() },
+	"Logs_Test_Type":   func() (interface{}, error) { return logStore.ListTestTypes() },
+	"Logs_Maintenance": func() (interface{}, error) { return logStore.ListMaintenanceLogs() },
+	"Loop_Data":        func() (interface{}, error) { return logStore.ListLoopData() },
+	"Io_card_info":     func() (interface{}, error) { return logStore.ListIocardinfo() },
+	"Chamber":          func() (interface{}, error) { return logStore.ListChambers() },
+	"Channel":          func() (interface{}, error) { return logStore.ListChannels() },
+	"Calibration":      func() (interface{}, error) { return logStore.ListCalibrations() },
+}
+
+// Names accepted by ReadTable, sorted
+func TableNames() []string {
+
+	var names []string
+
+	for name := range tableReaders {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Read every row of the table served at /name
+func ReadTable(name string) (interface{}, error) {
+
+	reader, ok := tableReaders[name]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown table %q", name)
+	}
+
+	return reader()
+}
+
+func processTableRead(name string) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+
+		logs, err := ReadTable(name)
+
+		if err != nil {
+			respondStoreError(c, err)
+			return
+		}
+
+		respondOK(c, http.StatusOK, nil, logs)
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Values of Response.Status
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Struct to hold the response of every route. Id is the id of the row a
+// request created or changed, Data what a read returns and Errors what was
+// wrong with a request that failed. The HTTP status code tells the same as
+// Status: 2xx for "ok", 4xx for a request that cannot be stored as sent and
+// 5xx for a failure of the server.
+
+type Response struct {
+	Status string       `json:"status"`
+	Id     interface{}  `json:"id,omitempty"`
+	Data   interface{}  `json:"data,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// Struct to hold what was wrong with a request. Field is empty when the
+// error is not about one field.
+
+type FieldError struct {
+	Field  string      `json:"field,omitempty"`
+	Value  interface{} `json:"value,omitempty"`
+	Reason string      `json:"reason"`
+}
+
+// Respond to a request that succeeded
+func respondOK(c *gin.Context, code int, id interface{}, data interface{}) {
+
+	c.JSON(code, Response{Status: StatusOK, Id: id, Data: data})
+}
+
+// Respond to a request that failed
+func respondError(c *gin.Context, code int, errs ...FieldError) {
+
+	c.JSON(code, Response{Status: StatusError, Errors: errs})
+}
+
+// Respond to a request whose store call failed: 422 when the store rejected
+// the request, 404 when the row does not exist and 500 otherwise.
+func respondStoreError(c *gin.Context, err error) {
+
+	switch {
+
+	case store.IsInvalid(err):
+		respondError(c, http.StatusUnprocessableEntity, FieldError{Reason: err.Error()})
+
+	case err == store.ErrNotFound:
+		respondError(c, http.StatusNotFound, FieldError{Reason: "no such row"})
+
+	default:
+		logf("error", "%s %s: %s", c.Request.Method, c.Request.URL.Path, err.Error())
+
+		respondError(c, http.StatusInternalServerError, FieldError{Reason: "the request could not be stored, see the server log"})
+	}
+}
+
+// Respond 404 to a route that does not exist
+func processNoRoute(c *gin.Context) {
+
+	respondError(c, http.StatusNotFound, FieldError{Reason: "no route " + c.Request.Method + " " + c.Request.URL.Path})
+}
+
+// Record a change made by the caller of the request in the activity log.
+// Failures are logged only, the change itself is already stored.
+func recordActivity(c *gin.Context, actionType string, newvalue string) {
+
+	err := logStore.InsertActivity(5, actionType, newvalue, callerId(c))
+
+	if err != nil {
+		logf("error", "Recording Activity Log: %s", err.Error())
+	}
+}
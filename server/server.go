@@ -0,0 +1,121 @@
+// Package server holds the routes of the NGCS Local Log Server. Processes the
+// requests listed in InitialiseRoutes and stores them into the appropriate
+// klima_chamber tables through a store.Store.
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/Ramcharanpakala/goprojectes/api"
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+	"github.com/gin-gonic/gin"
+)
+
+var ngcsLogConfig config.NGCSLogConfig
+
+var ngcsLogConfigMu sync.RWMutex
+
+var logStore store.Store
+
+// Set the store and NGCS Log Config used by the routes. Must be called before
+// the router starts serving requests.
+func Setup(s store.Store, logConfig config.NGCSLogConfig) {
+
+	logStore = s
+
+	SetLogConfig(logConfig)
+}
+
+// Replace the NGCS Log Config while the router is serving requests. Remote
+// forwarding, the alarm bands, LogLevel and TimeZone apply from the next
+// request on.
+func SetLogConfig(logConfig config.NGCSLogConfig) {
+
+	ngcsLogConfigMu.Lock()
+	defer ngcsLogConfigMu.Unlock()
+
+	ngcsLogConfig = logConfig
+
+	// Validated with the config, an unknown zone keeps the one in use
+	if loc, err := logConfig.Location(); err == nil {
+		model.SetLocation(loc)
+	}
+}
+
+// NGCS Log Config in use
+func currentLogConfig() config.NGCSLogConfig {
+
+	ngcsLogConfigMu.RLock()
+	defer ngcsLogConfigMu.RUnlock()
+
+	return ngcsLogConfig
+}
+
+// Register every ingest and read route on the router
+func InitialiseRoutes(router *gin.Engine) {
+
+	router.Use(authenticate, idempotent, forwardRemotely)
+
+	router.NoRoute(processNoRoute)
+
+	router.POST("/Logs_Event", processEvent_Log)
+	router.POST("/Logs_Event_Type", processEvent_typeLog)
+	router.POST("/Logs_Test", processTest_Log)
+	router.POST("/Logs_Test_Type", processTest_typeLog)
+	router.POST("/Logs_Maintenance", processMaintenance_Log)
+	router.POST("/Logs_All", processAll_Logs)
+	router.POST("/Loop_Data", processLoopDataInsert)
+	router.PUT("/Loop_Data/:date_time_date", processLoopDataCreateOrUpdate)
+	router.POST("/set_io_card_info", processIocardinfo)
+	router.POST("/Chamber", processChamberInsert)
+	router.PUT("/Chamber/:id", processChamberUpdate)
+	router.POST("/Channel", processChannelInsert)
+	router.GET("/Channel/name/:name", processChannelByName)
+	router.PUT("/Channel/:id", processChannelUpdate)
+	router.DELETE("/Channel/:id", processChannelRetire)
+	router.POST("/Calibration", processCalibrationInsert)
+	router.GET("/Logs_Event_Type/name/:name", processEvent_typeByName)
+	router.PUT("/Logs_Event_Type/:id", processEvent_typeUpdate)
+	router.DELETE("/Logs_Event_Type/:id", processEvent_typeRetire)
+	router.GET("/Logs_Test_Type/name/:name", processTest_typeByName)
+	router.PUT("/Logs_Test_Type/:id", processTest_typeUpdate)
+	router.DELETE("/Logs_Test_Type/:id", processTest_typeRetire)
+	router.POST("/Logs_Test_Type/:id/Profile", processTest_ProfileInsert)
+	router.GET("/Logs_Test_Type/:id/Profile", processTest_ProfileGet)
+	router.GET("/Logs_Test_Type/:id/Profile/versions", processTest_ProfileVersions)
+	router.POST("/Logs_Test/:log_id/Evaluate", processTest_Evaluate)
+	router.GET("/Logs_Test/:log_id/Evaluation", processTest_Evaluation)
+
+	// Read routes
+	router.GET("/openapi.json", processOpenAPI)
+	router.GET("/Logs_Event", processTableRead("Logs_Event"))
+	router.GET("/Logs_Event_Type", processTableRead("Logs_Event_Type"))
+	router.GET("/Logs_Test", processTableRead("Logs_Test"))
+	router.GET("/Logs_Test_Type", processTableRead("Logs_Test_Type"))
+	router.GET("/Logs_Maintenance", processTableRead("Logs_Maintenance"))
+	router.GET("/Loop_Data", processLoopDataList)
+	router.GET("/Loop_Data/hourly", processLoopDataHourly)
+	router.GET("/Io_card_info", processTableRead("Io_card_info"))
+	router.GET("/get_io_card_info", processTableRead("Io_card_info"))
+	router.GET("/Chamber", processTableRead("Chamber"))
+	router.GET("/Channel", processTableRead("Channel"))
+	router.GET("/Calibration", processTableRead("Calibration"))
+	router.GET("/Calibration/expired", processCalibrationExpired)
+	router.GET("/Chamber/:id", processChamberGet)
+	router.GET("/Chamber/:id/Logs_Event", processChamberRead("Logs_Event"))
+	router.GET("/Chamber/:id/Logs_Test", processChamberRead("Logs_Test"))
+	router.GET("/Chamber/:id/Logs_Maintenance", processChamberRead("Logs_Maintenance"))
+	router.GET("/Chamber/:id/Loop_Data", processChamberLoopData)
+	router.GET("/Chamber/:id/Loop_Data/hourly", processChamberLoopDataHourly)
+	router.GET("/Chamber/:id/Io_card_info", processChamberRead("Io_card_info"))
+	router.GET("/Chamber/:id/Calibration", processChamberRead("Calibration"))
+}
+
+// Serve the OpenAPI document of the routes
+func processOpenAPI(c *gin.Context) {
+
+	c.Data(http.StatusOK, "application/json; charset=utf-8", api.Spec)
+}
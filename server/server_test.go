@@ -0,0 +1,1179 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/api"
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+	"github.com/Ramcharanpakala/goprojectes/store/memory"
+	"github.com/gin-gonic/gin"
+)
+
+const eventTypeJSON = `{"events_type":"trips","created_by":1,"modified_by":1,"create_date":"2019-01-01 04:00:55","modified_date":"2019-01-06 05:20:32"}`
+
+const testTypeJSON = `{"test_type":"XYZ","create_date":"2019-01-05 06:00:55","modified_date":"2019-01-04 03:20:32","created_by":1,"modified_by":1}`
+
+const eventJSON = `{"log_id":"TE001","program_name":"ABC","program_date_time_date":"2019-01-03 04:25:20","ZTK_Logs_Event_Type_id":1,"ZTK_Users_id":4,"created_by":1,"created_date":"2019-01-01 04:00:55","modified_by":1,"modified_date":"2019-01-06 05:20:32"}`
+
+const testJSON = `{"log_id":"TE001","log_name":"DEF","log_date_time_date":"2019-01-10 04:00:00","ZTK_Logs_Test_Type_id":1,"ZTK_Users_id":1,"created_by":1,"created_date":"2019-01-01 04:00:55","modified_by":1,"modified_date":"2019-01-06 05:20:32"}`
+
+const maintenanceJSON = `{"component_name":"trips","runtime_hr":1,"counter":1,"days_till_service":10,"maintenance_pending":1,"maintenance_status":0,"created_date":"2019-01-01 04:00:55","modified_date":"2019-01-06 05:20:32","created_by":1,"modified_by":1}`
+
+const iocardinfoJSON = `{"card_address":"00000100","card_type":"00000100","card_version":"00000100","card_serial_number":"cfab001","secret_key":"fechxz","customer_id":1,"mfg_date_date":"2019-02-02 04:03:55","created_date":"2019-01-01 04:00:55","modified_date":"2019-01-06 05:20:32","created_by":1,"modified_by":1}`
+
+// Set up the routes on a new memory store
+func newTestServer(t *testing.T, logConfig config.NGCSLogConfig) (*gin.Engine, *memory.Store) {
+
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	logStore := memory.New()
+
+	Setup(logStore, logConfig)
+
+	router := gin.New()
+
+	InitialiseRoutes(router)
+
+	return router, logStore
+}
+
+// Send a request and decode its response, which must be one Response
+func send(t *testing.T, router *gin.Engine, method string, path string, body string) (int, Response) {
+
+	t.Helper()
+
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	recorder := httptest.NewRecorder()
+
+	router.ServeHTTP(recorder, request)
+
+	var response Response
+
+	decoder := json.NewDecoder(recorder.Body)
+
+	err := decoder.Decode(&response)
+
+	if err != nil {
+		t.Fatalf("%s %s: response is not JSON: %v", method, path, err)
+	}
+
+	if decoder.More() {
+		t.Fatalf("%s %s: more than one JSON value in the response", method, path)
+	}
+
+	return recorder.Code, response
+}
+
+// Send a request that must get the status code and return its response
+func expect(t *testing.T, router *gin.Engine, method string, path string, body string, code int) Response {
+
+	t.Helper()
+
+	got, response := send(t, router, method, path, body)
+
+	status := StatusOK
+
+	if code >= http.StatusBadRequest {
+		status = StatusError
+	}
+
+	if got != code || response.Status != status {
+		t.Fatalf("%s %s: got %d %+v, want %d", method, path, got, response, code)
+	}
+
+	return response
+}
+
+// Send a request that must succeed and return its response
+func mustSend(t *testing.T, router *gin.Engine, method string, path string, body string) Response {
+
+	t.Helper()
+
+	code, response := send(t, router, method, path, body)
+
+	if code >= http.StatusMultipleChoices || response.Status != StatusOK {
+		t.Fatalf("%s %s: got %d %+v", method, path, code, response)
+	}
+
+	return response
+}
+
+// Rows returned by a read route
+func readRows(t *testing.T, router *gin.Engine, path string) []interface{} {
+
+	t.Helper()
+
+	rows, ok := mustSend(t, router, "GET", path, "").Data.([]interface{})
+
+	if !ok {
+		t.Fatalf("GET %s: not a list", path)
+	}
+
+	return rows
+}
+
+func TestEventLog(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	expect(t, router, "POST", "/Logs_Event_Type", eventTypeJSON, http.StatusCreated)
+
+	expect(t, router, "POST", "/Logs_Event", eventJSON, http.StatusCreated)
+
+	rows := readRows(t, router, "/Logs_Event")
+
+	if len(rows) != 1 || rows[0].(map[string]interface{})["log_id"] != "TE001" {
+		t.Fatalf("Logs_Event rows %v", rows)
+	}
+
+	// The insert of the type and the log are both in the activity log
+	activities := logStore.Activities()
+
+	if len(activities) != 2 || activities[1].ActionType != "INSERT" || !strings.Contains(activities[1].NewValue, `"log_id":"TE001"`) {
+		t.Fatalf("activity log %v", activities)
+	}
+}
+
+func TestEventLogTypeName(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{})
+
+	mustSend(t, router, "POST", "/Logs_Event_Type", eventTypeJSON)
+
+	event := strings.Replace(eventJSON, `"ZTK_Logs_Event_Type_id":1`, `"events_type":"trips"`, 1)
+
+	expect(t, router, "POST", "/Logs_Event", event, http.StatusCreated)
+
+	// Unknown names are rejected unless AutoCreateTypes is set
+	event = strings.Replace(eventJSON, `"ZTK_Logs_Event_Type_id":1`, `"events_type":"alarm"`, 1)
+
+	expect(t, router, "POST", "/Logs_Event", event, http.StatusUnprocessableEntity)
+
+	router, _ = newTestServer(t, config.NGCSLogConfig{AutoCreateTypes: 1})
+
+	expect(t, router, "POST", "/Logs_Event", event, http.StatusCreated)
+
+	if rows := readRows(t, router, "/Logs_Event_Type"); len(rows) != 1 {
+		t.Fatalf("Logs_Event_Type rows %v", rows)
+	}
+}
+
+func TestEventLogUnknownType(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	expect(t, router, "POST", "/Logs_Event", eventJSON, http.StatusUnprocessableEntity)
+
+	if rows := readRows(t, router, "/Logs_Event"); len(rows) != 0 {
+		t.Fatalf("Logs_Event rows %v", rows)
+	}
+
+	if activities := logStore.Activities(); len(activities) != 0 {
+		t.Fatalf("activity log %v", activities)
+	}
+}
+
+func TestEventType(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	mustSend(t, router, "POST", "/Logs_Event_Type", eventTypeJSON)
+
+	expect(t, router, "POST", "/Logs_Event_Type", eventTypeJSON, http.StatusUnprocessableEntity)
+
+	eventType := mustSend(t, router, "GET", "/Logs_Event_Type/name/trips", "").Data.(map[string]interface{})
+
+	if eventType["id"] != 1.0 || eventType["active"] != 1.0 {
+		t.Fatalf("Logs_Event_Type %v", eventType)
+	}
+
+	code, _ := send(t, router, "GET", "/Logs_Event_Type/name/alarm", "")
+
+	if code != http.StatusNotFound {
+		t.Fatalf("GET unknown name: got %d", code)
+	}
+
+	expect(t, router, "PUT", "/Logs_Event_Type/1", `{"events_type":"alarm","modified_by":2}`, http.StatusOK)
+
+	code, _ = send(t, router, "PUT", "/Logs_Event_Type/9", `{"events_type":"other"}`)
+
+	if code != http.StatusNotFound {
+		t.Fatalf("PUT unknown id: got %d", code)
+	}
+
+	expect(t, router, "DELETE", "/Logs_Event_Type/1", "", http.StatusOK)
+
+	// A retired type can no longer be used by new logs
+	expect(t, router, "POST", "/Logs_Event", eventJSON, http.StatusUnprocessableEntity)
+
+	rows := readRows(t, router, "/Logs_Event_Type")
+
+	if row := rows[0].(map[string]interface{}); row["events_type"] != "alarm" || row["active"] != 0.0 {
+		t.Fatalf("Logs_Event_Type row %v", row)
+	}
+
+	var actions []string
+
+	for _, activity := range logStore.Activities() {
+		actions = append(actions, activity.ActionType)
+	}
+
+	if strings.Join(actions, ",") != "INSERT,UPDATE,RETIRE" {
+		t.Fatalf("activity log actions %v", actions)
+	}
+}
+
+func TestTestLog(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{})
+
+	expect(t, router, "POST", "/Logs_Test", testJSON, http.StatusUnprocessableEntity)
+
+	expect(t, router, "POST", "/Logs_Test_Type", testTypeJSON, http.StatusCreated)
+
+	expect(t, router, "POST", "/Logs_Test", testJSON, http.StatusCreated)
+
+	rows := readRows(t, router, "/Logs_Test")
+
+	if len(rows) != 1 || rows[0].(map[string]interface{})["log_name"] != "DEF" {
+		t.Fatalf("Logs_Test rows %v", rows)
+	}
+
+	testType := mustSend(t, router, "GET", "/Logs_Test_Type/name/XYZ", "").Data.(map[string]interface{})
+
+	if testType["id"] != 1.0 {
+		t.Fatalf("Logs_Test_Type %v", testType)
+	}
+
+	expect(t, router, "DELETE", "/Logs_Test_Type/1", "", http.StatusOK)
+
+	expect(t, router, "POST", "/Logs_Test", testJSON, http.StatusUnprocessableEntity)
+}
+
+func TestMaintenanceLog(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	expect(t, router, "POST", "/Logs_Maintenance", maintenanceJSON, http.StatusCreated)
+
+	if rows := readRows(t, router, "/Logs_Maintenance"); len(rows) != 1 {
+		t.Fatalf("Logs_Maintenance rows %v", rows)
+	}
+
+	if activities := logStore.Activities(); len(activities) != 1 {
+		t.Fatalf("activity log %v", activities)
+	}
+}
+
+func TestLoopDataUpsert(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	sample := `{"temp_sp":80.22,"temp_pv":5.6,"hum_sp":25.4,"hum_pv":3.2,"press_sp":35.4,"press_pv":4.4,"date_time_date":"2019-01-15 06:05:40"}`
+
+	expect(t, router, "POST", "/Loop_Data", sample, http.StatusCreated)
+
+	// PUT of an existing date time updates it
+	update := strings.Replace(sample, `"temp_pv":5.6`, `"temp_pv":79.9`, 1)
+
+	expect(t, router, "PUT", "/Loop_Data/2019-01-15%2006:05:40", update, http.StatusOK)
+
+	// PUT of a new date time inserts it
+	insert := strings.Replace(sample, "06:05:40", "06:05:50", 1)
+
+	expect(t, router, "PUT", "/Loop_Data/2019-01-15%2006:05:50", insert, http.StatusCreated)
+
+	// The date time of the body must be the one of the URL
+	response := expect(t, router, "PUT", "/Loop_Data/2019-01-15%2006:06:00", insert, http.StatusBadRequest)
+
+	if response.Errors[0].Field != "date_time_date" {
+		t.Fatalf("errors %+v", response.Errors)
+	}
+
+	// A second POST of a date time is rejected, PUT replaces it
+	expect(t, router, "POST", "/Loop_Data", insert, http.StatusUnprocessableEntity)
+
+	rows := readRows(t, router, "/Loop_Data")
+
+	if len(rows) != 2 {
+		t.Fatalf("Loop_Data rows %v", rows)
+	}
+
+	if row := rows[0].(map[string]interface{}); row["temp_pv"] != 79.9 {
+		t.Fatalf("Loop_Data not updated: %v", row)
+	}
+
+	// The replaced sample is in the activity log as an update
+	activities := logStore.Activities()
+
+	if len(activities) != 3 || activities[0].ActionType != "INSERT" || activities[1].ActionType != "UPDATE" || activities[2].ActionType != "INSERT" || !strings.Contains(activities[1].NewValue, `"temp_pv":79.9`) {
+		t.Fatalf("activity log %v", activities)
+	}
+}
+
+func TestLoopDataBetween(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{TimeZone: "UTC"})
+
+	for _, at := range []string{"06:05:40", "06:05:50", "06:06:00"} {
+		mustSend(t, router, "POST", "/Loop_Data", `{"temp_sp":80.22,"date_time_date":"2019-01-15 `+at+`"}`)
+	}
+
+	for query, want := range map[string]int{
+		"":                            3,
+		"?start=2019-01-15T06:05:50Z": 2,
+		"?end=2019-01-15T06:05:50Z":   1,
+		"?start=2019-01-15%2006:05:41&end=2019-01-15T06:06:00Z": 1,
+	} {
+
+		if rows := readRows(t, router, "/Loop_Data"+query); len(rows) != want {
+			t.Errorf("GET /Loop_Data%s: %d rows, want %d", query, len(rows), want)
+		}
+	}
+
+	response := expect(t, router, "GET", "/Loop_Data?start=yesterday", "", http.StatusBadRequest)
+
+	if response.Errors[0].Field != "start" {
+		t.Fatalf("errors %+v", response.Errors)
+	}
+}
+
+func TestLoopDataHourly(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{TimeZone: "UTC"})
+
+	mustSend(t, router, "POST", "/Chamber", `{"name":"VC 4033"}`)
+
+	for _, at := range []string{"06:05:40", "06:35:40", "07:05:40"} {
+		mustSend(t, router, "POST", "/Loop_Data", `{"temp_pv":20,"date_time_date":"2019-01-15 `+at+`","ZTK_Chamber_id":1}`)
+	}
+
+	_, err := logStore.PruneLoopData(model.Time{}, model.NewTime(time.Date(2019, 1, 16, 0, 0, 0, 0, time.UTC)), func([]model.Loop_Data) error { return nil })
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for path, want := range map[string]int{
+		"/Loop_Data/hourly":                                     2,
+		"/Loop_Data/hourly?start=2019-01-15T07:00:00Z":          1,
+		"/Chamber/1/Loop_Data/hourly?end=2019-01-15%2007:00:00": 1,
+		"/Loop_Data": 0,
+	} {
+
+		if rows := readRows(t, router, path); len(rows) != want {
+			t.Errorf("GET %s: %d rows, want %d", path, len(rows), want)
+		}
+	}
+
+	hours := readRows(t, router, "/Chamber/1/Loop_Data/hourly")
+
+	if hour := hours[0].(map[string]interface{}); hour["samples"] != 2.0 || hour["hour"] != "2019-01-15T06:00:00Z" {
+		t.Fatalf("hourly Loop_Data %v", hour)
+	}
+
+	expect(t, router, "GET", "/Chamber/2/Loop_Data/hourly", "", http.StatusNotFound)
+}
+
+func TestLoopDataChannels(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{TimeZone: "UTC"})
+
+	mustSend(t, router, "POST", "/Channel", `{"name":"co2","unit":"ppm","minimum":0,"maximum":5000}`)
+
+	expect(t, router, "POST", "/Channel", `{"name":"co2"}`, http.StatusUnprocessableEntity)
+	expect(t, router, "POST", "/Channel", `{"name":"temp_pv"}`, http.StatusUnprocessableEntity)
+
+	for _, method := range []string{"POST", "PUT"} {
+
+		path := map[string]string{"POST": "/Channel", "PUT": "/Channel/1"}[method]
+
+		response := expect(t, router, method, path, `{"name":"co2","minimum":5000,"maximum":0}`, http.StatusBadRequest)
+
+		if len(response.Errors) != 1 || response.Errors[0].Field != "maximum" {
+			t.Fatalf("%s %s with minimum above maximum: errors %+v", method, path, response.Errors)
+		}
+	}
+
+	// Older controllers send only the columns
+	mustSend(t, router, "POST", "/Loop_Data", `{"temp_sp":80.22,"temp_pv":5.6,"date_time_date":"2019-01-15 06:05:40"}`)
+	mustSend(t, router, "POST", "/Loop_Data", `{"temp_sp":80.22,"temp_pv":5.8,"date_time_date":"2019-01-15 06:05:50","channels":{"co2":412.5}}`)
+
+	for _, sample := range []string{
+		`{"date_time_date":"2019-01-15 06:06:00","channels":{"co2":6000}}`,
+		`{"date_time_date":"2019-01-15 06:06:00","channels":{"tc_1":23.1}}`,
+		`{"date_time_date":"2019-01-15 06:06:00","channels":{"hum_pv":50}}`,
+	} {
+		expect(t, router, "POST", "/Loop_Data", sample, http.StatusUnprocessableEntity)
+	}
+
+	expect(t, router, "PUT", "/Loop_Data/2019-01-15%2006:05:50", `{"temp_pv":5.9,"date_time_date":"2019-01-15 06:05:50","channels":{"co2":420}}`, http.StatusOK)
+
+	rows := readRows(t, router, "/Loop_Data")
+
+	if len(rows) != 2 || rows[0].(map[string]interface{})["channels"] != nil {
+		t.Fatalf("Loop_Data rows %v", rows)
+	}
+
+	if channels := rows[1].(map[string]interface{})["channels"].(map[string]interface{}); len(channels) != 1 || channels["co2"] != 420.0 {
+		t.Fatalf("channels %v", channels)
+	}
+
+	channel := mustSend(t, router, "GET", "/Channel/name/co2", "").Data.(map[string]interface{})
+
+	if channel["unit"] != "ppm" || channel["maximum"] != 5000.0 {
+		t.Fatalf("channel %v", channel)
+	}
+
+	mustSend(t, router, "PUT", "/Channel/1", `{"name":"co2","unit":"ppm"}`)
+	mustSend(t, router, "POST", "/Loop_Data", `{"date_time_date":"2019-01-15 06:06:00","channels":{"co2":6000}}`)
+
+	mustSend(t, router, "DELETE", "/Channel/1", "")
+	expect(t, router, "POST", "/Loop_Data", `{"date_time_date":"2019-01-15 06:06:10","channels":{"co2":400}}`, http.StatusUnprocessableEntity)
+	expect(t, router, "GET", "/Channel/name/co3", "", http.StatusNotFound)
+	expect(t, router, "DELETE", "/Channel/9", "", http.StatusNotFound)
+
+	// Unknown channels are catalogued when types are created automatically
+	router, _ = newTestServer(t, config.NGCSLogConfig{AutoCreateTypes: 1})
+
+	mustSend(t, router, "POST", "/Loop_Data", `{"date_time_date":"2019-01-15 06:06:00","channels":{"tc_1":23.1,"tc_2":24}}`)
+
+	if rows := readRows(t, router, "/Channel"); len(rows) != 2 {
+		t.Fatalf("channels %v", rows)
+	}
+}
+
+func TestCalibration(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{TimeZone: "UTC"})
+
+	mustSend(t, router, "POST", "/Chamber", `{"name":"KL-1"}`)
+	mustSend(t, router, "POST", "/Channel", `{"name":"tc_1","unit":"°C"}`)
+
+	mustSend(t, router, "POST", "/Calibration", `{"ZTK_Chamber_id":1,"channel":"temp_pv","offset":-0.5,"calibration_date":"2019-01-01 00:00:00","due_date":"2019-07-01 00:00:00","certificate":"K-0815"}`)
+	mustSend(t, router, "POST", "/Calibration", `{"ZTK_Chamber_id":1,"channel":"tc_1","gain":2,"calibration_date":"2019-01-01 00:00:00","due_date":"2020-01-01 00:00:00"}`)
+	// Recalibrated later, samples from then on use the new offset
+	mustSend(t, router, "POST", "/Calibration", `{"ZTK_Chamber_id":1,"channel":"temp_pv","offset":0.25,"calibration_date":"2019-06-01 00:00:00","due_date":"2019-12-01 00:00:00"}`)
+
+	expect(t, router, "POST", "/Calibration", `{"ZTK_Chamber_id":1,"channel":"temp_sp","calibration_date":"2019-01-01 00:00:00","due_date":"2020-01-01 00:00:00"}`, http.StatusUnprocessableEntity)
+	expect(t, router, "POST", "/Calibration", `{"ZTK_Chamber_id":2,"channel":"temp_pv","calibration_date":"2019-01-01 00:00:00","due_date":"2020-01-01 00:00:00"}`, http.StatusUnprocessableEntity)
+	expect(t, router, "POST", "/Calibration", `{"channel":"temp_pv","calibration_date":"2019-01-01 00:00:00","due_date":"2018-01-01 00:00:00"}`, http.StatusBadRequest)
+
+	mustSend(t, router, "POST", "/Loop_Data", `{"temp_pv":40,"hum_pv":50,"date_time_date":"2019-02-01 00:00:00","ZTK_Chamber_id":1,"channels":{"tc_1":20}}`)
+	mustSend(t, router, "POST", "/Loop_Data", `{"temp_pv":40,"date_time_date":"2019-06-02 00:00:00","ZTK_Chamber_id":1}`)
+	// Chamber 0 has no calibrations
+	mustSend(t, router, "POST", "/Loop_Data", `{"temp_pv":40,"date_time_date":"2019-06-02 00:00:00"}`)
+
+	rows := readRows(t, router, "/Chamber/1/Loop_Data")
+
+	first, second := rows[0].(map[string]interface{}), rows[1].(map[string]interface{})
+
+	if first["temp_pv"] != 39.5 || first["hum_pv"] != 50.0 || first["channels"].(map[string]interface{})["tc_1"] != 40.0 || second["temp_pv"] != 40.25 {
+		t.Fatalf("corrected samples %v", rows)
+	}
+
+	calibrated := first["calibrated"].(map[string]interface{})
+
+	if len(calibrated) != 2 || calibrated["temp_pv"].(map[string]interface{})["raw"] != 40.0 || calibrated["temp_pv"].(map[string]interface{})["ZTK_Calibration_id"] != 1.0 {
+		t.Fatalf("calibrated values %v", calibrated)
+	}
+
+	for _, row := range readRows(t, router, "/Loop_Data") {
+
+		if row := row.(map[string]interface{}); row["ZTK_Chamber_id"] == 0.0 && (row["temp_pv"] != 40.0 || row["calibrated"] != nil) {
+			t.Fatalf("sample of chamber 0 %v", row)
+		}
+	}
+
+	for query, want := range map[string]int{
+		"?at=2019-05-01T00:00:00Z": 0,
+		"?at=2019-08-01T00:00:00Z": 0,
+		"?at=2019-12-02T00:00:00Z": 1,
+		"?at=2020-01-02T00:00:00Z": 2,
+	} {
+
+		if rows := readRows(t, router, "/Calibration/expired"+query); len(rows) != want {
+			t.Errorf("GET /Calibration/expired%s: %d rows, want %d", query, len(rows), want)
+		}
+	}
+
+	if rows := readRows(t, router, "/Chamber/1/Calibration"); len(rows) != 3 || rows[1].(map[string]interface{})["gain"] != 2.0 {
+		t.Fatalf("calibrations of chamber 1 %v", rows)
+	}
+
+	// A value corrected past the range of its column is rejected
+	mustSend(t, router, "POST", "/Chamber", `{"name":"KL-2"}`)
+	mustSend(t, router, "POST", "/Calibration", `{"ZTK_Chamber_id":2,"channel":"hum_pv","offset":2,"calibration_date":"2019-01-01 00:00:00","due_date":"2030-01-01 00:00:00"}`)
+
+	for _, method := range []string{"POST", "PUT"} {
+
+		response := expect(t, router, method, map[string]string{"POST": "/Loop_Data", "PUT": "/Loop_Data/2019-02-01%2000:00:00"}[method], `{"hum_pv":99.5,"date_time_date":"2019-02-01 00:00:00","ZTK_Chamber_id":2}`, http.StatusUnprocessableEntity)
+
+		if len(response.Errors) != 1 || response.Errors[0].Field != "hum_pv" || response.Errors[0].Value != 101.5 {
+			t.Fatalf("%s of a hum_pv calibrated past 100: errors %+v", method, response.Errors)
+		}
+	}
+
+	mustSend(t, router, "POST", "/Loop_Data", `{"hum_pv":97.5,"date_time_date":"2019-02-01 00:00:00","ZTK_Chamber_id":2}`)
+}
+
+func TestIocardinfo(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	expect(t, router, "POST", "/set_io_card_info", iocardinfoJSON, http.StatusCreated)
+
+	for _, path := range []string{"/Io_card_info", "/get_io_card_info"} {
+
+		rows := readRows(t, router, path)
+
+		if len(rows) != 1 || rows[0].(map[string]interface{})["card_serial_number"] != "cfab001" {
+			t.Fatalf("%s rows %v", path, rows)
+		}
+	}
+
+	activities := logStore.Activities()
+
+	if len(activities) != 1 || activities[0].TableId != 5 || activities[0].UserId != 0 || !strings.Contains(activities[0].NewValue, "cfab001") {
+		t.Fatalf("activity log %v", activities)
+	}
+}
+
+func TestChambers(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{TimeZone: "UTC"})
+
+	mustSend(t, router, "POST", "/set_io_card_info", iocardinfoJSON)
+
+	mustSend(t, router, "POST", "/Chamber", `{"name":"KL-1","location":"hall A","io_cards":["cfab001"]}`)
+	mustSend(t, router, "POST", "/Chamber", `{"name":"KL-2"}`)
+
+	expect(t, router, "POST", "/Chamber", `{"name":"KL-1"}`, http.StatusUnprocessableEntity)
+	expect(t, router, "POST", "/Chamber", `{"name":"KL-3","io_cards":["cfab001"]}`, http.StatusUnprocessableEntity)
+	expect(t, router, "POST", "/Logs_Event", `{"log_id":"TE001","program_name":"ABC","program_date_time_date":"2019-01-03 04:25:20","ZTK_Chamber_id":9}`, http.StatusUnprocessableEntity)
+
+	// Both chambers send a sample for the same second without replacing the
+	// other's
+	expect(t, router, "PUT", "/Loop_Data/2019-01-15%2006:05:40", `{"temp_pv":21,"date_time_date":"2019-01-15 06:05:40","ZTK_Chamber_id":1}`, http.StatusCreated)
+	expect(t, router, "PUT", "/Loop_Data/2019-01-15%2006:05:40", `{"temp_pv":22,"date_time_date":"2019-01-15 06:05:40","ZTK_Chamber_id":2}`, http.StatusCreated)
+	expect(t, router, "PUT", "/Loop_Data/2019-01-15%2006:05:40", `{"temp_pv":23,"date_time_date":"2019-01-15 06:05:40","ZTK_Chamber_id":2}`, http.StatusOK)
+	mustSend(t, router, "POST", "/Logs_Maintenance", `{"component_name":"compressor","ZTK_Chamber_id":1}`)
+
+	if rows := readRows(t, router, "/Loop_Data"); len(rows) != 2 {
+		t.Fatalf("Loop_Data rows %v", rows)
+	}
+
+	rows := readRows(t, router, "/Chamber/2/Loop_Data?start=2019-01-15T06:05:40Z")
+
+	if len(rows) != 1 || rows[0].(map[string]interface{})["temp_pv"] != 23.0 {
+		t.Fatalf("Loop_Data of chamber 2 %v", rows)
+	}
+
+	for path, want := range map[string]int{
+		"/Chamber":                    2,
+		"/Chamber/1/Logs_Maintenance": 1,
+		"/Chamber/2/Logs_Maintenance": 0,
+		"/Chamber/1/Io_card_info":     1,
+		"/Chamber/2/Io_card_info":     0,
+		"/Chamber/1/Logs_Event":       0,
+	} {
+
+		if rows := readRows(t, router, path); len(rows) != want {
+			t.Errorf("GET %s: %d rows, want %d", path, len(rows), want)
+		}
+	}
+
+	// Refit the card to chamber 2
+	expect(t, router, "PUT", "/Chamber/2", `{"name":"KL-2","io_cards":["cfab001"]}`, http.StatusUnprocessableEntity)
+	mustSend(t, router, "PUT", "/Chamber/1", `{"name":"KL-1","location":"hall B"}`)
+	mustSend(t, router, "PUT", "/Chamber/2", `{"name":"KL-2","io_cards":["cfab001"]}`)
+
+	chamber := mustSend(t, router, "GET", "/Chamber/1", "").Data.(map[string]interface{})
+
+	if chamber["location"] != "hall B" || len(chamber["io_cards"].([]interface{})) != 0 {
+		t.Fatalf("chamber 1 %v", chamber)
+	}
+
+	// The changes of the chambers in the activity log
+	want := map[string]string{"INSERT": "created_by", "UPDATE": "modified_by"}
+
+	for _, activity := range logStore.Activities() {
+
+		var value map[string]interface{}
+
+		json.Unmarshal([]byte(activity.NewValue), &value)
+
+		if _, ok := value["io_cards"]; !ok {
+			continue
+		}
+
+		if _, ok := value[want[activity.ActionType]]; !ok {
+			t.Errorf("activity %s %s has no %q", activity.ActionType, activity.NewValue, want[activity.ActionType])
+		}
+	}
+
+	// Chamber 0 holds the records not assigned to a chamber
+	mustSend(t, router, "PUT", "/Loop_Data/2019-01-15%2006:05:40", `{"temp_pv":24,"date_time_date":"2019-01-15 06:05:40"}`)
+	mustSend(t, router, "POST", "/Logs_Maintenance", `{"component_name":"heater"}`)
+
+	for path, want := range map[string]int{
+		"/Chamber/0/Loop_Data":        1,
+		"/Chamber/0/Loop_Data/hourly": 0,
+		"/Chamber/0/Logs_Maintenance": 1,
+		"/Chamber/0/Logs_Event":       0,
+	} {
+
+		if rows := readRows(t, router, path); len(rows) != want {
+			t.Errorf("GET %s: %d rows, want %d", path, len(rows), want)
+		}
+	}
+
+	if rows := readRows(t, router, "/Chamber/0/Loop_Data"); rows[0].(map[string]interface{})["temp_pv"] != 24.0 {
+		t.Fatalf("Loop_Data of chamber 0 %v", rows)
+	}
+
+	expect(t, router, "GET", "/Chamber/0", "", http.StatusNotFound)
+	expect(t, router, "GET", "/Chamber/9", "", http.StatusNotFound)
+	expect(t, router, "GET", "/Chamber/9/Logs_Test", "", http.StatusNotFound)
+	expect(t, router, "PUT", "/Chamber/9", `{"name":"KL-9"}`, http.StatusNotFound)
+	expect(t, router, "GET", "/Chamber/x/Loop_Data", "", http.StatusBadRequest)
+}
+
+func TestAllLogs(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	test := strings.Replace(testJSON, `"ZTK_Logs_Test_Type_id":1`, `"ZTK_Logs_Test_Type":`+testTypeJSON, 1)
+	event := strings.Replace(eventJSON, `"ZTK_Logs_Event_Type_id":1`, `"ZTK_Logs_Event_Type":`+eventTypeJSON, 1)
+
+	all := fmt.Sprintf(`{"ZTK_Logs_Test":%s,"ZTK_Logs_Event":%s,"Logs_Maintenance":%s}`, test, event, maintenanceJSON)
+
+	response := expect(t, router, "POST", "/Logs_All", all, http.StatusCreated).Id.(map[string]interface{})
+
+	for _, key := range []string{"ZTK_Logs_Test_id", "ZTK_Logs_Event_id", "ZTK_Logs_Maintenance_id"} {
+
+		if _, ok := response[key]; !ok {
+			t.Fatalf("response %v has no %q", response, key)
+		}
+	}
+
+	for _, path := range []string{"/Logs_Test", "/Logs_Event", "/Logs_Maintenance", "/Logs_Test_Type", "/Logs_Event_Type"} {
+
+		if rows := readRows(t, router, path); len(rows) != 1 {
+			t.Fatalf("%s rows %v", path, rows)
+		}
+	}
+
+	if activities := logStore.Activities(); len(activities) != 1 {
+		t.Fatalf("activity log %v", activities)
+	}
+
+	// Nothing is stored when one part is rejected
+	mustSend(t, router, "DELETE", "/Logs_Test_Type/1", "")
+
+	expect(t, router, "POST", "/Logs_All", all, http.StatusUnprocessableEntity)
+
+	for _, path := range []string{"/Logs_Test", "/Logs_Event", "/Logs_Maintenance"} {
+
+		if rows := readRows(t, router, path); len(rows) != 1 {
+			t.Fatalf("%s rows after rejected Logs_All %v", path, rows)
+		}
+	}
+}
+
+func TestTestProfileEvaluation(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{})
+
+	mustSend(t, router, "POST", "/Logs_Test_Type", testTypeJSON)
+	mustSend(t, router, "POST", "/Logs_Test", testJSON)
+
+	profile := `{"comment":"soak","steps":[{"step_type":"soak","temp_sp":25,"duration_min":1,"temp_tol":1}]}`
+
+	expect(t, router, "POST", "/Logs_Test_Type/1/Profile", profile, http.StatusCreated)
+
+	expect(t, router, "POST", "/Logs_Test_Type/1/Profile", profile, http.StatusCreated)
+
+	if versions := readRows(t, router, "/Logs_Test_Type/1/Profile/versions"); len(versions) != 2 {
+		t.Fatalf("profile versions %v", versions)
+	}
+
+	latest := mustSend(t, router, "GET", "/Logs_Test_Type/1/Profile", "").Data.(map[string]interface{})
+
+	if latest["version"] != 2.0 {
+		t.Fatalf("latest profile %v", latest)
+	}
+
+	code, _ := send(t, router, "GET", "/Logs_Test/TE001/Evaluation", "")
+
+	if code != http.StatusNotFound {
+		t.Fatalf("evaluation before evaluate: got %d", code)
+	}
+
+	for _, at := range []string{"04:00:00", "04:00:20", "04:00:40"} {
+		mustSend(t, router, "POST", "/Loop_Data", `{"temp_sp":25,"temp_pv":25.2,"date_time_date":"2019-01-10 `+at+`"}`)
+	}
+
+	evaluation := mustSend(t, router, "POST", "/Logs_Test/TE001/Evaluate?version=1", "").Data.(map[string]interface{})
+
+	if evaluation["verdict"] != "PASS" || evaluation["version"] != 1.0 {
+		t.Fatalf("evaluation %v", evaluation)
+	}
+
+	stored := mustSend(t, router, "GET", "/Logs_Test/TE001/Evaluation", "").Data.(map[string]interface{})
+
+	if stored["verdict"] != "PASS" {
+		t.Fatalf("stored evaluation %v", stored)
+	}
+
+	// A step out of tolerance for most of its duration fails, although it
+	// is in tolerance at its end
+	mustSend(t, router, "POST", "/Logs_Test", strings.NewReplacer("TE001", "TE002", "2019-01-10 04:00:00", "2019-01-11 04:00:00").Replace(testJSON))
+
+	for _, sample := range []string{"22,04:00:00", "22,04:00:10", "22,04:00:20", "22,04:00:30", "22,04:00:40", "25.2,04:00:50"} {
+
+		pv, at, _ := strings.Cut(sample, ",")
+
+		mustSend(t, router, "POST", "/Loop_Data", `{"temp_sp":25,"temp_pv":`+pv+`,"date_time_date":"2019-01-11 `+at+`"}`)
+	}
+
+	evaluation = mustSend(t, router, "POST", "/Logs_Test/TE002/Evaluate?version=1", "").Data.(map[string]interface{})
+
+	step := evaluation["steps"].([]interface{})[0].(map[string]interface{})
+
+	if evaluation["verdict"] != "FAIL" || step["settling_time_sec"] != 50.0 || step["time_out_of_tolerance_sec"] != 50.0 {
+		t.Fatalf("evaluation of a step settled late %v", evaluation)
+	}
+}
+
+func TestForwardRemotely(t *testing.T) {
+
+	forwarded := make(chan string, 10)
+
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		forwarded <- r.Method + " " + r.URL.Path + " " + string(body)
+	}))
+
+	defer remote.Close()
+
+	host, port, _ := strings.Cut(strings.TrimPrefix(remote.URL, "http://"), ":")
+
+	remotePort, _ := strconv.Atoi(port)
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{})
+
+	mustSend(t, router, "POST", "/Logs_Event_Type", eventTypeJSON)
+
+	// LogRemotely is switched on while the server runs
+	SetLogConfig(config.NGCSLogConfig{LogRemotely: 1, RemoteLogServer: host, RemoteLogServerPort: remotePort, TimeZone: "Europe/Berlin"})
+
+	defer SetLogConfig(config.NGCSLogConfig{})
+
+	readRows(t, router, "/Logs_Event_Type")
+	mustSend(t, router, "POST", "/Logs_Event", eventJSON)
+
+	mustSend(t, router, "PUT", "/Loop_Data/2019-01-15%2006:05:40", `{"temp_sp":20,"date_time_date":"2019-01-15 06:05:40"}`)
+
+	// Date times are forwarded in UTC
+	for _, want := range []string{
+		`POST /Logs_Event {"log_id":"TE001","program_name":"ABC","program_date_time_date":"2019-01-03T03:25:20Z",`,
+		`PUT /Loop_Data/2019-01-15T05:05:40Z {"temp_sp":20,`,
+	} {
+
+		select {
+		case request := <-forwarded:
+			if !strings.HasPrefix(request, want) {
+				t.Fatalf("forwarded %q, want %q", request, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s not forwarded", want)
+		}
+	}
+
+	select {
+	case request := <-forwarded:
+		t.Fatalf("forwarded %q", request)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestLoopDataAlarms(t *testing.T) {
+
+	newTestServer(t, config.NGCSLogConfig{TempAlarmBand: 2, HumAlarmBand: 5})
+
+	log := model.Loop_Data{Dtsp: 40, Dtpv: 37.5, Dhsp: 50, Dhpv: 54, Dpsp: 1, Dppv: 30}
+
+	if alarms := checkAlarms(&log); len(alarms) != 1 || alarms[0] != "temp_pv" {
+		t.Fatalf("alarms %v", alarms)
+	}
+
+	SetLogConfig(config.NGCSLogConfig{})
+
+	if alarms := checkAlarms(&log); len(alarms) != 0 {
+		t.Fatalf("alarms without bands %v", alarms)
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	mustSend(t, router, "POST", "/Logs_Event_Type", eventTypeJSON)
+
+	badEvent := strings.NewReplacer(`"log_id":"TE001"`, `"log_id":""`, `"2019-01-03 04:25:20"`, `"03/01/2019"`).Replace(eventJSON)
+	badMaintenance := strings.Replace(maintenanceJSON, `"runtime_hr":1`, `"runtime_hr":-5`, 1)
+
+	for _, request := range []struct {
+		path   string
+		body   string
+		fields []string
+	}{
+		{"/Logs_Event", badEvent, []string{"log_id", "program_date_time_date"}},
+		{"/Logs_Maintenance", badMaintenance, []string{"runtime_hr"}},
+		{"/Loop_Data", `{"temp_sp":20,"hum_pv":120}`, []string{"hum_pv", "date_time_date"}},
+		{"/set_io_card_info", `{"card_address":"00000100","customer_id":"one"}`, []string{"customer_id"}},
+		{"/Logs_All", `{"ZTK_Logs_Event":` + badEvent + `}`, []string{"ZTK_Logs_Event.log_id", "ZTK_Logs_Event.program_date_time_date"}},
+		{"/Logs_Test", `{"log_id":`, []string{""}},
+	} {
+
+		response := expect(t, router, "POST", request.path, request.body, http.StatusBadRequest)
+
+		var fields []string
+
+		for _, fieldErr := range response.Errors {
+
+			fields = append(fields, fieldErr.Field)
+
+			if fieldErr.Reason == "" {
+				t.Fatalf("POST %s: no reason in %v", request.path, fieldErr)
+			}
+		}
+
+		if strings.Join(fields, " ") != strings.Join(request.fields, " ") {
+			t.Fatalf("POST %s: invalid fields %v, want %v", request.path, fields, request.fields)
+		}
+	}
+
+	for _, path := range []string{"/Logs_Event", "/Logs_Maintenance", "/Loop_Data", "/Io_card_info", "/Logs_Test"} {
+
+		if rows := readRows(t, router, path); len(rows) != 0 {
+			t.Fatalf("%s rows %v", path, rows)
+		}
+	}
+
+	// Only the event type is in the activity log
+	if activities := logStore.Activities(); len(activities) != 1 {
+		t.Fatalf("activity log %v", activities)
+	}
+}
+
+// Store whose inserts of maintenance logs fail
+type failingStore struct {
+	store.Store
+}
+
+func (failingStore) InsertMaintenanceLog(log *model.Logs_Maintenance) (int64, error) {
+
+	return 0, errors.New("connection refused by 10.0.0.5")
+}
+
+func TestResponseCodes(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	response := expect(t, router, "POST", "/Logs_Maintenance", maintenanceJSON, http.StatusCreated)
+
+	if response.Id != 1.0 {
+		t.Fatalf("id %v", response.Id)
+	}
+
+	expect(t, router, "GET", "/Logs_Evnt", "", http.StatusNotFound)
+	expect(t, router, "PUT", "/Logs_Event_Type/abc", `{"events_type":"x"}`, http.StatusBadRequest)
+	expect(t, router, "GET", "/Logs_Test_Type/1/Profile", "", http.StatusNotFound)
+
+	Setup(failingStore{logStore}, config.NGCSLogConfig{})
+
+	response = expect(t, router, "POST", "/Logs_Maintenance", maintenanceJSON, http.StatusInternalServerError)
+
+	if len(response.Errors) != 1 || strings.Contains(response.Errors[0].Reason, "10.0.0.5") {
+		t.Fatalf("errors %v", response.Errors)
+	}
+
+	// The failed insert is not in the activity log
+	if activities := logStore.Activities(); len(activities) != 1 {
+		t.Fatalf("activity log %v", activities)
+	}
+}
+
+func TestOpenAPIDescribesEveryRoute(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{})
+
+	code, _ := send(t, router, "GET", "/openapi.json", "")
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+
+	err := json.Unmarshal(api.Spec, &spec)
+
+	if code != http.StatusOK || err != nil {
+		t.Fatalf("GET /openapi.json: %d %v", code, err)
+	}
+
+	for _, route := range router.Routes() {
+
+		if route.Path == "/openapi.json" {
+			continue
+		}
+
+		path := route.Path
+
+		for _, part := range strings.Split(route.Path, "/") {
+
+			if strings.HasPrefix(part, ":") {
+				path = strings.Replace(path, part, "{"+part[1:]+"}", 1)
+			}
+		}
+
+		if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is not in api/openapi.json", route.Method, path)
+		}
+	}
+}
+
+func TestTimestamps(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{TimeZone: "Europe/Berlin"})
+
+	defer SetLogConfig(config.NGCSLogConfig{})
+
+	// Date times without a zone are in the chamber's zone, spaces are ignored
+	mustSend(t, router, "POST", "/set_io_card_info", strings.Replace(iocardinfoJSON, `"2019-02-02 04:03:55"`, `" 2019-02-02 04:03:55"`, 1))
+
+	row := readRows(t, router, "/Io_card_info")[0].(map[string]interface{})
+
+	if row["mfg_date_date"] != "2019-02-02T03:03:55Z" || row["device_created_date"] != "2019-01-01T03:00:55Z" {
+		t.Fatalf("io card info %v", row)
+	}
+
+	// The same sample given in RFC 3339 updates the one stored
+	mustSend(t, router, "PUT", "/Loop_Data/2019-07-15%2006:05:40", `{"temp_sp":20,"date_time_date":"2019-07-15 06:05:40"}`)
+
+	code, _ := send(t, router, "PUT", "/Loop_Data/2019-07-15T04:05:40Z", `{"temp_sp":21,"date_time_date":"2019-07-15T06:05:40+02:00"}`)
+
+	if code != http.StatusOK {
+		t.Fatalf("PUT in RFC 3339: %d", code)
+	}
+
+	if rows := readRows(t, router, "/Loop_Data"); len(rows) != 1 || rows[0].(map[string]interface{})["date_time_date"] != "2019-07-15T04:05:40Z" {
+		t.Fatalf("loop data %v", rows)
+	}
+
+	response := expect(t, router, "POST", "/Logs_Maintenance", strings.Replace(maintenanceJSON, `"2019-01-01 04:00:55"`, `"01/01/2019"`, 1), http.StatusBadRequest)
+
+	if len(response.Errors) != 1 || response.Errors[0].Field != "created_date" || response.Errors[0].Value != "01/01/2019" {
+		t.Fatalf("invalid date %v", response.Errors)
+	}
+
+	expect(t, router, "PUT", "/Loop_Data/yesterday", `{"date_time_date":"2019-07-15 06:05:40"}`, http.StatusBadRequest)
+}
+
+func TestChamberTimeZone(t *testing.T) {
+
+	router, _ := newTestServer(t, config.NGCSLogConfig{TimeZone: "Europe/Berlin", AutoCreateTypes: 1})
+
+	defer SetLogConfig(config.NGCSLogConfig{})
+
+	mustSend(t, router, "POST", "/Chamber", `{"name":"KL-1","time_zone":"America/New_York"}`)
+	mustSend(t, router, "POST", "/Chamber", `{"name":"KL-2"}`)
+
+	response := expect(t, router, "POST", "/Chamber", `{"name":"KL-3","time_zone":"Mars/Olympus"}`, http.StatusBadRequest)
+
+	if len(response.Errors) != 1 || response.Errors[0].Field != "time_zone" {
+		t.Fatalf("errors %+v", response.Errors)
+	}
+
+	// Date times without a zone are in the zone of the chamber that sent
+	// them, or in the TimeZone of the server when it has none; the chamber
+	// may follow the date time
+	mustSend(t, router, "PUT", "/Loop_Data/2019-07-15%2006:05:40", `{"date_time_date":"2019-07-15 06:05:40","ZTK_Chamber_id":1}`)
+	mustSend(t, router, "POST", "/Loop_Data", `{"date_time_date":"2019-07-15 06:05:40","ZTK_Chamber_id":2}`)
+	mustSend(t, router, "POST", "/Loop_Data", `{"date_time_date":"2019-07-15 06:05:40"}`)
+	mustSend(t, router, "POST", "/Logs_Event", `{"log_id":"TE001","program_name":"ABC","program_date_time_date":"2019-01-03 04:25:20","events_type":"trips","ZTK_Chamber_id":1}`)
+	mustSend(t, router, "POST", "/Logs_All", `{"ZTK_Logs_Test":{"log_date_time_date":"2019-01-03 04:25:20","log_id":"TE002","log_name":"ABC","test_type":"damp heat","ZTK_Chamber_id":1}}`)
+
+	for path, want := range map[string]string{
+		"/Chamber/1/Loop_Data": "2019-07-15T10:05:40Z",
+		"/Chamber/2/Loop_Data": "2019-07-15T04:05:40Z",
+		"/Chamber/0/Loop_Data": "2019-07-15T04:05:40Z",
+	} {
+
+		if rows := readRows(t, router, path); len(rows) != 1 || rows[0].(map[string]interface{})["date_time_date"] != want {
+			t.Errorf("GET %s: %v, want %s", path, rows, want)
+		}
+	}
+
+	if rows := readRows(t, router, "/Chamber/1/Logs_Event"); len(rows) != 1 || rows[0].(map[string]interface{})["program_date_time_date"] != "2019-01-03T09:25:20Z" {
+		t.Fatalf("event logs %v", rows)
+	}
+
+	if rows := readRows(t, router, "/Chamber/1/Logs_Test"); len(rows) != 1 || rows[0].(map[string]interface{})["log_date_time_date"] != "2019-01-03T09:25:20Z" {
+		t.Fatalf("test logs %v", rows)
+	}
+
+	// The sample is replaced in the zone of its chamber, and read back
+	// between date times in that zone
+	expect(t, router, "PUT", "/Loop_Data/2019-07-15%2006:05:40", `{"temp_pv":21,"date_time_date":"2019-07-15 06:05:40","ZTK_Chamber_id":1}`, http.StatusOK)
+
+	if rows := readRows(t, router, "/Chamber/1/Loop_Data?start=2019-07-15%2006:05:40&end=2019-07-15%2006:05:41"); len(rows) != 1 || rows[0].(map[string]interface{})["temp_pv"] != 21.0 {
+		t.Fatalf("Loop_Data of chamber 1 %v", rows)
+	}
+
+	chamber := mustSend(t, router, "GET", "/Chamber/1", "").Data.(map[string]interface{})
+
+	if chamber["time_zone"] != "America/New_York" {
+		t.Fatalf("chamber 1 %v", chamber)
+	}
+}
+
+func TestStampedByCaller(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	SetAPIKeys([]config.APIKey{{Key: "k-chamber", UserId: 7, Name: "chamber 4"}, {Key: "k-service", UserId: 9, Name: "service"}})
+
+	defer SetAPIKeys(nil)
+
+	sendAs := func(header string, value string, method string, path string, body string) int {
+
+		request := httptest.NewRequest(method, path, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(header, value)
+
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		return recorder.Code
+	}
+
+	for _, key := range []string{"", "k-unknown"} {
+
+		if code := sendAs(APIKeyHeader, key, "POST", "/Logs_Event_Type", eventTypeJSON); code != http.StatusUnauthorized {
+			t.Fatalf("key %q: %d", key, code)
+		}
+	}
+
+	if code := sendAs(APIKeyHeader, "", "GET", "/openapi.json", ""); code != http.StatusOK {
+		t.Fatalf("GET /openapi.json without key: %d", code)
+	}
+
+	before := model.Now()
+
+	if code := sendAs(APIKeyHeader, "k-chamber", "POST", "/Logs_Event_Type", eventTypeJSON); code != http.StatusCreated {
+		t.Fatalf("POST with key: %d", code)
+	}
+
+	if code := sendAs("Authorization", "Bearer k-service", "PUT", "/Logs_Event_Type/1", `{"events_type":"alarm","modified_by":2,"modified_date":"2019-02-01 10:00:00"}`); code != http.StatusOK {
+		t.Fatalf("PUT with bearer key: %d", code)
+	}
+
+	eventType, _ := logStore.GetEventTypeByName("alarm")
+
+	// The client's authors and dates are replaced, its dates kept as device dates
+	if eventType.Lcreated != 7 || eventType.Lmodified != 9 || eventType.Lcreated1.Before(before.Time) || eventType.Lmodified2.Before(before.Time) {
+		t.Fatalf("stamps %+v", eventType)
+	}
+
+	if eventType.Ldevicecreated.String() != "2019-01-01T04:00:55Z" || eventType.Ldevicemodified.String() != "2019-02-01T10:00:00Z" {
+		t.Fatalf("device dates %+v", eventType)
+	}
+
+	if activities := logStore.Activities(); len(activities) != 2 || activities[0].UserId != 7 || activities[1].UserId != 9 {
+		t.Fatalf("activity log %v", activities)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+
+	router, logStore := newTestServer(t, config.NGCSLogConfig{})
+
+	mustSend(t, router, "POST", "/Logs_Event_Type", eventTypeJSON)
+
+	sendKey := func(key string, body string) *httptest.ResponseRecorder {
+
+		request := httptest.NewRequest("POST", "/Logs_Event", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(IdempotencyKeyHeader, key)
+
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	first := sendKey("TE001-1", eventJSON)
+	retry := sendKey("TE001-1", eventJSON)
+
+	if first.Code != http.StatusCreated || retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Fatalf("retry: got %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+
+	if first.Header().Get(IdempotentReplayedHeader) != "" || retry.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Fatalf("%s headers %v, %v", IdempotentReplayedHeader, first.Header(), retry.Header())
+	}
+
+	if events, _ := logStore.ListEventLogs(); len(events) != 1 || len(logStore.Activities()) != 2 {
+		t.Fatalf("retry stored again: %d events, %d activities", len(events), len(logStore.Activities()))
+	}
+
+	if code := sendKey("TE001-1", strings.Replace(eventJSON, "TE001", "TE002", 1)).Code; code != http.StatusUnprocessableEntity {
+		t.Fatalf("key reused by another request: %d", code)
+	}
+
+	// A failed request stored nothing, its corrected retry is stored
+	if code := sendKey("TE003-1", `{"log_id":"TE003"}`).Code; code != http.StatusBadRequest {
+		t.Fatalf("invalid request: %d", code)
+	}
+
+	if code := sendKey("TE003-1", strings.Replace(eventJSON, "TE001", "TE003", 1)).Code; code != http.StatusCreated {
+		t.Fatalf("corrected retry: %d", code)
+	}
+
+	claimIdempotencyKey(0, "TE004-1")
+
+	if code := sendKey("TE004-1", eventJSON).Code; code != http.StatusConflict {
+		t.Fatalf("key in progress: %d", code)
+	}
+
+	releaseIdempotencyKey(0, "TE004-1")
+
+	if code := sendKey(strings.Repeat("k", maxIdempotencyKey+1), eventJSON).Code; code != http.StatusBadRequest {
+		t.Fatalf("long key: %d", code)
+	}
+
+	// Without a key every request is stored
+	mustSend(t, router, "POST", "/Logs_Event", eventJSON)
+
+	if events, _ := logStore.ListEventLogs(); len(events) != 3 {
+		t.Fatalf("%d events, want 3", len(events))
+	}
+}
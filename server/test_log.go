@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+	"github.com/gin-gonic/gin"
+)
+
+func processTest_Log(c *gin.Context) {
+
+	var log model.Logs_Test
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampCreated(c, &log)
+
+	id, err := logStore.InsertTestLog(&log, currentLogConfig().AutoCreateTypes == 1)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+
+	// Activity log
+
+	totaldata := map[string]interface{}{
+		"log_id":                log.Tid,
+		"log_name":              log.Tname,
+		"log_date_time":         log.Tdatetime,
+		"ZTK_Logs_Test_Type_id": log.Ttypeid,
+		"ZTK_Chamber_id":        log.Tchamberid,
+		"ZTK_Users_id":          log.Tuserid,
+		"created_by":            log.Tcreatedby,
+		"created":               log.Tcreated,
+		"modified_by":           log.Tmodifiedby,
+		"modified ":             log.Tmodified,
+	}
+	datat, _ := json.Marshal(totaldata)
+
+	recordActivity(c, "INSERT", string(datat))
+}
+
+func processTest_Evaluate(c *gin.Context) {
+
+	var version int
+	var err error
+
+	logId := c.Params.ByName("log_id")
+
+	if c.Query("version") != "" {
+
+		version, err = strconv.Atoi(c.Query("version"))
+
+		if err != nil {
+			respondError(c, http.StatusBadRequest, FieldError{Field: "version", Value: c.Query("version"), Reason: "is not a number"})
+			return
+		}
+	}
+
+	id, test, err := logStore.GetTestLog(logId)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	typeId := test.Ttypeid
+
+	profile, err := logStore.GetTestProfile(typeId, version)
+
+	if err == store.ErrNotFound {
+		respondError(c, http.StatusNotFound, FieldError{Field: "ZTK_Logs_Test_Type_id", Value: typeId, Reason: "has no profile"})
+		return
+	}
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	start := test.Tdatetime.Time
+
+	if start.IsZero() {
+		respondError(c, http.StatusUnprocessableEntity, FieldError{Field: "log_date_time_date", Reason: "is not a date time"})
+		return
+	}
+
+	end := start.Add(model.ProfileDuration(profile))
+
+	samples, err := getLoopData(test.Tchamberid, start, end)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	evaluation := model.EvaluateTestProfile(profile, start, samples)
+	evaluation.Elogid = logId
+
+	detail, _ := json.Marshal(evaluation)
+
+	err = logStore.SetTestVerdict(id, profile.Pid, evaluation.Everdict, string(detail))
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, logId, evaluation)
+
+	// Activity log
+
+	recordActivity(c, "UPDATE", string(detail))
+}
+
+func processTest_Evaluation(c *gin.Context) {
+
+	logId := c.Params.ByName("log_id")
+
+	var detail string
+
+	// The verdict of the latest test recorded with the log id
+	id, _, err := logStore.GetTestLog(logId)
+
+	if err == nil {
+		detail, err = logStore.GetTestVerdict(id)
+	}
+
+	if err == store.ErrNotFound {
+		respondError(c, http.StatusNotFound, FieldError{Field: "log_id", Value: logId, Reason: "is not evaluated"})
+		return
+	}
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, logId, json.RawMessage(detail))
+}
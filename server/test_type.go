@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+)
+
+func processTest_typeLog(c *gin.Context) {
+
+	var log model.Logs_Test_Type
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampCreated(c, &log)
+
+	id, err := logStore.InsertTestType(&log)
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusCreated, id, nil)
+}
+
+func processTest_typeByName(c *gin.Context) {
+
+	log, err := logStore.GetTestTypeByName(c.Params.ByName("name"))
+
+	respondTypeByName(c, "test_type", log, err)
+}
+
+func processTest_typeUpdate(c *gin.Context) {
+
+	var log model.Logs_Test_Type
+
+	if !bindLog(c, &log) {
+		return
+	}
+
+	stampModified(c, &log)
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	err := logStore.UpdateTestType(id, &log)
+
+	if respondTypeChange(c, "test_type", id, err) {
+
+		datat, _ := json.Marshal(log)
+
+		recordActivity(c, "UPDATE", string(datat))
+	}
+}
+
+func processTest_typeRetire(c *gin.Context) {
+
+	id, ok := typeId(c)
+
+	if !ok {
+		return
+	}
+
+	err := logStore.RetireTestType(id)
+
+	if respondTypeChange(c, "test_type", id, err) {
+		recordActivity(c, "RETIRE", fmt.Sprintf("{\"ZTK_Logs_Test_Type_id\":%d}", id))
+	}
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ramcharanpakala/goprojectes/store"
+	"github.com/gin-gonic/gin"
+)
+
+// Id of the type row in the path. Responds 400 and returns false when it is
+// not a number.
+func typeId(c *gin.Context) (int, bool) {
+
+	id, err := strconv.Atoi(c.Params.ByName("id"))
+
+	if err != nil {
+		respondError(c, http.StatusBadRequest, FieldError{Field: "id", Value: c.Params.ByName("id"), Reason: "is not a number"})
+		return 0, false
+	}
+
+	return id, true
+}
+
+// Send the response for an update or retire of a type row and report
+// whether a row was changed.
+func respondTypeChange(c *gin.Context, name string, id int, err error) bool {
+
+	if err == store.ErrNotFound {
+		respondError(c, http.StatusNotFound, FieldError{Field: "id", Value: id, Reason: "no such " + name})
+		return false
+	}
+
+	if err != nil {
+		respondStoreError(c, err)
+		return false
+	}
+
+	respondOK(c, http.StatusOK, id, nil)
+
+	return true
+}
+
+// Send the row of a type found by name
+func respondTypeByName(c *gin.Context, name string, log interface{}, err error) {
+
+	if err == store.ErrNotFound {
+		respondError(c, http.StatusNotFound, FieldError{Field: name, Value: c.Params.ByName("name"), Reason: "no such " + name})
+		return
+	}
+
+	if err != nil {
+		respondStoreError(c, err)
+		return
+	}
+
+	respondOK(c, http.StatusOK, nil, log)
+}
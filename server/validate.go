@@ -0,0 +1,161 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+
+	// Name the fields of validation errors as they are named in the request
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+			if name == "-" {
+				return ""
+			}
+
+			return name
+		})
+
+		// A model.Time is checked as its time.Time, or as the JSON value
+		// it was read from when that is not a date time
+		v.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+
+			t := field.Interface().(model.Time)
+
+			if t.Invalid() == "" {
+				return t.Time
+			}
+
+			var value interface{}
+
+			json.Unmarshal([]byte(t.Invalid()), &value)
+
+			return value
+		}, model.Time{})
+
+		v.RegisterValidation("time", func(fl validator.FieldLevel) bool {
+
+			_, ok := fl.Field().Interface().(time.Time)
+
+			return ok
+		})
+	}
+}
+
+// Decode the JSON request into log and check the rules of its binding tags.
+// Responds 400 listing every invalid field and returns false when the
+// request cannot be stored. Date times a chamber sent without a zone are
+// taken to be in its zone.
+func bindLog(c *gin.Context, log interface{}) bool {
+
+	err := c.ShouldBindJSON(log)
+
+	if err == nil {
+
+		if zoned, ok := log.(model.ChamberZoned); ok {
+			zoned.ChamberInZone(chamberLocation)
+		}
+
+		// Forward the request as read, so date times reach the remote
+		// server in UTC whatever the zone of this chamber
+		if body, err := json.Marshal(log); err == nil {
+			c.Set(forwardBodyKey, body)
+		}
+
+		return true
+	}
+
+	fieldErrors := requestErrors(err)
+
+	logf("warn", "Invalid %s %s: %v", c.Request.Method, c.Request.URL.Path, fieldErrors)
+
+	respondError(c, http.StatusBadRequest, fieldErrors...)
+
+	return false
+}
+
+// Fields of the request named by a ShouldBindJSON error
+func requestErrors(err error) []FieldError {
+
+	var validationErrors validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+
+	switch {
+
+	case errors.As(err, &validationErrors):
+		var fieldErrors []FieldError
+
+		for _, e := range validationErrors {
+
+			// The namespace starts with the name of the request type
+			_, field, _ := strings.Cut(e.Namespace(), ".")
+
+			fieldErrors = append(fieldErrors, FieldError{Field: field, Value: e.Value(), Reason: ruleReason(e)})
+		}
+
+		return fieldErrors
+
+	case errors.As(err, &typeErr):
+		return []FieldError{{Field: typeErr.Field, Reason: "must be a " + jsonType(typeErr.Type)}}
+
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return []FieldError{{Reason: "request is not valid JSON: " + err.Error()}}
+
+	case errors.Is(err, io.EOF):
+		return []FieldError{{Reason: "request body must be a JSON object"}}
+	}
+
+	return []FieldError{{Reason: err.Error()}}
+}
+
+// Reason of a broken binding rule, for the response
+func ruleReason(e validator.FieldError) string {
+
+	switch e.Tag() {
+	case "required":
+		return "is required"
+	case "time":
+		return "must be a date time like " + model.TimeExample
+	case "gte":
+		return "must be at least " + e.Param()
+	case "lte":
+		return "must be at most " + e.Param()
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(e.Param(), " ", ", ")
+	case "timezone":
+		return "must be a time zone like Europe/Berlin or UTC"
+	}
+
+	return "breaks the rule " + e.Tag() + "=" + e.Param()
+}
+
+// Name of a Go type as a JSON type
+func jsonType(t reflect.Type) string {
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int64, reflect.Float64:
+		return "number"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+
+	return t.String()
+}
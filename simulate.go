@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	logclient "github.com/Ramcharanpakala/goprojectes/client"
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/simulator"
+)
+
+// Errors of the log server printed before they are only counted
+const simulateErrorsShown = 5
+
+// Run simulated climate chambers through a test profile and post their
+// Loop_Data, events and maintenance logs to the log server in
+// ngcsLogConfig.json, e.g.
+//
+//	ngcslog simulate
+//	ngcslog simulate -type 3 -speed 60
+//	ngcslog simulate -profile profile.json -chambers 20 -speed 0 -faults 2
+func simulate(args []string) {
+
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	loader := config.NewLoader(flags)
+	testType := flags.Int("type", 0, "run the profile of this test type id and post a Logs_Test, so the run can be evaluated")
+	profileFile := flags.String("profile", "", "run the Logs_Test_Profile in this JSON file instead of the built in one")
+	speed := flags.Float64("speed", 1, "seconds of chamber time in a second, 0 for as fast as the log server takes them")
+	interval := flags.Duration("interval", 5*time.Second, "chamber time between Loop_Data samples")
+	faults := flags.Float64("faults", 0.5, "average number of faults of a chamber in an hour, 0 for none")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed of the noise and faults, the same seed gives the same run")
+	chambers := flags.Int("chambers", 1, "number of chambers run at the same time")
+	start := flags.String("start", "", "date time the profile starts, default now")
+	logId := flags.String("log-id", "SIM", "log id of the run, chambers are numbered as in SIM-1 and registered under it")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: ngcslog simulate [flags]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	if flags.NArg() != 0 || *chambers < 1 || *interval <= 0 || *speed < 0 || *faults < 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	if *testType != 0 && *profileFile != "" {
+		fail(2, "-type and -profile cannot be used together.")
+	}
+
+	startTime := time.Now()
+
+	if *start != "" {
+		startTime = parseSince(*start, "-start").Time
+	}
+
+	logServer := logclient.NewLocal(loadConfig(loader, config.Config.ValidateLog).Log)
+
+	profile := simulator.DefaultProfile
+
+	if *profileFile != "" {
+		profile = readProfile(*profileFile)
+	}
+
+	if *testType != 0 {
+
+		var err error
+
+		profile, err = logServer.GetTestProfile(*testType, 0)
+
+		if err != nil {
+			fail(1, "Unable to read the profile of test type %d: %s", *testType, err.Error())
+		}
+	}
+
+	if len(profile.Psteps) == 0 {
+		fail(2, "The profile has no steps.")
+	}
+
+	sink := newServerSink(logServer)
+
+	stop := make(chan struct{})
+
+	interrupt := make(chan os.Signal, 1)
+
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-interrupt
+		close(stop)
+	}()
+
+	fmt.Printf("Running %d chambers through %q, %s of chamber time.\n", *chambers, profile.Pcomment, model.ProfileDuration(profile))
+
+	began := time.Now()
+
+	var mu sync.Mutex
+	var total simulator.Stats
+	var wg sync.WaitGroup
+
+	registered, err := logServer.ListChambers()
+
+	if err != nil {
+		fail(1, "Unable to read the chambers: %s", err.Error())
+	}
+
+	for i := 1; i <= *chambers; i++ {
+
+		id := *logId
+
+		if *chambers > 1 {
+			id = fmt.Sprintf("%s-%d", *logId, i)
+		}
+
+		chamberId := registerChamber(logServer, registered, id)
+
+		if *testType != 0 {
+
+			test := model.Logs_Test{Tid: id, Tname: "simulated " + profile.Pcomment, Tdatetime: model.NewTime(startTime), Ttypeid: *testType, Tchamberid: chamberId}
+
+			if _, err := logServer.InsertTestLog(&test); err != nil {
+				fail(1, "Unable to post the test log %s: %s", id, err.Error())
+			}
+		}
+
+		chamber := simulator.NewChamber(*seed + int64(i))
+		chamber.FaultsPerHour = *faults
+
+		run := simulator.Run{
+			Chamber:      chamber,
+			Profile:      profile,
+			LogId:        id,
+			ChamberId:    chamberId,
+			Start:        startTime,
+			Interval:     *interval,
+			Speed:        *speed,
+			ServiceHours: 2000,
+		}
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			stats := run.Execute(sink, stop)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			total.LoopData += stats.LoopData
+			total.Events += stats.Events
+			total.Maintenance += stats.Maintenance
+			total.Skipped += stats.Skipped
+			total.Failed += stats.Failed
+		}()
+	}
+
+	wg.Wait()
+
+	took := time.Since(began)
+
+	fmt.Printf("Posted %d Loop_Data samples, %d events and %d maintenance logs in %s, %.1f records a second.\n",
+		total.LoopData, total.Events, total.Maintenance, took.Round(time.Millisecond), float64(total.Total())/took.Seconds())
+
+	if total.Skipped > 0 {
+		fmt.Printf("Skipped %d events of retired event types.\n", total.Skipped)
+	}
+
+	if total.Failed > 0 {
+		fail(1, "%d records were not stored.", total.Failed)
+	}
+}
+
+// Id of the chamber registered under the name, registering it when it is
+// not one of the registered chambers
+func registerChamber(logServer *logclient.Client, registered []model.Chamber, name string) int {
+
+	for _, chamber := range registered {
+
+		if chamber.Cname == name {
+			return chamber.Cid
+		}
+	}
+
+	id, err := logServer.InsertChamber(&model.Chamber{Cname: name, Clocation: "simulator"})
+
+	if err != nil {
+		fail(1, "Unable to register the chamber %s: %s", name, err.Error())
+	}
+
+	return int(id)
+}
+
+// Read a Logs_Test_Profile from a JSON file
+func readProfile(path string) model.Logs_Test_Profile {
+
+	contents, err := os.ReadFile(path)
+
+	if err != nil {
+		fail(1, "Unable to read the profile file: %s", err.Error())
+	}
+
+	var profile model.Logs_Test_Profile
+
+	err = json.Unmarshal(contents, &profile)
+
+	if err != nil {
+		fail(2, "Profile file %s: %s", path, err.Error())
+	}
+
+	err = model.ValidateTestProfile(profile)
+
+	if err != nil {
+		fail(2, "Profile file %s: %s", path, err.Error())
+	}
+
+	return profile
+}
+
+// Struct to hold the log server the simulated chambers post to. Event types
+// are created the first time they are used, so the run does not depend on
+// AutoCreateTypes. The events of a type retired on the log server are
+// skipped with simulator.ErrSkipped, as it rejects them; eventTypes holds id
+// 0 for such a type.
+
+type serverSink struct {
+	logServer *logclient.Client
+
+	mu         sync.Mutex
+	eventTypes map[string]int
+	errors     int
+}
+
+func newServerSink(logServer *logclient.Client) *serverSink {
+	return &serverSink{logServer: logServer, eventTypes: map[string]int{}}
+}
+
+func (s *serverSink) LoopData(log *model.Loop_Data) error {
+
+	_, err := s.logServer.InsertLoopData(log)
+
+	return s.report(err)
+}
+
+func (s *serverSink) Event(log *model.Logs_Event) error {
+
+	id, err := s.eventType(log.Etypename)
+
+	if err != nil {
+		return s.report(err)
+	}
+
+	if id == 0 {
+		return simulator.ErrSkipped
+	}
+
+	log.Etypeid = id
+
+	_, err = s.logServer.InsertEventLog(log)
+
+	return s.report(err)
+}
+
+func (s *serverSink) Maintenance(log *model.Logs_Maintenance) error {
+
+	_, err := s.logServer.InsertMaintenanceLog(log)
+
+	return s.report(err)
+}
+
+// Id of the event type with the name, created when the log server does not
+// have it, or 0 when it is retired
+func (s *serverSink) eventType(name string) (int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if id, ok := s.eventTypes[name]; ok {
+		return id, nil
+	}
+
+	eventType, err := s.logServer.GetEventTypeByName(name)
+
+	var notFound *logclient.Error
+
+	if errors.As(err, &notFound) && notFound.StatusCode == http.StatusNotFound {
+
+		eventType = model.Logs_Event_Type{Levents: name, Lactive: 1}
+
+		var id int64
+
+		id, err = s.logServer.InsertEventType(&eventType)
+
+		eventType.Lid = int(id)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if eventType.Lactive == 0 {
+
+		fmt.Fprintf(os.Stderr, "Event type %q is retired, its events are not posted.\n", name)
+
+		eventType.Lid = 0
+	}
+
+	s.eventTypes[name] = eventType.Lid
+
+	return eventType.Lid, nil
+}
+
+// Print the first errors of the log server, the later ones are only counted
+func (s *serverSink) report(err error) error {
+
+	if err == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors++
+
+	if s.errors <= simulateErrorsShown {
+		fmt.Fprintln(os.Stderr, "Error:", err.Error())
+	}
+
+	if s.errors == simulateErrorsShown {
+		fmt.Fprintln(os.Stderr, "Error: Further errors are only counted.")
+	}
+
+	return err
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	logclient "github.com/Ramcharanpakala/goprojectes/client"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/simulator"
+)
+
+func TestServerSinkEventTypes(t *testing.T) {
+
+	var mu sync.Mutex
+
+	var lookups int
+	var posted []int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method + " " + r.URL.Path {
+
+		case "GET /Logs_Event_Type/name/trips":
+			lookups++
+			w.Write([]byte(`{"status":"ok","data":{"id":3,"active":0,"events_type":"trips"}}`))
+
+		case "GET /Logs_Event_Type/name/door":
+			lookups++
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"status":"error","errors":[{"reason":"not found"}]}`))
+
+		case "POST /Logs_Event_Type":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"status":"ok","id":4}`))
+
+		case "POST /Logs_Event":
+			var log model.Logs_Event
+			json.NewDecoder(r.Body).Decode(&log)
+			posted = append(posted, log.Etypeid)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"status":"ok","id":1}`))
+
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	defer server.Close()
+
+	sink := newServerSink(logclient.New(server.URL))
+
+	for i := 0; i < 2; i++ {
+
+		for _, name := range []string{"trips", "door"} {
+
+			err := sink.Event(&model.Logs_Event{Etypename: name})
+
+			if name == "trips" && !errors.Is(err, simulator.ErrSkipped) || name == "door" && err != nil {
+				t.Fatalf("event %s: %v", name, err)
+			}
+		}
+	}
+
+	// The retired type is looked up once and its events skipped, the
+	// missing one is created
+	if lookups != 2 || len(posted) != 2 || posted[0] != 4 || posted[1] != 4 {
+		t.Fatalf("%d lookups, posted events of types %v", lookups, posted)
+	}
+
+	// Skipped events are not errors of the log server
+	if sink.errors != 0 {
+		t.Fatalf("%d errors reported", sink.errors)
+	}
+}
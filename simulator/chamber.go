@@ -0,0 +1,223 @@
+// Package simulator models a climate chamber following a test profile, so
+// the log server can be demonstrated and load tested without hardware. The
+// chamber reaches its setpoints with a thermal lag, its humidity follows the
+// temperature, its sensors are noisy and now and then a part fails.
+package simulator
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Struct to hold the temperature in °C, relative humidity in % and pressure
+// of a chamber
+
+type Reading struct {
+	Temp  float64
+	Hum   float64
+	Press float64
+}
+
+// Struct to hold a part of the chamber that fails, and how its failure
+// changes where the chamber is heading
+
+type fault struct {
+	name      string
+	component string
+	apply     func(target *Reading, ambient Reading)
+}
+
+// Faults the chamber may have, with the component they are logged against
+var faults = []fault{
+	{"heater failure", "heater", func(target *Reading, ambient Reading) {
+		target.Temp = math.Min(target.Temp, ambient.Temp)
+	}},
+	{"compressor failure", "compressor", func(target *Reading, ambient Reading) {
+		target.Temp = math.Max(target.Temp, ambient.Temp)
+	}},
+	{"humidifier failure", "humidifier", func(target *Reading, ambient Reading) {
+		target.Hum = math.Min(target.Hum, ambient.Hum)
+	}},
+	{"door open", "door", func(target *Reading, ambient Reading) {
+		*target = ambient
+	}},
+	{"temperature sensor stuck", "temperature sensor", nil},
+}
+
+// Components of the chamber, the maintenance logs are posted for
+func Components() []string {
+
+	var components []string
+
+	for _, f := range faults {
+		components = append(components, f.component)
+	}
+
+	return components
+}
+
+// Struct to hold something that happened in the chamber: a fault starting
+// or clearing
+
+type Event struct {
+	Name      string
+	Component string
+	Fault     bool
+}
+
+// Struct to hold a simulated climate chamber. The exported fields may be
+// changed before the first Step.
+//
+// Each channel moves towards its target with a first order lag: after Lag it
+// has covered 63% of the way. The relative humidity also changes with the
+// temperature, as the same amount of water vapour is a lower share of what
+// warmer air can hold. The sensors add normally distributed noise.
+
+type Chamber struct {
+	TempLag  time.Duration
+	HumLag   time.Duration
+	PressLag time.Duration
+
+	TempNoise  float64
+	HumNoise   float64
+	PressNoise float64
+
+	// Average number of faults in an hour, 0 for none
+	FaultsPerHour float64
+
+	// Conditions of the room the chamber drifts to when it is not controlled
+	Ambient Reading
+
+	rand   *rand.Rand
+	actual Reading
+	sensed Reading
+	fault  *fault
+	left   time.Duration
+}
+
+// Chamber at ambient conditions with the lags and noise of a typical chamber.
+// The same seed gives the same run.
+func NewChamber(seed int64) *Chamber {
+
+	ambient := Reading{Temp: 22, Hum: 45, Press: 0}
+
+	return &Chamber{
+		TempLag:       2 * time.Minute,
+		HumLag:        time.Minute,
+		PressLag:      30 * time.Second,
+		TempNoise:     0.15,
+		HumNoise:      0.5,
+		PressNoise:    0.05,
+		FaultsPerHour: 0.5,
+		Ambient:       ambient,
+		rand:          rand.New(rand.NewSource(seed)),
+		actual:        ambient,
+		sensed:        ambient,
+	}
+}
+
+// Conditions in the chamber, without sensor noise
+func (c *Chamber) Actual() Reading {
+	return c.actual
+}
+
+// Advance the chamber by dt towards the setpoint and return what its sensors
+// read, with the faults that started or cleared. A humidity or pressure
+// setpoint of 0 leaves that channel uncontrolled.
+func (c *Chamber) Step(setpoint Reading, dt time.Duration) (Reading, []Event) {
+
+	events := c.updateFault(dt)
+
+	target := setpoint
+
+	if target.Hum == 0 {
+		target.Hum = c.Ambient.Hum
+	}
+
+	if target.Press == 0 {
+		target.Press = c.Ambient.Press
+	}
+
+	if c.fault != nil && c.fault.apply != nil {
+		c.fault.apply(&target, c.Ambient)
+	}
+
+	before := c.actual.Temp
+
+	c.actual.Temp = approach(c.actual.Temp, target.Temp, dt, c.TempLag)
+
+	// Same vapour pressure at the new temperature
+	c.actual.Hum *= saturation(before) / saturation(c.actual.Temp)
+
+	c.actual.Hum = clamp(approach(c.actual.Hum, target.Hum, dt, c.HumLag), 0, 100)
+	c.actual.Press = approach(c.actual.Press, target.Press, dt, c.PressLag)
+
+	stuck := c.fault != nil && c.fault.component == "temperature sensor"
+
+	if !stuck {
+		c.sensed.Temp = round(c.actual.Temp + c.rand.NormFloat64()*c.TempNoise)
+	}
+
+	c.sensed.Hum = round(clamp(c.actual.Hum+c.rand.NormFloat64()*c.HumNoise, 0, 100))
+	c.sensed.Press = round(math.Max(0, c.actual.Press+c.rand.NormFloat64()*c.PressNoise))
+
+	return c.sensed, events
+}
+
+// Clear a fault that has run its time, or start one at random
+func (c *Chamber) updateFault(dt time.Duration) []Event {
+
+	if c.fault != nil {
+
+		c.left -= dt
+
+		if c.left > 0 {
+			return nil
+		}
+
+		cleared := c.fault
+		c.fault = nil
+
+		return []Event{{Name: cleared.name + " cleared", Component: cleared.component}}
+	}
+
+	if c.FaultsPerHour <= 0 || c.rand.Float64() >= 1-math.Exp(-c.FaultsPerHour*dt.Hours()) {
+		return nil
+	}
+
+	c.fault = &faults[c.rand.Intn(len(faults))]
+
+	// Between 2 and 15 minutes
+	c.left = 2*time.Minute + time.Duration(c.rand.Int63n(int64(13*time.Minute)))
+
+	return []Event{{Name: c.fault.name, Component: c.fault.component, Fault: true}}
+}
+
+// Value after moving from value towards target for dt with the lag
+func approach(value float64, target float64, dt time.Duration, lag time.Duration) float64 {
+
+	if lag <= 0 {
+		return target
+	}
+
+	return value + (target-value)*(1-math.Exp(-dt.Seconds()/lag.Seconds()))
+}
+
+// Saturation vapour pressure of water in hPa at a temperature in °C, after
+// the Magnus formula
+func saturation(temp float64) float64 {
+
+	return 6.112 * math.Exp(17.62*temp/(243.12+temp))
+}
+
+func clamp(value float64, low float64, high float64) float64 {
+
+	return math.Max(low, math.Min(high, value))
+}
+
+// Round to the 2 decimals the controllers report
+func round(value float64) float64 {
+
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,268 @@
+package simulator
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Profile run when none is given: up to a damp heat, down to a cold soak and
+// back to room conditions, 2 hours 40 minutes in all
+var DefaultProfile = model.Logs_Test_Profile{
+	Pcomment: "damp heat and cold",
+	Psteps: []model.Logs_Test_Profile_Step{
+		{Stype: "ramp", Stsp: 40, Shsp: 75, Sduration: 20, Sttol: 5, Shtol: 8},
+		{Stype: "soak", Stsp: 40, Shsp: 75, Sduration: 40, Sttol: 1, Shtol: 5},
+		{Stype: "ramp", Stsp: -10, Sduration: 30, Sttol: 5},
+		{Stype: "soak", Stsp: -10, Sduration: 40, Sttol: 1},
+		{Stype: "ramp", Stsp: 22, Shsp: 45, Sduration: 30, Sttol: 5, Shtol: 8},
+	},
+}
+
+// Setpoint of the profile after elapsed, with the index of its step. A ramp
+// moves linearly from the setpoint of the step before, the first one from
+// start. A humidity or pressure setpoint of 0 leaves the channel
+// uncontrolled for the step, and the next ramp starts it from start. False
+// once the profile has ended.
+func Setpoint(profile model.Logs_Test_Profile, start Reading, elapsed time.Duration) (Reading, int, bool) {
+
+	from := start
+
+	for i, step := range profile.Psteps {
+
+		to := Reading{Temp: step.Stsp, Hum: step.Shsp, Press: step.Spsp}
+		duration := time.Duration(step.Sduration) * time.Minute
+
+		if elapsed < duration {
+
+			if step.Stype != "ramp" {
+				return to, i, true
+			}
+
+			share := float64(elapsed) / float64(duration)
+
+			setpoint := Reading{
+				Temp:  from.Temp + (to.Temp-from.Temp)*share,
+				Hum:   from.Hum + (to.Hum-from.Hum)*share,
+				Press: from.Press + (to.Press-from.Press)*share,
+			}
+
+			if to.Hum == 0 {
+				setpoint.Hum = 0
+			}
+
+			if to.Press == 0 {
+				setpoint.Press = 0
+			}
+
+			return setpoint, i, true
+		}
+
+		elapsed -= duration
+		from = to
+
+		if to.Hum == 0 {
+			from.Hum = start.Hum
+		}
+
+		if to.Press == 0 {
+			from.Press = start.Press
+		}
+	}
+
+	return from, len(profile.Psteps), false
+}
+
+// Returned by a sink for a record it does not post, e.g. an event of a type
+// retired on the log server
+var ErrSkipped = errors.New("record skipped")
+
+// Struct to hold where a run posts its records
+
+type Sink interface {
+	LoopData(log *model.Loop_Data) error
+	Event(log *model.Logs_Event) error
+	Maintenance(log *model.Logs_Maintenance) error
+}
+
+// Struct to hold the records a run posted and how many of them the sink
+// skipped or failed to take
+
+type Stats struct {
+	LoopData    int
+	Events      int
+	Maintenance int
+	Skipped     int
+	Failed      int
+}
+
+func (s Stats) Total() int {
+	return s.LoopData + s.Events + s.Maintenance
+}
+
+// Struct to hold a run of a chamber through a profile.
+//
+// A Loop_Data sample is posted every Interval of chamber time, an event when
+// the profile starts, a ramp or soak step starts, a fault starts or clears
+// and the profile ends, and a maintenance log for every component each hour of
+// chamber time and for the component of every fault. Speed is how many
+// seconds of chamber time pass in a second, 0 runs as fast as the sink
+// takes the records. Every record carries ChamberId, 0 when the chamber is
+// not registered.
+
+type Run struct {
+	Chamber   *Chamber
+	Profile   model.Logs_Test_Profile
+	LogId     string
+	ChamberId int
+	Start     time.Time
+	Interval  time.Duration
+	Speed     float64
+
+	// Hours the components have run before the run, and between services;
+	// a component is due for service when less than a day is left
+	RuntimeHours int
+	ServiceHours int
+}
+
+// Run the chamber through the profile, posting to the sink, until the
+// profile ends or stop is closed. Records the sink skips or fails to take
+// are counted apart from the posted ones.
+func (r *Run) Execute(sink Sink, stop <-chan struct{}) Stats {
+
+	var stats Stats
+
+	post := func(err error, count *int) {
+
+		if errors.Is(err, ErrSkipped) {
+			stats.Skipped++
+			return
+		}
+
+		if err != nil {
+			stats.Failed++
+			return
+		}
+
+		*count++
+	}
+
+	start := model.NewTime(r.Start)
+	begin := r.Chamber.Actual()
+	faults := map[string]int{}
+
+	event := func(name string, at time.Time) {
+
+		log := model.Logs_Event{Lid: r.LogId, Pname: r.Profile.Pcomment, Pdatetime: start, Etypename: name, Echamberid: r.ChamberId, Ecreated: model.NewTime(at)}
+
+		post(sink.Event(&log), &stats.Events)
+	}
+
+	maintenance := func(component string, at time.Time) {
+
+		hours := r.RuntimeHours + int(at.Sub(r.Start).Hours())
+		days := 0
+
+		if r.ServiceHours > 0 {
+			days = (r.ServiceHours - hours%r.ServiceHours) / 24
+		}
+
+		pending := 0
+
+		if faults[component] > 0 || r.ServiceHours > 0 && days == 0 {
+			pending = 1
+		}
+
+		log := model.Logs_Maintenance{Mname: component, Mruntime: hours, Mcounter: faults[component], Mservice: days, Mpending: pending, Mchamberid: r.ChamberId, Mcreated: model.NewTime(at)}
+
+		post(sink.Maintenance(&log), &stats.Maintenance)
+	}
+
+	event("profile started", r.Start)
+
+	lastStep := -1
+	nextHour := time.Hour
+
+	for elapsed := time.Duration(0); ; elapsed += r.Interval {
+
+		at := r.Start.Add(elapsed)
+
+		setpoint, step, running := Setpoint(r.Profile, begin, elapsed)
+
+		if !running {
+			break
+		}
+
+		if step != lastStep {
+			event(r.Profile.Psteps[step].Stype+" started", at)
+			lastStep = step
+		}
+
+		reading, events := r.Chamber.Step(setpoint, r.Interval)
+
+		sample := model.Loop_Data{
+			Dtsp:       round(setpoint.Temp),
+			Dtpv:       reading.Temp,
+			Dhsp:       round(setpoint.Hum),
+			Dhpv:       reading.Hum,
+			Dpsp:       round(setpoint.Press),
+			Dppv:       reading.Press,
+			Ddatatime:  model.NewTime(at),
+			Dchamberid: r.ChamberId,
+		}
+
+		post(sink.LoopData(&sample), &stats.LoopData)
+
+		for _, happened := range events {
+
+			event(happened.Name, at)
+
+			if happened.Fault {
+				faults[happened.Component]++
+				maintenance(happened.Component, at)
+			}
+		}
+
+		if elapsed >= nextHour {
+
+			for _, component := range Components() {
+				maintenance(component, at)
+			}
+
+			nextHour += time.Hour
+		}
+
+		if !r.wait(stop) {
+			return stats
+		}
+	}
+
+	event("profile finished", r.Start.Add(model.ProfileDuration(r.Profile)))
+
+	return stats
+}
+
+// Wait for the next sample at Speed, false when stop is closed
+func (r *Run) wait(stop <-chan struct{}) bool {
+
+	if r.Speed <= 0 {
+
+		select {
+		case <-stop:
+			return false
+		default:
+			return true
+		}
+	}
+
+	timer := time.NewTimer(time.Duration(float64(r.Interval) / r.Speed))
+	defer timer.Stop()
+
+	select {
+	case <-stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,260 @@
+package simulator
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+func TestSetpoint(t *testing.T) {
+
+	start := Reading{Temp: 20, Hum: 50}
+
+	tests := []struct {
+		elapsed time.Duration
+		want    Reading
+		step    int
+		running bool
+	}{
+		{0, Reading{Temp: 20, Hum: 50}, 0, true},
+		{10 * time.Minute, Reading{Temp: 30, Hum: 62.5}, 0, true},
+		{30 * time.Minute, Reading{Temp: 40, Hum: 75}, 1, true},
+		// Humidity is not controlled below freezing
+		{75 * time.Minute, Reading{Temp: 15}, 2, true},
+		// and ramps up again from start
+		{155 * time.Minute, Reading{Temp: 16 + 2.0/3, Hum: 45 + 5.0/6}, 4, true},
+		{160 * time.Minute, Reading{Temp: 22, Hum: 45}, 5, false},
+	}
+
+	for _, test := range tests {
+
+		got, step, running := Setpoint(DefaultProfile, start, test.elapsed)
+
+		if math.Abs(got.Temp-test.want.Temp) > 1e-9 || math.Abs(got.Hum-test.want.Hum) > 1e-9 || step != test.step || running != test.running {
+			t.Errorf("Setpoint after %s = %+v, %d, %t, want %+v, %d, %t", test.elapsed, got, step, running, test.want, test.step, test.running)
+		}
+	}
+}
+
+// Chamber without noise or faults, so only the lag and coupling are left
+func quietChamber() *Chamber {
+
+	c := NewChamber(1)
+
+	c.TempNoise, c.HumNoise, c.PressNoise = 0, 0, 0
+	c.FaultsPerHour = 0
+
+	return c
+}
+
+func TestChamberLag(t *testing.T) {
+
+	c := quietChamber()
+
+	setpoint := Reading{Temp: 122, Hum: 45}
+
+	for elapsed := time.Duration(0); elapsed < c.TempLag; elapsed += 5 * time.Second {
+		c.Step(setpoint, 5*time.Second)
+	}
+
+	// 63% of the way from 22 to 122 after one lag
+	if got := c.Actual().Temp; math.Abs(got-85.2) > 0.1 {
+		t.Fatalf("temperature after one lag %.2f, want 85.2", got)
+	}
+}
+
+func TestHumidityFollowsTemperature(t *testing.T) {
+
+	c := quietChamber()
+
+	c.HumLag = time.Hour
+
+	reading, _ := c.Step(Reading{Temp: 40}, 10*time.Minute)
+
+	// Warmer air holds more water, so the relative humidity drops
+	if reading.Temp <= 30 || reading.Hum >= 30 {
+		t.Fatalf("after heating to %.2f °C humidity is %.2f %%", reading.Temp, reading.Hum)
+	}
+
+	c = quietChamber()
+
+	reading, _ = c.Step(Reading{Temp: 22, Hum: 80}, 10*time.Minute)
+
+	if reading.Hum < 79 {
+		t.Fatalf("controlled humidity %.2f %%, want 80", reading.Hum)
+	}
+}
+
+func TestSameSeedSameRun(t *testing.T) {
+
+	a, b := NewChamber(7), NewChamber(7)
+
+	a.FaultsPerHour, b.FaultsPerHour = 20, 20
+
+	for i := 0; i < 500; i++ {
+
+		setpoint := Reading{Temp: float64(i % 60), Hum: 60}
+
+		readingA, eventsA := a.Step(setpoint, 5*time.Second)
+		readingB, eventsB := b.Step(setpoint, 5*time.Second)
+
+		if readingA != readingB || len(eventsA) != len(eventsB) {
+			t.Fatalf("step %d: %+v %v and %+v %v", i, readingA, eventsA, readingB, eventsB)
+		}
+	}
+}
+
+// Struct to hold the records a run posted, failing every Loop_Data sample
+// after failAfter when it is set and skipping the events named skipEvent
+
+type sliceSink struct {
+	loopData    []model.Loop_Data
+	events      []model.Logs_Event
+	maintenance []model.Logs_Maintenance
+	failAfter   int
+	skipEvent   string
+}
+
+func (s *sliceSink) LoopData(log *model.Loop_Data) error {
+
+	if s.failAfter > 0 && len(s.loopData) >= s.failAfter {
+		return errors.New("log server down")
+	}
+
+	s.loopData = append(s.loopData, *log)
+
+	return nil
+}
+
+func (s *sliceSink) Event(log *model.Logs_Event) error {
+
+	if log.Etypename == s.skipEvent {
+		return ErrSkipped
+	}
+
+	s.events = append(s.events, *log)
+
+	return nil
+}
+
+func (s *sliceSink) Maintenance(log *model.Logs_Maintenance) error {
+
+	s.maintenance = append(s.maintenance, *log)
+
+	return nil
+}
+
+func TestExecute(t *testing.T) {
+
+	c := quietChamber()
+
+	start := time.Date(2019, 2, 11, 8, 0, 0, 0, time.UTC)
+
+	run := Run{Chamber: c, Profile: DefaultProfile, LogId: "SIM-1", ChamberId: 2, Start: start, Interval: 10 * time.Second, ServiceHours: 500}
+
+	sink := &sliceSink{}
+
+	stats := run.Execute(sink, nil)
+
+	// 2 hours 40 minutes every 10 seconds
+	if len(sink.loopData) != 960 || stats.LoopData != 960 || stats.Failed != 0 {
+		t.Fatalf("posted %d Loop_Data samples, stats %+v", len(sink.loopData), stats)
+	}
+
+	var names []string
+
+	for _, event := range sink.events {
+		names = append(names, event.Etypename)
+	}
+
+	want := []string{"profile started", "ramp started", "soak started", "ramp started", "soak started", "ramp started", "profile finished"}
+
+	if len(names) != len(want) {
+		t.Fatalf("events %v, want %v", names, want)
+	}
+
+	for i := range want {
+
+		if names[i] != want[i] {
+			t.Fatalf("events %v, want %v", names, want)
+		}
+	}
+
+	if !sink.events[6].Ecreated.Time.Equal(start.Add(160*time.Minute)) || sink.events[0].Lid != "SIM-1" || sink.events[0].Echamberid != 2 {
+		t.Fatalf("last event %+v", sink.events[6])
+	}
+
+	// The chamber follows the profile
+	soak := sink.loopData[359]
+
+	if soak.Dtsp != 40 || soak.Dchamberid != 2 || math.Abs(soak.Dtpv-40) > 0.5 || math.Abs(soak.Dhpv-75) > 1 {
+		t.Fatalf("end of the damp heat soak %+v", soak)
+	}
+
+	// Every component on every full hour
+	if len(sink.maintenance) != 2*len(Components()) || sink.maintenance[0].Mruntime != 1 || sink.maintenance[0].Mservice != 20 {
+		t.Fatalf("maintenance logs %+v", sink.maintenance)
+	}
+}
+
+func TestExecuteSkipped(t *testing.T) {
+
+	run := Run{Chamber: quietChamber(), Profile: DefaultProfile, LogId: "SIM-1", Start: time.Now(), Interval: 10 * time.Second}
+
+	sink := &sliceSink{skipEvent: "ramp started"}
+
+	stats := run.Execute(sink, nil)
+
+	// Three ramps are skipped, not counted as posted or failed
+	if stats.Skipped != 3 || stats.Events != 4 || stats.Failed != 0 || len(sink.events) != 4 {
+		t.Fatalf("stats %+v, posted %d events", stats, len(sink.events))
+	}
+}
+
+func TestExecuteWithFaults(t *testing.T) {
+
+	c := NewChamber(3)
+
+	c.FaultsPerHour = 6
+
+	run := Run{Chamber: c, Profile: DefaultProfile, LogId: "SIM-1", Start: time.Now(), Interval: 10 * time.Second}
+
+	sink := &sliceSink{failAfter: 100}
+
+	stats := run.Execute(sink, nil)
+
+	if stats.Failed != 860 || stats.LoopData != 100 {
+		t.Fatalf("stats %+v", stats)
+	}
+
+	faults := 0
+
+	for _, log := range sink.maintenance {
+
+		if log.Mcounter > 0 && log.Mpending == 1 {
+			faults++
+		}
+	}
+
+	if faults == 0 || len(sink.events) <= 7 {
+		t.Fatalf("no faults in %d events and %d maintenance logs", len(sink.events), len(sink.maintenance))
+	}
+}
+
+func TestExecuteStops(t *testing.T) {
+
+	run := Run{Chamber: quietChamber(), Profile: DefaultProfile, Start: time.Now(), Interval: time.Second, Speed: 1}
+
+	stop := make(chan struct{})
+
+	time.AfterFunc(50*time.Millisecond, func() { close(stop) })
+
+	stats := run.Execute(&sliceSink{}, stop)
+
+	if stats.LoopData == 0 || stats.LoopData > 2 {
+		t.Fatalf("stats %+v", stats)
+	}
+}
@@ -0,0 +1,86 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Check the name of a channel to be catalogued: it is required and must not
+// be the name of a Loop_Data column.
+func CheckChannelName(name string) error {
+
+	if name == "" {
+		return Invalid("name is required")
+	}
+
+	for _, column := range model.LoopDataColumns {
+
+		if name == column {
+			return Invalid("channel %q is a Loop_Data column", name)
+		}
+	}
+
+	return nil
+}
+
+// Check the range of a channel to be catalogued, see model.ValidateChannel
+func CheckChannelRange(channel model.Channel) error {
+
+	if err := model.ValidateChannel(channel); err != nil {
+		return Invalid("channel %q maximum %s", channel.Chname, err.Error())
+	}
+
+	return nil
+}
+
+// Check a value sent for a channel of the catalogue: the channel must be
+// active, its range valid and the value inside it.
+func CheckChannel(channel model.Channel, value float64) error {
+
+	if channel.Chactive == 0 {
+		return Invalid("channel %q is retired", channel.Chname)
+	}
+
+	if err := CheckChannelRange(channel); err != nil {
+		return err
+	}
+
+	if channel.Chminimum != nil && value < *channel.Chminimum {
+		return Invalid("channel %q value %v is below its minimum %v", channel.Chname, value, *channel.Chminimum)
+	}
+
+	if channel.Chmaximum != nil && value > *channel.Chmaximum {
+		return Invalid("channel %q value %v is above its maximum %v", channel.Chname, value, *channel.Chmaximum)
+	}
+
+	return nil
+}
+
+// Report whether a calibration names a process value column rather than a
+// channel of the catalogue
+func IsProcessValue(channel string) bool {
+
+	for _, column := range model.ProcessValueColumns {
+
+		if channel == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Names of the channels of a Loop_Data sample, sorted
+func ChannelNames(channels map[string]float64) []string {
+
+	var names []string
+
+	for name := range channels {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+func (s *Store) InsertCalibration(calibration *model.Calibration) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.checkChamber(calibration.Calchamberid)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if !store.IsProcessValue(calibration.Calchannel) && s.channelByName(calibration.Calchannel) == nil {
+		return 0, store.Invalid("channel %q is not a process value or a catalogued channel", calibration.Calchannel)
+	}
+
+	row := *calibration
+	row.Calid = len(s.calibrations) + 1
+
+	s.calibrations = append(s.calibrations, row)
+
+	return int64(row.Calid), nil
+}
+
+func (s *Store) ListCalibrations() ([]model.Calibration, error) {
+
+	return s.calibrationsWhere(func(model.Calibration) bool { return true })
+}
+
+func (s *Store) ListChamberCalibrations(chamberId int) ([]model.Calibration, error) {
+
+	return s.calibrationsWhere(func(calibration model.Calibration) bool {
+		return calibration.Calchamberid == chamberId
+	})
+}
+
+func (s *Store) calibrationsWhere(keep func(model.Calibration) bool) ([]model.Calibration, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	calibrations := []model.Calibration{}
+
+	for _, calibration := range s.calibrations {
+
+		if keep(calibration) {
+			calibrations = append(calibrations, calibration)
+		}
+	}
+
+	sort.SliceStable(calibrations, func(i, j int) bool {
+		return calibrations[i].Caldate.Before(calibrations[j].Caldate.Time)
+	})
+
+	return calibrations, nil
+}
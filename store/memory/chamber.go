@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+func (s *Store) InsertChamber(chamber *model.Chamber) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := len(s.chambers) + 1
+
+	err := s.checkChamberName(chamber.Cname, 0)
+
+	if err == nil {
+		err = s.checkCards(chamber.Ciocards, id)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	row := *chamber
+	row.Cid = id
+	row.Ciocards = append([]string{}, chamber.Ciocards...)
+
+	s.chambers = append(s.chambers, row)
+
+	return int64(id), nil
+}
+
+func (s *Store) ListChambers() ([]model.Chamber, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chambers := []model.Chamber{}
+
+	for _, chamber := range s.chambers {
+
+		chamber.Ciocards = append([]string{}, chamber.Ciocards...)
+
+		chambers = append(chambers, chamber)
+	}
+
+	return chambers, nil
+}
+
+func (s *Store) GetChamber(id int) (model.Chamber, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	chamber := s.chamberById(id)
+
+	if chamber == nil {
+		return model.Chamber{}, store.ErrNotFound
+	}
+
+	row := *chamber
+	row.Ciocards = append([]string{}, chamber.Ciocards...)
+
+	return row, nil
+}
+
+// Rename, move or refit a chamber. Its IO cards are replaced by the ones
+// given.
+func (s *Store) UpdateChamber(id int, chamber *model.Chamber) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.chamberById(id)
+
+	if row == nil {
+		return store.ErrNotFound
+	}
+
+	err := s.checkChamberName(chamber.Cname, id)
+
+	if err == nil {
+		err = s.checkCards(chamber.Ciocards, id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	row.Cname = chamber.Cname
+	row.Clocation = chamber.Clocation
+	row.Ctimezone = chamber.Ctimezone
+	row.Ciocards = append([]string{}, chamber.Ciocards...)
+	row.Cmodifiedby = chamber.Cmodifiedby
+	row.Cmodified = chamber.Cmodified
+
+	return nil
+}
+
+// IO cards whose serial numbers are fitted to the chamber
+func (s *Store) ListChamberIocardinfo(chamberId int) ([]model.Io_card_Info, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Io_card_Info{}
+
+	chamber := s.chamberById(chamberId)
+
+	if chamber == nil {
+		return logs, nil
+	}
+
+	for _, log := range s.iocardinfo {
+
+		for _, serial := range chamber.Ciocards {
+
+			if log.Inumber == serial {
+				logs = append(logs, log)
+				break
+			}
+		}
+	}
+
+	return logs, nil
+}
+
+func (s *Store) chamberById(id int) *model.Chamber {
+
+	for i := range s.chambers {
+
+		if s.chambers[i].Cid == id {
+			return &s.chambers[i]
+		}
+	}
+
+	return nil
+}
+
+// Check that name is set and not used by another chamber. exceptId is the id
+// of the chamber being renamed, or 0 for a new one.
+func (s *Store) checkChamberName(name string, exceptId int) error {
+
+	if name == "" {
+		return store.Invalid("name is required")
+	}
+
+	for _, chamber := range s.chambers {
+
+		if chamber.Cname == name && chamber.Cid != exceptId {
+			return store.Invalid("chamber %q already exists", name)
+		}
+	}
+
+	return nil
+}
+
+// Check that none of the IO cards is fitted to a chamber other than id
+func (s *Store) checkCards(cards []string, id int) error {
+
+	for _, chamber := range s.chambers {
+
+		if chamber.Cid == id {
+			continue
+		}
+
+		for _, fitted := range chamber.Ciocards {
+
+			for _, serial := range cards {
+
+				if serial == fitted {
+					return store.Invalid("card_serial_number %q is fitted to ZTK_Chamber_id %d", serial, chamber.Cid)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check that a chamber id referenced by a record is registered. 0 is a
+// record not assigned to a chamber.
+func (s *Store) checkChamber(id int) error {
+
+	if id != 0 && s.chamberById(id) == nil {
+		return store.Invalid("ZTK_Chamber_id %d does not exist", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+func (s *Store) InsertChannel(channel *model.Channel) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := store.CheckChannelRange(*channel)
+
+	if err == nil {
+		err = s.checkChannelName(channel.Chname, 0)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(s.insertChannel(*channel)), nil
+}
+
+func (s *Store) ListChannels() ([]model.Channel, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]model.Channel{}, s.channels...), nil
+}
+
+func (s *Store) GetChannelByName(name string) (model.Channel, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel := s.channelByName(name)
+
+	if channel == nil {
+		return model.Channel{}, store.ErrNotFound
+	}
+
+	return *channel, nil
+}
+
+// Change the name, unit, range or description of a channel. Values stored
+// before are not checked against the new range.
+func (s *Store) UpdateChannel(id int, channel *model.Channel) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.channelById(id)
+
+	if row == nil {
+		return store.ErrNotFound
+	}
+
+	err := store.CheckChannelRange(*channel)
+
+	if err == nil {
+		err = s.checkChannelName(channel.Chname, id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	row.Chname = channel.Chname
+	row.Chunit = channel.Chunit
+	row.Chminimum = channel.Chminimum
+	row.Chmaximum = channel.Chmaximum
+	row.Chdescription = channel.Chdescription
+	row.Chmodifiedby = channel.Chmodifiedby
+	row.Chmodified = channel.Chmodified
+
+	return nil
+}
+
+func (s *Store) RetireChannel(id int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.channelById(id)
+
+	if row == nil {
+		return store.ErrNotFound
+	}
+
+	row.Chactive = 0
+
+	return nil
+}
+
+func (s *Store) insertChannel(channel model.Channel) int {
+
+	channel.Chid = len(s.channels) + 1
+	channel.Chactive = 1
+
+	s.channels = append(s.channels, channel)
+
+	return channel.Chid
+}
+
+func (s *Store) channelById(id int) *model.Channel {
+
+	for i := range s.channels {
+
+		if s.channels[i].Chid == id {
+			return &s.channels[i]
+		}
+	}
+
+	return nil
+}
+
+func (s *Store) channelByName(name string) *model.Channel {
+
+	for i := range s.channels {
+
+		if s.channels[i].Chname == name {
+			return &s.channels[i]
+		}
+	}
+
+	return nil
+}
+
+// Check that name may be catalogued and is not used by another channel.
+// exceptId is the id of the channel being renamed, or 0 for a new one.
+func (s *Store) checkChannelName(name string, exceptId int) error {
+
+	err := store.CheckChannelName(name)
+
+	if err != nil {
+		return err
+	}
+
+	channel := s.channelByName(name)
+
+	if channel != nil && channel.Chid != exceptId {
+		return store.Invalid("channel %q already exists", name)
+	}
+
+	return nil
+}
+
+// Check the channel values of a Loop_Data sample against the catalogue. An
+// unknown channel is catalogued without a unit or range if autoCreate is
+// set, once every value has been checked.
+func (s *Store) checkChannels(channels map[string]float64, autoCreate bool) error {
+
+	var created []string
+
+	for _, name := range store.ChannelNames(channels) {
+
+		err := store.CheckChannelName(name)
+
+		if err != nil {
+			return err
+		}
+
+		channel := s.channelByName(name)
+
+		if channel == nil && !autoCreate {
+			return store.Invalid("channel %q does not exist", name)
+		}
+
+		if channel == nil {
+			created = append(created, name)
+			continue
+		}
+
+		err = store.CheckChannel(*channel, channels[name])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range created {
+		s.insertChannel(model.Channel{Chname: name})
+	}
+
+	return nil
+}
+
+// Copy of a Loop_Data sample that does not share its channels or
+// calibrated values
+func copyLoopData(log model.Loop_Data) model.Loop_Data {
+
+	if log.Dchannels != nil {
+
+		channels := make(map[string]float64, len(log.Dchannels))
+
+		for name, value := range log.Dchannels {
+			channels[name] = value
+		}
+
+		log.Dchannels = channels
+	}
+
+	if log.Dcalibrated != nil {
+
+		calibrated := make(map[string]model.Calibrated_Value, len(log.Dcalibrated))
+
+		for name, value := range log.Dcalibrated {
+			calibrated[name] = value
+		}
+
+		log.Dcalibrated = calibrated
+	}
+
+	return log
+}
@@ -0,0 +1,1055 @@
+// Package memory keeps the log tables in memory. It needs no DB, so the
+// server can be exercised on a laptop and in tests; nothing survives a
+// restart.
+package memory
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+// Row of ZTK_Activity_Log
+type Activity = store.Activity
+
+// Struct to hold a row of an event or test type table
+
+type typeRow struct {
+	id         int
+	active     int
+	name       string
+	createdBy  int
+	modifiedBy int
+	created    model.Time
+	modified   model.Time
+
+	deviceCreated  model.Time
+	deviceModified model.Time
+}
+
+// Struct to hold a type table and its name column, used in messages
+
+type typeTable struct {
+	table  string
+	column string
+	rows   []typeRow
+}
+
+// Struct to hold a test log with its verdict
+
+type testRow struct {
+	log       model.Logs_Test
+	profileId int
+	verdict   string
+	detail    string
+}
+
+// Struct to hold a store.Store kept in memory
+
+type Store struct {
+	mu sync.Mutex
+
+	eventTypes   typeTable
+	testTypes    typeTable
+	events       []model.Logs_Event
+	tests        []testRow
+	profiles     []model.Logs_Test_Profile
+	maintenance  []model.Logs_Maintenance
+	loopData     []model.Loop_Data
+	hourly       []model.Loop_Data_Hourly
+	iocardinfo   []model.Io_card_Info
+	chambers     []model.Chamber
+	channels     []model.Channel
+	calibrations []model.Calibration
+	activities   []Activity
+	activityId   int64
+	idempotent   []store.IdempotentResponse
+}
+
+// Create an empty Store
+func New() *Store {
+
+	return &Store{
+		eventTypes: typeTable{table: "ZTK_Logs_Event_Type", column: "events_type"},
+		testTypes:  typeTable{table: "ZTK_Logs_Test_Type", column: "test_type"},
+	}
+}
+
+// Every row recorded in ZTK_Activity_Log, oldest first
+func (s *Store) Activities() []Activity {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Activity{}, s.activities...)
+}
+
+func (s *Store) Close() error {
+	return nil
+}
+
+func (s *Store) InsertEventLog(log *model.Logs_Event, autoCreate bool) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertEventLog(log, autoCreate)
+}
+
+func (s *Store) insertEventLog(log *model.Logs_Event, autoCreate bool) (int64, error) {
+
+	var err error
+
+	log.Etypeid, err = s.eventTypes.resolve(log.Etypename, log.Etypeid, autoCreate, log.Createdby, log.Ecreated)
+
+	if err == nil {
+		err = s.eventTypes.check(log.Etypeid)
+	}
+
+	if err == nil {
+		err = s.checkChamber(log.Echamberid)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	row := *log
+	row.Etypename = ""
+	row.LogEventType = nil
+
+	s.events = append(s.events, row)
+
+	return int64(len(s.events)), nil
+}
+
+func (s *Store) ListEventLogs() ([]model.Logs_Event, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Logs_Event{}
+
+	for _, log := range s.events {
+		logs = append(logs, s.eventLog(log))
+	}
+
+	return logs, nil
+}
+
+func (s *Store) ListChamberEventLogs(chamberId int) ([]model.Logs_Event, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Logs_Event{}
+
+	for _, log := range s.events {
+
+		if log.Echamberid == chamberId {
+			logs = append(logs, s.eventLog(log))
+		}
+	}
+
+	return logs, nil
+}
+
+// Event log as read, with the name of its type
+func (s *Store) eventLog(log model.Logs_Event) model.Logs_Event {
+
+	if row := s.eventTypes.byId(log.Etypeid); row != nil {
+		log.Etypename = row.name
+	}
+
+	return log
+}
+
+func (s *Store) InsertEventType(t *model.Logs_Event_Type) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.eventTypes.checkName(t.Levents, 0)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(s.eventTypes.insert(eventTypeRow(t))), nil
+}
+
+func (s *Store) ListEventTypes() ([]model.Logs_Event_Type, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Logs_Event_Type{}
+
+	for _, row := range s.eventTypes.rows {
+		logs = append(logs, eventType(row))
+	}
+
+	return logs, nil
+}
+
+func (s *Store) GetEventTypeByName(name string) (model.Logs_Event_Type, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.eventTypes.byName(name)
+
+	if row == nil {
+		return model.Logs_Event_Type{}, store.ErrNotFound
+	}
+
+	return eventType(*row), nil
+}
+
+func (s *Store) UpdateEventType(id int, t *model.Logs_Event_Type) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.eventTypes.update(id, eventTypeRow(t))
+}
+
+func (s *Store) RetireEventType(id int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.eventTypes.retire(id)
+}
+
+func (s *Store) InsertTestLog(log *model.Logs_Test, autoCreate bool) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertTestLog(log, autoCreate)
+}
+
+func (s *Store) insertTestLog(log *model.Logs_Test, autoCreate bool) (int64, error) {
+
+	var err error
+
+	log.Ttypeid, err = s.testTypes.resolve(log.Ttypename, log.Ttypeid, autoCreate, log.Tcreatedby, log.Tcreated)
+
+	if err == nil {
+		err = s.testTypes.check(log.Ttypeid)
+	}
+
+	if err == nil {
+		err = s.checkChamber(log.Tchamberid)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	row := *log
+	row.Ttypename = ""
+	row.LogTestType = nil
+
+	s.tests = append(s.tests, testRow{log: row})
+
+	return int64(len(s.tests)), nil
+}
+
+func (s *Store) ListTestLogs() ([]model.Logs_Test, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Logs_Test{}
+
+	for _, row := range s.tests {
+		logs = append(logs, s.testLog(row.log))
+	}
+
+	return logs, nil
+}
+
+func (s *Store) ListChamberTestLogs(chamberId int) ([]model.Logs_Test, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Logs_Test{}
+
+	for _, row := range s.tests {
+
+		if row.log.Tchamberid == chamberId {
+			logs = append(logs, s.testLog(row.log))
+		}
+	}
+
+	return logs, nil
+}
+
+// Test log as read, with the name of its type
+func (s *Store) testLog(log model.Logs_Test) model.Logs_Test {
+
+	if row := s.testTypes.byId(log.Ttypeid); row != nil {
+		log.Ttypename = row.name
+	}
+
+	return log
+}
+
+// Latest test log recorded with the log id and its row id
+func (s *Store) testByLogId(logId string) (int64, *testRow) {
+
+	for i := len(s.tests) - 1; i >= 0; i-- {
+
+		if s.tests[i].log.Tid == logId {
+			return int64(i + 1), &s.tests[i]
+		}
+	}
+
+	return 0, nil
+}
+
+// Test log of the row id
+func (s *Store) testById(id int64) *testRow {
+
+	if id < 1 || id > int64(len(s.tests)) {
+		return nil
+	}
+
+	return &s.tests[id-1]
+}
+
+func (s *Store) GetTestLog(logId string) (int64, model.Logs_Test, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, row := s.testByLogId(logId)
+
+	if row == nil {
+		return 0, model.Logs_Test{}, store.ErrNotFound
+	}
+
+	return id, s.testLog(row.log), nil
+}
+
+func (s *Store) SetTestVerdict(id int64, profileId int, verdict string, detail string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.testById(id)
+
+	if row == nil {
+		return store.ErrNotFound
+	}
+
+	row.profileId = profileId
+	row.verdict = verdict
+	row.detail = detail
+
+	return nil
+}
+
+func (s *Store) GetTestVerdict(id int64) (string, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.testById(id)
+
+	if row == nil || row.verdict == "" {
+		return "", store.ErrNotFound
+	}
+
+	return row.detail, nil
+}
+
+func (s *Store) InsertTestType(t *model.Logs_Test_Type) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.testTypes.checkName(t.Ltesttype, 0)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(s.testTypes.insert(testTypeRow(t))), nil
+}
+
+func (s *Store) ListTestTypes() ([]model.Logs_Test_Type, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Logs_Test_Type{}
+
+	for _, row := range s.testTypes.rows {
+		logs = append(logs, testType(row))
+	}
+
+	return logs, nil
+}
+
+func (s *Store) GetTestTypeByName(name string) (model.Logs_Test_Type, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row := s.testTypes.byName(name)
+
+	if row == nil {
+		return model.Logs_Test_Type{}, store.ErrNotFound
+	}
+
+	return testType(*row), nil
+}
+
+func (s *Store) UpdateTestType(id int, t *model.Logs_Test_Type) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.testTypes.update(id, testTypeRow(t))
+}
+
+func (s *Store) RetireTestType(id int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.testTypes.retire(id)
+}
+
+func (s *Store) InsertTestProfile(profile *model.Logs_Test_Profile) (int, int, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.testTypes.byId(profile.Ptypeid) == nil {
+		return 0, 0, store.ErrNotFound
+	}
+
+	version := 1
+
+	for _, p := range s.profiles {
+
+		if p.Ptypeid == profile.Ptypeid && p.Pversion >= version {
+			version = p.Pversion + 1
+		}
+	}
+
+	row := *profile
+	row.Pid = len(s.profiles) + 1
+	row.Pversion = version
+	row.Pcreated = model.Now()
+	row.Psteps = append([]model.Logs_Test_Profile_Step{}, profile.Psteps...)
+
+	for i := range row.Psteps {
+		row.Psteps[i].Sno = i + 1
+	}
+
+	s.profiles = append(s.profiles, row)
+
+	return row.Pid, row.Pversion, nil
+}
+
+func (s *Store) GetTestProfile(typeId int, version int) (model.Logs_Test_Profile, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var found *model.Logs_Test_Profile
+
+	for i, p := range s.profiles {
+
+		if p.Ptypeid != typeId || (version != 0 && p.Pversion != version) {
+			continue
+		}
+
+		if found == nil || p.Pversion > found.Pversion {
+			found = &s.profiles[i]
+		}
+	}
+
+	if found == nil {
+		return model.Logs_Test_Profile{}, store.ErrNotFound
+	}
+
+	profile := *found
+	profile.Psteps = append([]model.Logs_Test_Profile_Step{}, found.Psteps...)
+
+	return profile, nil
+}
+
+func (s *Store) ListTestProfiles(typeId int) ([]model.Logs_Test_Profile, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Logs_Test_Profile{}
+
+	for _, p := range s.profiles {
+
+		if p.Ptypeid == typeId {
+			p.Psteps = nil
+			logs = append(logs, p)
+		}
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].Pversion < logs[j].Pversion
+	})
+
+	return logs, nil
+}
+
+func (s *Store) InsertMaintenanceLog(log *model.Logs_Maintenance) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.insertMaintenanceLog(log)
+}
+
+func (s *Store) insertMaintenanceLog(log *model.Logs_Maintenance) (int64, error) {
+
+	err := s.checkChamber(log.Mchamberid)
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.maintenance = append(s.maintenance, *log)
+
+	return int64(len(s.maintenance)), nil
+}
+
+func (s *Store) ListMaintenanceLogs() ([]model.Logs_Maintenance, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]model.Logs_Maintenance{}, s.maintenance...), nil
+}
+
+func (s *Store) ListChamberMaintenanceLogs(chamberId int) ([]model.Logs_Maintenance, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Logs_Maintenance{}
+
+	for _, log := range s.maintenance {
+
+		if log.Mchamberid == chamberId {
+			logs = append(logs, log)
+		}
+	}
+
+	return logs, nil
+}
+
+func (s *Store) InsertLoopData(log *model.Loop_Data, autoCreate bool) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.checkChamber(log.Dchamberid)
+
+	if err == nil {
+		err = s.checkChannels(log.Dchannels, autoCreate)
+	}
+
+	if err == nil && s.loopDataIndex(log) >= 0 {
+		err = store.Invalid("chamber %d has a Loop_Data sample at %s already", log.Dchamberid, log.Ddatatime)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.loopData = append(s.loopData, copyLoopData(*log))
+
+	return int64(len(s.loopData)), nil
+}
+
+func (s *Store) UpdateLoopData(log *model.Loop_Data, autoCreate bool) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i := s.loopDataIndex(log)
+
+	if i < 0 {
+		return store.ErrNotFound
+	}
+
+	err := s.checkChannels(log.Dchannels, autoCreate)
+
+	if err != nil {
+		return err
+	}
+
+	s.loopData[i] = copyLoopData(*log)
+
+	return nil
+}
+
+// Store the sample, replacing the one of its chamber at its date time, and
+// report whether it was created
+func (s *Store) UpsertLoopData(log *model.Loop_Data, autoCreate bool) (int64, bool, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := s.checkChamber(log.Dchamberid)
+
+	if err == nil {
+		err = s.checkChannels(log.Dchannels, autoCreate)
+	}
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	if i := s.loopDataIndex(log); i >= 0 {
+		s.loopData[i] = copyLoopData(*log)
+		return int64(i + 1), false, nil
+	}
+
+	s.loopData = append(s.loopData, copyLoopData(*log))
+
+	return int64(len(s.loopData)), true, nil
+}
+
+// Index of the sample of the chamber at the date time of log, -1 when there
+// is none
+func (s *Store) loopDataIndex(log *model.Loop_Data) int {
+
+	for i := range s.loopData {
+
+		if s.loopData[i].Dchamberid == log.Dchamberid && s.loopData[i].Ddatatime.Equal(log.Ddatatime.Time) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (s *Store) ListLoopData() ([]model.Loop_Data, error) {
+
+	return s.loopDataWhere(func(log model.Loop_Data) bool { return true })
+}
+
+// Loop_Data captured at or after start and before end, oldest first. A zero
+// start or end leaves that side open.
+func (s *Store) ListLoopDataBetween(start model.Time, end model.Time) ([]model.Loop_Data, error) {
+
+	return s.loopDataWhere(func(log model.Loop_Data) bool {
+		return between(log.Ddatatime, start, end)
+	})
+}
+
+// Loop_Data of the chamber between start and end, as ListLoopDataBetween
+func (s *Store) ListChamberLoopData(chamberId int, start model.Time, end model.Time) ([]model.Loop_Data, error) {
+
+	return s.loopDataWhere(func(log model.Loop_Data) bool {
+		return log.Dchamberid == chamberId && between(log.Ddatatime, start, end)
+	})
+}
+
+// Report whether at is at or after start and before end, a zero start or end
+// leaving that side open
+func between(at model.Time, start model.Time, end model.Time) bool {
+
+	return (start.IsZero() || !at.Before(start.Time)) && (end.IsZero() || at.Before(end.Time))
+}
+
+func (s *Store) loopDataWhere(keep func(model.Loop_Data) bool) ([]model.Loop_Data, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	logs := []model.Loop_Data{}
+
+	for _, log := range s.loopData {
+
+		if keep(log) {
+			logs = append(logs, copyLoopData(log))
+		}
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		return logs[i].Ddatatime.Before(logs[j].Ddatatime.Time)
+	})
+
+	return logs, nil
+}
+
+func (s *Store) InsertIocardinfo(log *model.Io_card_Info) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.iocardinfo = append(s.iocardinfo, *log)
+
+	return int64(len(s.iocardinfo)), nil
+}
+
+func (s *Store) ListIocardinfo() ([]model.Io_card_Info, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]model.Io_card_Info{}, s.iocardinfo...), nil
+}
+
+// Insert every part of a Logs_All document. Nothing is stored if any part
+// fails.
+func (s *Store) InsertAll(log *model.Logs_All, autoCreate bool) (store.AllIds, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ids store.AllIds
+	var err error
+
+	saved := s.snapshot()
+
+	defer func() {
+		if err != nil {
+			s.restore(saved)
+		}
+	}()
+
+	if log.Event != nil {
+
+		event := log.Event
+
+		if t := event.LogEventType; t != nil {
+
+			event.Etypeid, err = s.eventTypes.resolveNested(eventTypeRow(t))
+
+			if err != nil {
+				return ids, err
+			}
+		}
+
+		ids.EventId, err = s.insertEventLog(event, autoCreate)
+
+		if err != nil {
+			return ids, err
+		}
+
+		ids.EventTypeId = event.Etypeid
+	}
+
+	if log.Test != nil {
+
+		test := log.Test
+
+		if t := test.LogTestType; t != nil {
+
+			test.Ttypeid, err = s.testTypes.resolveNested(testTypeRow(t))
+
+			if err != nil {
+				return ids, err
+			}
+		}
+
+		ids.TestId, err = s.insertTestLog(test, autoCreate)
+
+		if err != nil {
+			return ids, err
+		}
+
+		ids.TestTypeId = test.Ttypeid
+	}
+
+	if log.Maintenance != nil {
+
+		ids.MaintenanceId, err = s.insertMaintenanceLog(log.Maintenance)
+
+		if err != nil {
+			return ids, err
+		}
+	}
+
+	return ids, nil
+}
+
+func (s *Store) InsertActivity(tableId int, actionType string, newvalue string, userId int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.activityId++
+
+	s.activities = append(s.activities, Activity{Id: s.activityId, TableId: tableId, ActionType: actionType, NewValue: newvalue, UserId: userId, Created: model.Now()})
+
+	return nil
+}
+
+func (s *Store) GetIdempotentResponse(userId int, key string) (store.IdempotentResponse, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, response := range s.idempotent {
+
+		if response.UserId == userId && response.Key == key {
+			return response, nil
+		}
+	}
+
+	return store.IdempotentResponse{}, store.ErrNotFound
+}
+
+func (s *Store) InsertIdempotentResponse(response *store.IdempotentResponse) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, stored := range s.idempotent {
+
+		if stored.UserId == response.UserId && stored.Key == response.Key {
+			return store.Invalid("Idempotency-Key %q is already used", response.Key)
+		}
+	}
+
+	s.idempotent = append(s.idempotent, *response)
+
+	return nil
+}
+
+func (s *Store) DeleteIdempotentResponses(before model.Time) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := s.idempotent[:0]
+
+	for _, response := range s.idempotent {
+
+		if !response.Created.Before(before.Time) {
+			kept = append(kept, response)
+		}
+	}
+
+	deleted := int64(len(s.idempotent) - len(kept))
+
+	s.idempotent = kept
+
+	return deleted, nil
+}
+
+// Struct to hold the tables changed by InsertAll, restored when it fails
+
+type snapshot struct {
+	eventTypes  []typeRow
+	testTypes   []typeRow
+	events      int
+	tests       int
+	maintenance int
+}
+
+func (s *Store) snapshot() snapshot {
+
+	return snapshot{
+		eventTypes:  append([]typeRow{}, s.eventTypes.rows...),
+		testTypes:   append([]typeRow{}, s.testTypes.rows...),
+		events:      len(s.events),
+		tests:       len(s.tests),
+		maintenance: len(s.maintenance),
+	}
+}
+
+func (s *Store) restore(saved snapshot) {
+
+	s.eventTypes.rows = saved.eventTypes
+	s.testTypes.rows = saved.testTypes
+	s.events = s.events[:saved.events]
+	s.tests = s.tests[:saved.tests]
+	s.maintenance = s.maintenance[:saved.maintenance]
+}
+
+func eventType(row typeRow) model.Logs_Event_Type {
+
+	return model.Logs_Event_Type{Lid: row.id, Lactive: row.active, Levents: row.name, Lcreated: row.createdBy, Lmodified: row.modifiedBy, Lcreated1: row.created, Lmodified2: row.modified, Ldevicecreated: row.deviceCreated, Ldevicemodified: row.deviceModified}
+}
+
+func testType(row typeRow) model.Logs_Test_Type {
+
+	return model.Logs_Test_Type{Lid: row.id, Lactive: row.active, Ltesttype: row.name, Tcreatedby1: row.createdBy, Tmodifiedby2: row.modifiedBy, Tcreated1: row.created, Tmodified2: row.modified, Tdevicecreated1: row.deviceCreated, Tdevicemodified2: row.deviceModified}
+}
+
+func eventTypeRow(t *model.Logs_Event_Type) typeRow {
+
+	return typeRow{name: t.Levents, createdBy: t.Lcreated, modifiedBy: t.Lmodified, created: t.Lcreated1, modified: t.Lmodified2, deviceCreated: t.Ldevicecreated, deviceModified: t.Ldevicemodified}
+}
+
+func testTypeRow(t *model.Logs_Test_Type) typeRow {
+
+	return typeRow{name: t.Ltesttype, createdBy: t.Tcreatedby1, modifiedBy: t.Tmodifiedby2, created: t.Tcreated1, modified: t.Tmodified2, deviceCreated: t.Tdevicecreated1, deviceModified: t.Tdevicemodified2}
+}
+
+func (t *typeTable) byId(id int) *typeRow {
+
+	for i := range t.rows {
+
+		if t.rows[i].id == id {
+			return &t.rows[i]
+		}
+	}
+
+	return nil
+}
+
+// Row of the name, compared without case like the SQL stores do
+func (t *typeTable) byName(name string) *typeRow {
+
+	for i := range t.rows {
+
+		if strings.EqualFold(t.rows[i].name, name) {
+			return &t.rows[i]
+		}
+	}
+
+	return nil
+}
+
+// Check that name is set and not used by another row of the type table.
+// exceptId is the id of the row being renamed, or 0 for a new row.
+func (t *typeTable) checkName(name string, exceptId int) error {
+
+	if name == "" {
+		return store.Invalid("%s is required", t.column)
+	}
+
+	row := t.byName(name)
+
+	if row != nil && row.id != exceptId {
+		return store.Invalid("%s %q already exists", t.column, name)
+	}
+
+	return nil
+}
+
+func (t *typeTable) insert(row typeRow) int {
+
+	row.id = len(t.rows) + 1
+	row.active = 1
+
+	t.rows = append(t.rows, row)
+
+	return row.id
+}
+
+// Rename a type. Renaming to the name of another type is rejected.
+func (t *typeTable) update(id int, changed typeRow) error {
+
+	row := t.byId(id)
+
+	if row == nil {
+		return store.ErrNotFound
+	}
+
+	err := t.checkName(changed.name, id)
+
+	if err != nil {
+		return err
+	}
+
+	row.name = changed.name
+	row.modifiedBy = changed.modifiedBy
+	row.modified = changed.modified
+	row.deviceModified = changed.deviceModified
+
+	return nil
+}
+
+func (t *typeTable) retire(id int) error {
+
+	row := t.byId(id)
+
+	if row == nil {
+		return store.ErrNotFound
+	}
+
+	row.active = 0
+
+	return nil
+}
+
+// Resolve a type given by name in a log to its id. When name is empty the
+// given id is returned unchanged. An unknown name is created as a new type,
+// stamped like the log, if autoCreate is set, otherwise it is rejected, as is
+// a name that does not match an id also given in the log.
+func (t *typeTable) resolve(name string, id int, autoCreate bool, createdBy int, created model.Time) (int, error) {
+
+	if name == "" {
+		return id, nil
+	}
+
+	row := t.byName(name)
+
+	if row == nil && autoCreate {
+
+		return t.insert(typeRow{name: name, createdBy: createdBy, modifiedBy: createdBy, created: created, modified: created}), nil
+	}
+
+	if row == nil {
+		return 0, store.Invalid("%s %q does not exist", t.column, name)
+	}
+
+	if id != 0 && id != row.id {
+		return 0, store.Invalid("%s %q does not match %s_id %d", t.column, name, t.table, id)
+	}
+
+	return row.id, nil
+}
+
+// Check that a type id referenced by a log exists and has not been retired.
+func (t *typeTable) check(id int) error {
+
+	row := t.byId(id)
+
+	if row == nil {
+		return store.Invalid("%s_id %d does not exist", t.table, id)
+	}
+
+	if row.active == 0 {
+		return store.Invalid("%s_id %d is retired", t.table, id)
+	}
+
+	return nil
+}
+
+// Resolve a type embedded in a Logs_All document to its id, creating it
+// from the embedded fields when no type of that name exists yet.
+func (t *typeTable) resolveNested(nested typeRow) (int, error) {
+
+	if nested.name == "" {
+		return 0, store.Invalid("%s is required", t.column)
+	}
+
+	row := t.byName(nested.name)
+
+	if row == nil {
+		return t.insert(nested), nil
+	}
+
+	if row.active == 0 {
+		return 0, store.Invalid("%s %q is retired", t.column, nested.name)
+	}
+
+	return row.id, nil
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"sort"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+func (s *Store) OldestLoopData() (model.Time, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var at model.Time
+
+	for _, log := range s.loopData {
+
+		if at.IsZero() || log.Ddatatime.Before(at.Time) {
+			at = log.Ddatatime
+		}
+	}
+
+	if at.IsZero() {
+		return at, store.ErrNotFound
+	}
+
+	return at, nil
+}
+
+func (s *Store) PruneLoopData(start model.Time, end model.Time, archive func([]model.Loop_Data) error) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned []model.Loop_Data
+
+	kept := []model.Loop_Data{}
+
+	for _, log := range s.loopData {
+
+		if between(log.Ddatatime, start, end) {
+			pruned = append(pruned, copyLoopData(log))
+		} else {
+			kept = append(kept, log)
+		}
+	}
+
+	if len(pruned) == 0 {
+		return 0, nil
+	}
+
+	sort.SliceStable(pruned, func(i, j int) bool {
+		return pruned[i].Ddatatime.Before(pruned[j].Ddatatime.Time)
+	})
+
+	err := archive(pruned)
+
+	if err != nil {
+		return 0, err
+	}
+
+	for _, hour := range model.RollUpLoopData(pruned) {
+
+		merged := false
+
+		for i := range s.hourly {
+
+			if s.hourly[i].Hchamberid == hour.Hchamberid && s.hourly[i].Hhour.Equal(hour.Hhour.Time) {
+				s.hourly[i].Merge(hour)
+				merged = true
+			}
+		}
+
+		if !merged {
+			s.hourly = append(s.hourly, hour)
+		}
+	}
+
+	s.loopData = kept
+
+	return int64(len(pruned)), nil
+}
+
+func (s *Store) ListLoopDataHourly(start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error) {
+
+	return s.hourlyWhere(func(hour model.Loop_Data_Hourly) bool {
+		return between(hour.Hhour, start, end)
+	})
+}
+
+func (s *Store) ListChamberLoopDataHourly(chamberId int, start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error) {
+
+	return s.hourlyWhere(func(hour model.Loop_Data_Hourly) bool {
+		return hour.Hchamberid == chamberId && between(hour.Hhour, start, end)
+	})
+}
+
+func (s *Store) hourlyWhere(keep func(model.Loop_Data_Hourly) bool) ([]model.Loop_Data_Hourly, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hours := []model.Loop_Data_Hourly{}
+
+	for _, hour := range s.hourly {
+
+		if keep(hour) {
+			hours = append(hours, hour)
+		}
+	}
+
+	sort.SliceStable(hours, func(i, j int) bool {
+
+		if !hours[i].Hhour.Equal(hours[j].Hhour.Time) {
+			return hours[i].Hhour.Before(hours[j].Hhour.Time)
+		}
+
+		return hours[i].Hchamberid < hours[j].Hchamberid
+	})
+
+	return hours, nil
+}
+
+func (s *Store) OldestActivity() (model.Time, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.activities) == 0 {
+		return model.Time{}, store.ErrNotFound
+	}
+
+	// Recorded in order, the first is the oldest
+	return s.activities[0].Created, nil
+}
+
+func (s *Store) ListActivities(start model.Time, end model.Time) ([]store.Activity, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	activities := []store.Activity{}
+
+	for _, activity := range s.activities {
+
+		if between(activity.Created, start, end) {
+			activities = append(activities, activity)
+		}
+	}
+
+	return activities, nil
+}
+
+func (s *Store) PruneActivities(start model.Time, end model.Time, archive func([]store.Activity) error) (int64, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pruned []Activity
+
+	kept := []Activity{}
+
+	for _, activity := range s.activities {
+
+		if between(activity.Created, start, end) {
+			pruned = append(pruned, activity)
+		} else {
+			kept = append(kept, activity)
+		}
+	}
+
+	if len(pruned) == 0 {
+		return 0, nil
+	}
+
+	err := archive(pruned)
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.activities = kept
+
+	return int64(len(pruned)), nil
+}
@@ -0,0 +1,105 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+func (s *Store) InsertCalibration(calibration *model.Calibration) (int64, error) {
+
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		err := checkChamber(tx, calibration.Calchamberid)
+
+		if err == nil {
+			err = checkCalibrationChannel(tx, calibration.Calchannel)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec("insert into ZTK_Calibration (ZTK_Chamber_id,channel,value_offset,gain,calibration_date,due_date,certificate,created_by,modified_by,created,modified ) values(?,?,?,?,?,?,?,?,?,?,?);", calibration.Calchamberid, calibration.Calchannel, calibration.Caloffset, calibration.Calgain, calibration.Caldate, calibration.Calduedate, calibration.Calcertificate, calibration.Calcreatedby, calibration.Calmodifiedby, calibration.Calcreated, calibration.Calmodified)
+
+		if err != nil {
+			return err
+		}
+
+		id, err = result.LastInsertId()
+
+		return err
+	})
+
+	return id, err
+}
+
+func (s *Store) ListCalibrations() ([]model.Calibration, error) {
+
+	return s.queryCalibrations("")
+}
+
+func (s *Store) ListChamberCalibrations(chamberId int) ([]model.Calibration, error) {
+
+	return s.queryCalibrations(" where ZTK_Chamber_id = ?", chamberId)
+}
+
+func (s *Store) queryCalibrations(where string, args ...interface{}) ([]model.Calibration, error) {
+
+	calibrations := []model.Calibration{}
+
+	rows, err := s.db.Query("select id,ZTK_Chamber_id,channel,value_offset,gain,calibration_date,due_date,certificate,created_by,coalesce(created,''),modified_by,coalesce(modified,'') from ZTK_Calibration"+where+" order by calibration_date,id", args...)
+
+	if err != nil {
+		return calibrations, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var c model.Calibration
+		err = rows.Scan(&c.Calid, &c.Calchamberid, &c.Calchannel, &c.Caloffset, &c.Calgain, &c.Caldate, &c.Calduedate, &c.Calcertificate, &c.Calcreatedby, &c.Calcreated, &c.Calmodifiedby, &c.Calmodified)
+		if err != nil {
+			return calibrations, err
+		}
+		calibrations = append(calibrations, c)
+	}
+
+	return calibrations, rows.Err()
+}
+
+// Check that a calibration names a process value column or a channel of the
+// catalogue
+func checkCalibrationChannel(q queryer, channel string) error {
+
+	if store.IsProcessValue(channel) {
+		return nil
+	}
+
+	_, err := getChannelByName(q, channel)
+
+	if err == store.ErrNotFound {
+		return store.Invalid("channel %q is not a process value or a catalogued channel", channel)
+	}
+
+	return err
+}
+
+// Store the raw values and calibrations of the corrected values of a
+// Loop_Data sample
+func setLoopDataCalibrated(q queryer, sampleId int64, calibrated map[string]model.Calibrated_Value) error {
+
+	for channel, value := range calibrated {
+
+		_, err := q.Exec("insert into ZTK_Loop_Data_Calibration (ZTK_Loop_Data_id,channel,raw_value,ZTK_Calibration_id ) values(?,?,?,?);", sampleId, channel, value.Vraw, value.Vcalibrationid)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
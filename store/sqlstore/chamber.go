@@ -0,0 +1,240 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+func (s *Store) InsertChamber(chamber *model.Chamber) (int64, error) {
+
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		err := checkChamberName(tx, chamber.Cname, 0)
+
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec("insert into ZTK_Chamber (name,location,time_zone,created_by,modified_by,created,modified ) values(?,?,?,?,?,?,?);", chamber.Cname, chamber.Clocation, chamber.Ctimezone, chamber.Ccreatedby, chamber.Cmodifiedby, chamber.Ccreated, chamber.Cmodified)
+
+		if err != nil {
+			return err
+		}
+
+		id, err = result.LastInsertId()
+
+		if err != nil {
+			return err
+		}
+
+		return setChamberCards(tx, int(id), chamber.Ciocards)
+	})
+
+	return id, err
+}
+
+const chamberColumns = "id,name,location,time_zone,created_by,coalesce(created,''),modified_by,coalesce(modified,'')"
+
+func (s *Store) ListChambers() ([]model.Chamber, error) {
+
+	chambers := []model.Chamber{}
+
+	rows, err := s.db.Query("select " + chamberColumns + " from ZTK_Chamber order by id")
+
+	if err != nil {
+		return chambers, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var chamber model.Chamber
+		err = rows.Scan(&chamber.Cid, &chamber.Cname, &chamber.Clocation, &chamber.Ctimezone, &chamber.Ccreatedby, &chamber.Ccreated, &chamber.Cmodifiedby, &chamber.Cmodified)
+		if err != nil {
+			return chambers, err
+		}
+		chambers = append(chambers, chamber)
+	}
+
+	err = rows.Err()
+
+	if err != nil {
+		return chambers, err
+	}
+
+	cards, err := s.chamberCards("")
+
+	for i := range chambers {
+		chambers[i].Ciocards = append([]string{}, cards[chambers[i].Cid]...)
+	}
+
+	return chambers, err
+}
+
+func (s *Store) GetChamber(id int) (model.Chamber, error) {
+
+	var chamber model.Chamber
+
+	err := s.db.QueryRow("select "+chamberColumns+" from ZTK_Chamber where id = ?", id).Scan(&chamber.Cid, &chamber.Cname, &chamber.Clocation, &chamber.Ctimezone, &chamber.Ccreatedby, &chamber.Ccreated, &chamber.Cmodifiedby, &chamber.Cmodified)
+
+	if err == sql.ErrNoRows {
+		return chamber, store.ErrNotFound
+	}
+
+	if err != nil {
+		return chamber, err
+	}
+
+	cards, err := s.chamberCards(" where ZTK_Chamber_id = ?", id)
+
+	chamber.Ciocards = append([]string{}, cards[id]...)
+
+	return chamber, err
+}
+
+// Rename, move or refit a chamber. Its IO cards are replaced by the ones
+// given.
+func (s *Store) UpdateChamber(id int, chamber *model.Chamber) error {
+
+	return s.inTx(func(tx *sql.Tx) error {
+
+		err := checkChamber(tx, id)
+
+		if id == 0 || store.IsInvalid(err) {
+			return store.ErrNotFound
+		}
+
+		if err == nil {
+			err = checkChamberName(tx, chamber.Cname, id)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("update ZTK_Chamber set name=?,location=?,time_zone=?,modified_by=?,modified=? where id = ?", chamber.Cname, chamber.Clocation, chamber.Ctimezone, chamber.Cmodifiedby, chamber.Cmodified, id)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("delete from ZTK_Chamber_IO_Card where ZTK_Chamber_id = ?", id)
+
+		if err != nil {
+			return err
+		}
+
+		return setChamberCards(tx, id, chamber.Ciocards)
+	})
+}
+
+// IO cards whose serial numbers are fitted to the chamber
+func (s *Store) ListChamberIocardinfo(chamberId int) ([]model.Io_card_Info, error) {
+
+	return s.queryIocardinfo(" where card_serial_number in (select card_serial_number from ZTK_Chamber_IO_Card where ZTK_Chamber_id = ?)", chamberId)
+}
+
+// Serial numbers of the IO cards of the chambers matching where, keyed by
+// chamber id
+func (s *Store) chamberCards(where string, args ...interface{}) (map[int][]string, error) {
+
+	cards := map[int][]string{}
+
+	rows, err := s.db.Query("select ZTK_Chamber_id,card_serial_number from ZTK_Chamber_IO_Card"+where+" order by card_serial_number", args...)
+
+	if err != nil {
+		return cards, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var serial string
+		err = rows.Scan(&id, &serial)
+		if err != nil {
+			return cards, err
+		}
+		cards[id] = append(cards[id], serial)
+	}
+
+	return cards, rows.Err()
+}
+
+// Fit the IO cards to the chamber. A card fitted to another chamber is
+// rejected.
+func setChamberCards(q queryer, id int, cards []string) error {
+
+	for _, serial := range cards {
+
+		var other int
+
+		err := q.QueryRow("select ZTK_Chamber_id from ZTK_Chamber_IO_Card where card_serial_number = ?", serial).Scan(&other)
+
+		if err == nil {
+			return store.Invalid("card_serial_number %q is fitted to ZTK_Chamber_id %d", serial, other)
+		}
+
+		if err != sql.ErrNoRows {
+			return err
+		}
+
+		_, err = q.Exec("insert into ZTK_Chamber_IO_Card (card_serial_number,ZTK_Chamber_id ) values(?,?);", serial, id)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Check that name is set and not used by another chamber. exceptId is the id
+// of the chamber being renamed, or 0 for a new one.
+func checkChamberName(q queryer, name string, exceptId int) error {
+
+	var count int
+
+	if name == "" {
+		return store.Invalid("name is required")
+	}
+
+	err := q.QueryRow("select count(id) from ZTK_Chamber where name = ? and id <> ?", name, exceptId).Scan(&count)
+
+	if err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return store.Invalid("chamber %q already exists", name)
+	}
+
+	return nil
+}
+
+// Check that a chamber id referenced by a record is registered. 0 is a
+// record not assigned to a chamber.
+func checkChamber(q queryer, id int) error {
+
+	var count int
+
+	if id == 0 {
+		return nil
+	}
+
+	err := q.QueryRow("select count(id) from ZTK_Chamber where id = ?", id).Scan(&count)
+
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return store.Invalid("ZTK_Chamber_id %d does not exist", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,231 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+const channelColumns = "id,active,name,unit,minimum,maximum,description,created_by,coalesce(created,''),modified_by,coalesce(modified,'')"
+
+func (s *Store) InsertChannel(channel *model.Channel) (int64, error) {
+
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		err := store.CheckChannelRange(*channel)
+
+		if err == nil {
+			err = checkChannelName(tx, channel.Chname, 0)
+		}
+
+		if err == nil {
+			id, err = insertChannel(tx, channel)
+		}
+
+		return err
+	})
+
+	return id, err
+}
+
+func (s *Store) ListChannels() ([]model.Channel, error) {
+
+	channels := []model.Channel{}
+
+	rows, err := s.db.Query("select " + channelColumns + " from ZTK_Channel order by id")
+
+	if err != nil {
+		return channels, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		channel, err := scanChannel(rows)
+		if err != nil {
+			return channels, err
+		}
+		channels = append(channels, channel)
+	}
+
+	return channels, rows.Err()
+}
+
+func (s *Store) GetChannelByName(name string) (model.Channel, error) {
+
+	return getChannelByName(s.db, name)
+}
+
+// Change the name, unit, range or description of a channel. Values stored
+// before are not checked against the new range.
+func (s *Store) UpdateChannel(id int, channel *model.Channel) error {
+
+	return s.inTx(func(tx *sql.Tx) error {
+
+		err := channelExists(tx, id)
+
+		if err == nil {
+			err = store.CheckChannelRange(*channel)
+		}
+
+		if err == nil {
+			err = checkChannelName(tx, channel.Chname, id)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec("update ZTK_Channel set name=?,unit=?,minimum=?,maximum=?,description=?,modified_by=?,modified=? where id = ?", channel.Chname, channel.Chunit, channel.Chminimum, channel.Chmaximum, channel.Chdescription, channel.Chmodifiedby, channel.Chmodified, id)
+
+		return err
+	})
+}
+
+func (s *Store) RetireChannel(id int) error {
+
+	err := channelExists(s.db, id)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec("update ZTK_Channel set active=0 where id = ?", id)
+
+	return err
+}
+
+func insertChannel(q queryer, channel *model.Channel) (int64, error) {
+
+	result, err := q.Exec("insert into ZTK_Channel (name,unit,minimum,maximum,description,active,created_by,modified_by,created,modified ) values(?,?,?,?,?,1,?,?,?,?);", channel.Chname, channel.Chunit, channel.Chminimum, channel.Chmaximum, channel.Chdescription, channel.Chcreatedby, channel.Chmodifiedby, channel.Chcreated, channel.Chmodified)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func getChannelByName(q queryer, name string) (model.Channel, error) {
+
+	channel, err := scanChannel(q.QueryRow("select "+channelColumns+" from ZTK_Channel where name = ?", name))
+
+	if err == sql.ErrNoRows {
+		return channel, store.ErrNotFound
+	}
+
+	return channel, err
+}
+
+// Row scanner satisfied by both *sql.Row and *sql.Rows
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanChannel(row scanner) (model.Channel, error) {
+
+	var channel model.Channel
+	var minimum, maximum sql.NullFloat64
+
+	err := row.Scan(&channel.Chid, &channel.Chactive, &channel.Chname, &channel.Chunit, &minimum, &maximum, &channel.Chdescription, &channel.Chcreatedby, &channel.Chcreated, &channel.Chmodifiedby, &channel.Chmodified)
+
+	if minimum.Valid {
+		channel.Chminimum = &minimum.Float64
+	}
+
+	if maximum.Valid {
+		channel.Chmaximum = &maximum.Float64
+	}
+
+	return channel, err
+}
+
+func channelExists(q queryer, id int) error {
+
+	var count int
+
+	err := q.QueryRow("select count(id) from ZTK_Channel where id = ?", id).Scan(&count)
+
+	if err == nil && count == 0 {
+		err = store.ErrNotFound
+	}
+
+	return err
+}
+
+// Check that name may be catalogued and is not used by another channel.
+// exceptId is the id of the channel being renamed, or 0 for a new one.
+func checkChannelName(q queryer, name string, exceptId int) error {
+
+	var count int
+
+	err := store.CheckChannelName(name)
+
+	if err != nil {
+		return err
+	}
+
+	err = q.QueryRow("select count(id) from ZTK_Channel where name = ? and id <> ?", name, exceptId).Scan(&count)
+
+	if err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return store.Invalid("channel %q already exists", name)
+	}
+
+	return nil
+}
+
+// Store the channel values of a Loop_Data sample, checked against the
+// catalogue. An unknown channel is catalogued without a unit or range if
+// autoCreate is set.
+func setLoopDataChannels(q queryer, sampleId int64, channels map[string]float64, autoCreate bool) error {
+
+	for _, name := range store.ChannelNames(channels) {
+
+		err := store.CheckChannelName(name)
+
+		if err != nil {
+			return err
+		}
+
+		channel, err := getChannelByName(q, name)
+
+		if err == store.ErrNotFound && autoCreate {
+
+			channel = model.Channel{Chname: name, Chactive: 1}
+
+			var id int64
+
+			id, err = insertChannel(q, &channel)
+
+			channel.Chid = int(id)
+		}
+
+		if err == store.ErrNotFound {
+			return store.Invalid("channel %q does not exist", name)
+		}
+
+		if err == nil {
+			err = store.CheckChannel(channel, channels[name])
+		}
+
+		if err != nil {
+			return err
+		}
+
+		_, err = q.Exec("insert into ZTK_Loop_Data_Channel (ZTK_Loop_Data_id,ZTK_Channel_id,value ) values(?,?,?);", sampleId, channel.Chid, channels[name])
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
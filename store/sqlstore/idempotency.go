@@ -0,0 +1,59 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+// Response stored for the Idempotency-Key of a user
+func (s *Store) GetIdempotentResponse(userId int, key string) (store.IdempotentResponse, error) {
+
+	response := store.IdempotentResponse{UserId: userId, Key: key}
+
+	row := s.db.QueryRow("select method,path,request_hash,status_code,response,created from ZTK_Idempotency_Key where user_id = ? and idempotency_key = ?", userId, key)
+
+	err := row.Scan(&response.Method, &response.Path, &response.RequestHash, &response.StatusCode, &response.Body, &response.Created)
+
+	if err == sql.ErrNoRows {
+		return response, store.ErrNotFound
+	}
+
+	return response, err
+}
+
+// Store the response to a request sent with an Idempotency-Key
+func (s *Store) InsertIdempotentResponse(response *store.IdempotentResponse) error {
+
+	return s.inTx(func(tx *sql.Tx) error {
+
+		var count int
+
+		err := tx.QueryRow("select count(*) from ZTK_Idempotency_Key where user_id = ? and idempotency_key = ?", response.UserId, response.Key).Scan(&count)
+
+		if err != nil {
+			return err
+		}
+
+		if count > 0 {
+			return store.Invalid("Idempotency-Key %q is already used", response.Key)
+		}
+
+		_, err = tx.Exec("insert into ZTK_Idempotency_Key (user_id,idempotency_key,method,path,request_hash,status_code,response,created ) values(?,?,?,?,?,?,?,?);", response.UserId, response.Key, response.Method, response.Path, response.RequestHash, response.StatusCode, response.Body, response.Created)
+
+		return err
+	})
+}
+
+// Forget the responses stored before a time, returning how many
+func (s *Store) DeleteIdempotentResponses(before model.Time) (int64, error) {
+
+	result, err := s.db.Exec("delete from ZTK_Idempotency_Key where created < ?", before)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,376 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+func (s *Store) InsertEventLog(log *model.Logs_Event, autoCreate bool) (int64, error) {
+
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		var err error
+
+		id, err = insertEventLog(tx, log, autoCreate)
+
+		return err
+	})
+
+	return id, err
+}
+
+func insertEventLog(q queryer, log *model.Logs_Event, autoCreate bool) (int64, error) {
+
+	var err error
+
+	log.Etypeid, err = eventTypes.resolve(q, log.Etypename, log.Etypeid, autoCreate, log.Createdby, log.Ecreated)
+
+	if err == nil {
+		err = eventTypes.check(q, log.Etypeid)
+	}
+
+	if err == nil {
+		err = checkChamber(q, log.Echamberid)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := q.Exec("insert into ZTK_Logs_Event (log_id,program_name,program_date_time,ZTK_Logs_Event_Type_id,ZTK_Chamber_id,ZTK_Users_id,created_by,created,modified_by,modified,device_created,device_modified ) values(?,?,?,?,?,?,?,?,?,?,?,?);", log.Lid, log.Pname, log.Pdatetime, log.Etypeid, log.Echamberid, log.Eid, log.Createdby, log.Ecreated, log.Modifiedby, log.Emodified, log.Edevicecreated, log.Edevicemodified)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (s *Store) ListEventLogs() ([]model.Logs_Event, error) {
+
+	return s.queryEventLogs("")
+}
+
+func (s *Store) ListChamberEventLogs(chamberId int) ([]model.Logs_Event, error) {
+
+	return s.queryEventLogs(" where e.ZTK_Chamber_id = ?", chamberId)
+}
+
+func (s *Store) queryEventLogs(where string, args ...interface{}) ([]model.Logs_Event, error) {
+
+	logs := []model.Logs_Event{}
+
+	rows, err := s.db.Query("select e.log_id,e.program_name,coalesce(e.program_date_time,''),e.ZTK_Logs_Event_Type_id,coalesce(t.events_type,''),e.ZTK_Chamber_id,e.ZTK_Users_id,e.created_by,coalesce(e.created,''),e.modified_by,coalesce(e.modified,''),coalesce(e.device_created,''),coalesce(e.device_modified,'') from ZTK_Logs_Event e left join ZTK_Logs_Event_Type t on t.id = e.ZTK_Logs_Event_Type_id"+where+" order by e.id", args...)
+
+	if err != nil {
+		return logs, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var log model.Logs_Event
+		err = rows.Scan(&log.Lid, &log.Pname, &log.Pdatetime, &log.Etypeid, &log.Etypename, &log.Echamberid, &log.Eid, &log.Createdby, &log.Ecreated, &log.Modifiedby, &log.Emodified, &log.Edevicecreated, &log.Edevicemodified)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+func (s *Store) InsertTestLog(log *model.Logs_Test, autoCreate bool) (int64, error) {
+
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		var err error
+
+		id, err = insertTestLog(tx, log, autoCreate)
+
+		return err
+	})
+
+	return id, err
+}
+
+func insertTestLog(q queryer, log *model.Logs_Test, autoCreate bool) (int64, error) {
+
+	var err error
+
+	log.Ttypeid, err = testTypes.resolve(q, log.Ttypename, log.Ttypeid, autoCreate, log.Tcreatedby, log.Tcreated)
+
+	if err == nil {
+		err = testTypes.check(q, log.Ttypeid)
+	}
+
+	if err == nil {
+		err = checkChamber(q, log.Tchamberid)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := q.Exec("insert into ZTK_Logs_Test (log_id,log_name,log_date_time,ZTK_Logs_Test_Type_id,ZTK_Chamber_id,ZTK_Users_id,created_by,created,modified_by,modified,device_created,device_modified ) values(?,?,?,?,?,?,?,?,?,?,?,?);", log.Tid, log.Tname, log.Tdatetime, log.Ttypeid, log.Tchamberid, log.Tuserid, log.Tcreatedby, log.Tcreated, log.Tmodifiedby, log.Tmodified, log.Tdevicecreated, log.Tdevicemodified)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+// Columns of a test log l and the name of its type t, read from testLogTables
+const testLogColumns = "l.log_id,l.log_name,coalesce(l.log_date_time,''),l.ZTK_Logs_Test_Type_id,coalesce(t.test_type,''),l.ZTK_Chamber_id,l.ZTK_Users_id,l.created_by,coalesce(l.created,''),l.modified_by,coalesce(l.modified,''),coalesce(l.device_created,''),coalesce(l.device_modified,'')"
+
+const testLogTables = " from ZTK_Logs_Test l left join ZTK_Logs_Test_Type t on t.id = l.ZTK_Logs_Test_Type_id"
+
+func scanTestLog(row interface{ Scan(...interface{}) error }, log *model.Logs_Test) error {
+
+	return row.Scan(&log.Tid, &log.Tname, &log.Tdatetime, &log.Ttypeid, &log.Ttypename, &log.Tchamberid, &log.Tuserid, &log.Tcreatedby, &log.Tcreated, &log.Tmodifiedby, &log.Tmodified, &log.Tdevicecreated, &log.Tdevicemodified)
+}
+
+func (s *Store) ListTestLogs() ([]model.Logs_Test, error) {
+
+	return s.queryTestLogs("")
+}
+
+func (s *Store) ListChamberTestLogs(chamberId int) ([]model.Logs_Test, error) {
+
+	return s.queryTestLogs(" where l.ZTK_Chamber_id = ?", chamberId)
+}
+
+func (s *Store) queryTestLogs(where string, args ...interface{}) ([]model.Logs_Test, error) {
+
+	logs := []model.Logs_Test{}
+
+	rows, err := s.db.Query("select "+testLogColumns+testLogTables+where+" order by l.id", args...)
+
+	if err != nil {
+		return logs, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var log model.Logs_Test
+		err = scanTestLog(rows, &log)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+// Latest test log recorded with the log id and its row id
+func (s *Store) GetTestLog(logId string) (int64, model.Logs_Test, error) {
+
+	var id int64
+	var log model.Logs_Test
+
+	err := s.db.QueryRow("select l.id,"+testLogColumns+testLogTables+" where l.log_id = ? order by l.id desc limit 1", logId).Scan(&id, &log.Tid, &log.Tname, &log.Tdatetime, &log.Ttypeid, &log.Ttypename, &log.Tchamberid, &log.Tuserid, &log.Tcreatedby, &log.Tcreated, &log.Tmodifiedby, &log.Tmodified, &log.Tdevicecreated, &log.Tdevicemodified)
+
+	if err == sql.ErrNoRows {
+		return 0, log, store.ErrNotFound
+	}
+
+	return id, log, err
+}
+
+func (s *Store) SetTestVerdict(id int64, profileId int, verdict string, detail string) error {
+
+	_, err := s.db.Exec("update ZTK_Logs_Test set verdict=?,verdict_detail=?,ZTK_Logs_Test_Profile_id=? where id = ?", verdict, detail, profileId, id)
+
+	return err
+}
+
+func (s *Store) GetTestVerdict(id int64) (string, error) {
+
+	var detail sql.NullString
+
+	err := s.db.QueryRow("select verdict_detail from ZTK_Logs_Test where id = ?", id).Scan(&detail)
+
+	if err == sql.ErrNoRows || (err == nil && !detail.Valid) {
+		return "", store.ErrNotFound
+	}
+
+	return detail.String, err
+}
+
+func (s *Store) InsertMaintenanceLog(log *model.Logs_Maintenance) (int64, error) {
+
+	return insertMaintenanceLog(s.db, log)
+}
+
+func insertMaintenanceLog(q queryer, log *model.Logs_Maintenance) (int64, error) {
+
+	err := checkChamber(q, log.Mchamberid)
+
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := q.Exec("insert into ZTK_Logs_Maintenance (component_name,runtime_hr,counter,days_till_service,maintenance_pending,maintenance_status,ZTK_Chamber_id,created,modified,created_by,modified_by,device_created,device_modified ) values(?,?,?,?,?,?,?,?,?,?,?,?,?);", log.Mname, log.Mruntime, log.Mcounter, log.Mservice, log.Mpending, log.Mstatus, log.Mchamberid, log.Mcreated, log.Mmodified, log.Mcreatedby, log.Mmodifiedby, log.Mdevicecreated, log.Mdevicemodified)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (s *Store) ListMaintenanceLogs() ([]model.Logs_Maintenance, error) {
+
+	return s.queryMaintenanceLogs("")
+}
+
+func (s *Store) ListChamberMaintenanceLogs(chamberId int) ([]model.Logs_Maintenance, error) {
+
+	return s.queryMaintenanceLogs(" where ZTK_Chamber_id = ?", chamberId)
+}
+
+func (s *Store) queryMaintenanceLogs(where string, args ...interface{}) ([]model.Logs_Maintenance, error) {
+
+	logs := []model.Logs_Maintenance{}
+
+	rows, err := s.db.Query("select component_name,runtime_hr,counter,days_till_service,maintenance_pending,maintenance_status,ZTK_Chamber_id,coalesce(created,''),coalesce(modified,''),created_by,modified_by,coalesce(device_created,''),coalesce(device_modified,'') from ZTK_Logs_Maintenance"+where+" order by id", args...)
+
+	if err != nil {
+		return logs, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var log model.Logs_Maintenance
+		err = rows.Scan(&log.Mname, &log.Mruntime, &log.Mcounter, &log.Mservice, &log.Mpending, &log.Mstatus, &log.Mchamberid, &log.Mcreated, &log.Mmodified, &log.Mcreatedby, &log.Mmodifiedby, &log.Mdevicecreated, &log.Mdevicemodified)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+func (s *Store) InsertIocardinfo(log *model.Io_card_Info) (int64, error) {
+
+	result, err := s.db.Exec("insert into ZTK_IO_Card_Info (card_address,card_type,card_version,card_serial_number,secret_key,customer_id,mfg_date,created,modified,created_by,modified_by,device_created,device_modified ) values(?,?,?,?,?,?,?,?,?,?,?,?,?);", log.Iaddress, log.Itype, log.Iversion, log.Inumber, log.Ikey, log.Iid, log.Idate, log.Icreated, log.Imodified, log.Icreatedby, log.Imodifiedby, log.Idevicecreated, log.Idevicemodified)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (s *Store) ListIocardinfo() ([]model.Io_card_Info, error) {
+
+	return s.queryIocardinfo("")
+}
+
+func (s *Store) queryIocardinfo(where string, args ...interface{}) ([]model.Io_card_Info, error) {
+
+	logs := []model.Io_card_Info{}
+
+	rows, err := s.db.Query("select card_address,card_type,card_version,card_serial_number,secret_key,customer_id,coalesce(mfg_date,''),coalesce(created,''),coalesce(modified,''),created_by,modified_by,coalesce(device_created,''),coalesce(device_modified,'') from ZTK_IO_Card_Info"+where+" order by id", args...)
+
+	if err != nil {
+		return logs, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var log model.Io_card_Info
+		err = rows.Scan(&log.Iaddress, &log.Itype, &log.Iversion, &log.Inumber, &log.Ikey, &log.Iid, &log.Idate, &log.Icreated, &log.Imodified, &log.Icreatedby, &log.Imodifiedby, &log.Idevicecreated, &log.Idevicemodified)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+// Insert every part of a Logs_All document in one transaction. Nothing is
+// stored if any part fails.
+func (s *Store) InsertAll(log *model.Logs_All, autoCreate bool) (store.AllIds, error) {
+
+	var ids store.AllIds
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		var err error
+
+		if log.Event != nil {
+
+			event := log.Event
+
+			if t := event.LogEventType; t != nil {
+
+				event.Etypeid, err = eventTypes.resolveNested(tx, eventTypeRow(t))
+
+				if err != nil {
+					return err
+				}
+			}
+
+			ids.EventId, err = insertEventLog(tx, event, autoCreate)
+
+			if err != nil {
+				return err
+			}
+
+			ids.EventTypeId = event.Etypeid
+		}
+
+		if log.Test != nil {
+
+			test := log.Test
+
+			if t := test.LogTestType; t != nil {
+
+				test.Ttypeid, err = testTypes.resolveNested(tx, testTypeRow(t))
+
+				if err != nil {
+					return err
+				}
+			}
+
+			ids.TestId, err = insertTestLog(tx, test, autoCreate)
+
+			if err != nil {
+				return err
+			}
+
+			ids.TestTypeId = test.Ttypeid
+		}
+
+		if log.Maintenance != nil {
+
+			ids.MaintenanceId, err = insertMaintenanceLog(tx, log.Maintenance)
+		}
+
+		return err
+	})
+
+	return ids, err
+}
+
+// Insert a row into ZTK_Activity_Log recording the new value of a record.
+func (s *Store) InsertActivity(tableId int, actionType string, newvalue string, userId int) error {
+
+	_, err := s.db.Exec("insert into ZTK_Activity_Log (`ZTK_Table_Id`, `action_type`, `new_value`, `ZTK_Users_Id`, `created`) values(?,?,?,?,?);", tableId, actionType, newvalue, userId, model.Now())
+
+	return err
+}
@@ -0,0 +1,326 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+func (s *Store) InsertLoopData(log *model.Loop_Data, autoCreate bool) (int64, error) {
+
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		err := checkChamber(tx, log.Dchamberid)
+
+		if err != nil {
+			return err
+		}
+
+		_, err = loopDataId(tx, log)
+
+		if err == nil {
+			return store.Invalid("chamber %d has a Loop_Data sample at %s already", log.Dchamberid, log.Ddatatime)
+		}
+
+		if err != store.ErrNotFound {
+			return err
+		}
+
+		id, err = insertLoopData(tx, log)
+
+		if err != nil {
+			return err
+		}
+
+		return setLoopDataValues(tx, id, log, autoCreate)
+	})
+
+	return id, err
+}
+
+func (s *Store) UpdateLoopData(log *model.Loop_Data, autoCreate bool) error {
+
+	return s.inTx(func(tx *sql.Tx) error {
+
+		id, err := loopDataId(tx, log)
+
+		if err == nil {
+			err = updateLoopData(tx, id, log)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return replaceLoopDataValues(tx, id, log, autoCreate)
+	})
+}
+
+// Store the sample, replacing the one of its chamber at its date time, and
+// report whether it was created. MySQL inserts or replaces the row in one
+// statement; SQLite runs the transactions one after the other on its single
+// connection.
+func (s *Store) UpsertLoopData(log *model.Loop_Data, autoCreate bool) (int64, bool, error) {
+
+	var id int64
+	var created bool
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		err := checkChamber(tx, log.Dchamberid)
+
+		if err != nil {
+			return err
+		}
+
+		if s.dialect == MySQL {
+			id, created, err = upsertLoopData(tx, log)
+		} else {
+			id, created, err = insertOrUpdateLoopData(tx, log)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if created {
+			return setLoopDataValues(tx, id, log, autoCreate)
+		}
+
+		return replaceLoopDataValues(tx, id, log, autoCreate)
+	})
+
+	return id, created, err
+}
+
+// Id of the sample of the chamber at the date time of log, ErrNotFound when
+// there is none
+func loopDataId(q queryer, log *model.Loop_Data) (int64, error) {
+
+	var id int64
+
+	err := q.QueryRow("select id from ZTK_Loop_Data where ZTK_Chamber_id = ? and date_time = ?", log.Dchamberid, log.Ddatatime).Scan(&id)
+
+	if err == sql.ErrNoRows {
+		return 0, store.ErrNotFound
+	}
+
+	return id, err
+}
+
+func insertLoopData(q queryer, log *model.Loop_Data) (int64, error) {
+
+	result, err := q.Exec("insert into ZTK_Loop_Data (temp_sp,temp_pv,hum_sp,hum_pv,press_sp,press_pv,date_time,ZTK_Chamber_id ) values(?,?,?,?,?,?,?,?);", log.Dtsp, log.Dtpv, log.Dhsp, log.Dhpv, log.Dpsp, log.Dppv, log.Ddatatime, log.Dchamberid)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func updateLoopData(q queryer, id int64, log *model.Loop_Data) error {
+
+	_, err := q.Exec("UPDATE ZTK_Loop_Data SET temp_sp=?,temp_pv=?,hum_sp=?,hum_pv=?,press_sp=?,press_pv=? WHERE id = ?", log.Dtsp, log.Dtpv, log.Dhsp, log.Dhpv, log.Dpsp, log.Dppv, id)
+
+	return err
+}
+
+// Insert the sample or replace the one of its chamber at its date time in a
+// single statement. MySQL counts 1 affected row for an insert and 2, or 0
+// when nothing changed, for a replaced row.
+func upsertLoopData(q queryer, log *model.Loop_Data) (int64, bool, error) {
+
+	result, err := q.Exec("insert into ZTK_Loop_Data (temp_sp,temp_pv,hum_sp,hum_pv,press_sp,press_pv,date_time,ZTK_Chamber_id ) values(?,?,?,?,?,?,?,?) on duplicate key update id=LAST_INSERT_ID(id),temp_sp=values(temp_sp),temp_pv=values(temp_pv),hum_sp=values(hum_sp),hum_pv=values(hum_pv),press_sp=values(press_sp),press_pv=values(press_pv);", log.Dtsp, log.Dtpv, log.Dhsp, log.Dhpv, log.Dpsp, log.Dppv, log.Ddatatime, log.Dchamberid)
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	id, err := result.LastInsertId()
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	affected, err := result.RowsAffected()
+
+	return id, affected == 1, err
+}
+
+// Insert the sample or update the one of its chamber at its date time, for a
+// DB whose transactions do not overlap
+func insertOrUpdateLoopData(q queryer, log *model.Loop_Data) (int64, bool, error) {
+
+	id, err := loopDataId(q, log)
+
+	if err == store.ErrNotFound {
+		id, err = insertLoopData(q, log)
+		return id, true, err
+	}
+
+	if err == nil {
+		err = updateLoopData(q, id, log)
+	}
+
+	return id, false, err
+}
+
+// Store the channel and calibrated values of a new sample
+func setLoopDataValues(q queryer, id int64, log *model.Loop_Data, autoCreate bool) error {
+
+	err := setLoopDataChannels(q, id, log.Dchannels, autoCreate)
+
+	if err != nil {
+		return err
+	}
+
+	return setLoopDataCalibrated(q, id, log.Dcalibrated)
+}
+
+// Replace the channel and calibrated values of a sample
+func replaceLoopDataValues(q queryer, id int64, log *model.Loop_Data, autoCreate bool) error {
+
+	for _, table := range []string{"ZTK_Loop_Data_Channel", "ZTK_Loop_Data_Calibration"} {
+
+		_, err := q.Exec("delete from "+table+" where ZTK_Loop_Data_id = ?", id)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return setLoopDataValues(q, id, log, autoCreate)
+}
+
+func (s *Store) ListLoopData() ([]model.Loop_Data, error) {
+
+	logs, _, err := queryLoopData(s.db, "")
+
+	return logs, err
+}
+
+// Loop_Data captured at or after start and before end, oldest first. A zero
+// start or end leaves that side open.
+func (s *Store) ListLoopDataBetween(start model.Time, end model.Time) ([]model.Loop_Data, error) {
+
+	logs, _, err := queryLoopDataBetween(s.db, " where 1=1", nil, start, end)
+
+	return logs, err
+}
+
+// Loop_Data of the chamber between start and end, as ListLoopDataBetween
+func (s *Store) ListChamberLoopData(chamberId int, start model.Time, end model.Time) ([]model.Loop_Data, error) {
+
+	logs, _, err := queryLoopDataBetween(s.db, " where d.ZTK_Chamber_id = ?", []interface{}{chamberId}, start, end)
+
+	return logs, err
+}
+
+func queryLoopDataBetween(q queryer, where string, args []interface{}, start model.Time, end model.Time) ([]model.Loop_Data, []int64, error) {
+
+	if !start.IsZero() {
+		where += " and d.date_time >= ?"
+		args = append(args, start)
+	}
+
+	if !end.IsZero() {
+		where += " and d.date_time < ?"
+		args = append(args, end)
+	}
+
+	return queryLoopData(q, where, args...)
+}
+
+// Read the samples of ZTK_Loop_Data d matching where, oldest first, with
+// their channel values and calibrated values, and their ids. A sample is
+// read once for each of its channel values; the rows of a sample are merged
+// into one.
+func queryLoopData(q queryer, where string, args ...interface{}) ([]model.Loop_Data, []int64, error) {
+
+	logs := []model.Loop_Data{}
+
+	var ids []int64
+
+	rows, err := q.Query("select d.id,d.temp_sp,d.temp_pv,d.hum_sp,d.hum_pv,d.press_sp,d.press_pv,d.date_time,d.ZTK_Chamber_id,c.name,v.value from ZTK_Loop_Data d left join ZTK_Loop_Data_Channel v on v.ZTK_Loop_Data_id = d.id left join ZTK_Channel c on c.id = v.ZTK_Channel_id"+where+" order by d.date_time,d.id", args...)
+
+	if err != nil {
+		return logs, ids, err
+	}
+
+	defer rows.Close()
+
+	// Index in logs of every sample id
+	index := map[int64]int{}
+
+	var lastId int64
+
+	for rows.Next() {
+		var id int64
+		var log model.Loop_Data
+		var channel sql.NullString
+		var value sql.NullFloat64
+		err = rows.Scan(&id, &log.Dtsp, &log.Dtpv, &log.Dhsp, &log.Dhpv, &log.Dpsp, &log.Dppv, &log.Ddatatime, &log.Dchamberid, &channel, &value)
+		if err != nil {
+			return logs, ids, err
+		}
+		if len(logs) == 0 || id != lastId {
+			logs = append(logs, log)
+			ids = append(ids, id)
+			index[id] = len(logs) - 1
+			lastId = id
+		}
+		if channel.Valid {
+			last := &logs[len(logs)-1]
+			if last.Dchannels == nil {
+				last.Dchannels = map[string]float64{}
+			}
+			last.Dchannels[channel.String] = value.Float64
+		}
+	}
+
+	err = rows.Err()
+
+	if err != nil || len(logs) == 0 {
+		return logs, ids, err
+	}
+
+	rows.Close()
+
+	return logs, ids, readLoopDataCalibrated(q, logs, index, where, args)
+}
+
+// Add the calibrated values of the samples of ZTK_Loop_Data d matching where
+func readLoopDataCalibrated(q queryer, logs []model.Loop_Data, index map[int64]int, where string, args []interface{}) error {
+
+	rows, err := q.Query("select k.ZTK_Loop_Data_id,k.channel,k.raw_value,k.ZTK_Calibration_id from ZTK_Loop_Data_Calibration k where k.ZTK_Loop_Data_id in (select d.id from ZTK_Loop_Data d"+where+")", args...)
+
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var channel string
+		var value model.Calibrated_Value
+		err = rows.Scan(&id, &channel, &value.Vraw, &value.Vcalibrationid)
+		if err != nil {
+			return err
+		}
+		i, ok := index[id]
+		if !ok {
+			continue
+		}
+		if logs[i].Dcalibrated == nil {
+			logs[i].Dcalibrated = map[string]model.Calibrated_Value{}
+		}
+		logs[i].Dcalibrated[channel] = value
+	}
+
+	return rows.Err()
+}
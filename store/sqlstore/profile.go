@@ -0,0 +1,129 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+// Store the profile and its steps as the next version for the test type,
+// returning the new profile id and version.
+func (s *Store) InsertTestProfile(profile *model.Logs_Test_Profile) (int, int, error) {
+
+	var profileId int64
+	var version int
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		err := testTypes.exists(tx, profile.Ptypeid)
+
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow("select coalesce(max(version),0)+1 from ZTK_Logs_Test_Profile where ZTK_Logs_Test_Type_id = ?"+s.forUpdate(), profile.Ptypeid).Scan(&version)
+
+		if err != nil {
+			return err
+		}
+
+		result, err := tx.Exec("insert into ZTK_Logs_Test_Profile (ZTK_Logs_Test_Type_id,version,comment,created_by,created ) values(?,?,?,?,?);", profile.Ptypeid, version, profile.Pcomment, profile.Pcreatedby, now())
+
+		if err != nil {
+			return err
+		}
+
+		profileId, err = result.LastInsertId()
+
+		if err != nil {
+			return err
+		}
+
+		stmt, err := tx.Prepare("insert into ZTK_Logs_Test_Profile_Step (ZTK_Logs_Test_Profile_id,step_no,step_type,temp_sp,hum_sp,press_sp,duration_min,temp_tol,hum_tol,press_tol ) values(?,?,?,?,?,?,?,?,?,?);")
+
+		if err != nil {
+			return err
+		}
+
+		defer stmt.Close()
+
+		for i, step := range profile.Psteps {
+
+			_, err = stmt.Exec(profileId, i+1, step.Stype, step.Stsp, step.Shsp, step.Spsp, step.Sduration, step.Sttol, step.Shtol, step.Sptol)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return int(profileId), version, err
+}
+
+// Load a profile with its steps. A version of 0 selects the latest version
+// of the test type.
+func (s *Store) GetTestProfile(typeId int, version int) (model.Logs_Test_Profile, error) {
+
+	var profile model.Logs_Test_Profile
+
+	row := s.db.QueryRow("select id,ZTK_Logs_Test_Type_id,version,comment,created_by,created from ZTK_Logs_Test_Profile where ZTK_Logs_Test_Type_id = ? and (version = ? or ? = 0) order by version desc limit 1", typeId, version, version)
+
+	err := row.Scan(&profile.Pid, &profile.Ptypeid, &profile.Pversion, &profile.Pcomment, &profile.Pcreatedby, &profile.Pcreated)
+
+	if err == sql.ErrNoRows {
+		return profile, store.ErrNotFound
+	}
+
+	if err != nil {
+		return profile, err
+	}
+
+	rows, err := s.db.Query("select step_no,step_type,temp_sp,hum_sp,press_sp,duration_min,temp_tol,hum_tol,press_tol from ZTK_Logs_Test_Profile_Step where ZTK_Logs_Test_Profile_id = ? order by step_no", profile.Pid)
+
+	if err != nil {
+		return profile, err
+	}
+
+	defer rows.Close()
+
+	profile.Psteps = []model.Logs_Test_Profile_Step{}
+
+	for rows.Next() {
+		var step model.Logs_Test_Profile_Step
+		err = rows.Scan(&step.Sno, &step.Stype, &step.Stsp, &step.Shsp, &step.Spsp, &step.Sduration, &step.Sttol, &step.Shtol, &step.Sptol)
+		if err != nil {
+			return profile, err
+		}
+		profile.Psteps = append(profile.Psteps, step)
+	}
+
+	return profile, rows.Err()
+}
+
+// Every version of the profile of a test type, without steps
+func (s *Store) ListTestProfiles(typeId int) ([]model.Logs_Test_Profile, error) {
+
+	logs := []model.Logs_Test_Profile{}
+
+	rows, err := s.db.Query("select id,ZTK_Logs_Test_Type_id,version,comment,created_by,created from ZTK_Logs_Test_Profile where ZTK_Logs_Test_Type_id = ? order by version", typeId)
+
+	if err != nil {
+		return logs, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var log model.Logs_Test_Profile
+		err = rows.Scan(&log.Pid, &log.Ptypeid, &log.Pversion, &log.Pcomment, &log.Pcreatedby, &log.Pcreated)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
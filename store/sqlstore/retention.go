@@ -0,0 +1,287 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+const hourlyColumns = "ZTK_Chamber_id,hour,samples,temp_sp,temp_pv,temp_pv_min,temp_pv_max,hum_sp,hum_pv,hum_pv_min,hum_pv_max,press_sp,press_pv,press_pv_min,press_pv_max"
+
+// Date time of the oldest Loop_Data sample
+func (s *Store) OldestLoopData() (model.Time, error) {
+
+	return oldest(s.db, "select min(date_time) from ZTK_Loop_Data")
+}
+
+// Archive the samples captured at or after start and before end, roll them
+// up into ZTK_Loop_Data_Hourly and delete them, returning how many. The
+// samples are read, archived and deleted by id in one transaction, so a
+// sample stored meanwhile is neither deleted nor archived; it is pruned by
+// the next call. A failed archive prunes nothing.
+func (s *Store) PruneLoopData(start model.Time, end model.Time, archive func([]model.Loop_Data) error) (int64, error) {
+
+	var count int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		logs, ids, err := queryLoopDataBetween(tx, " where 1=1", nil, start, end)
+
+		if err != nil || len(logs) == 0 {
+			return err
+		}
+
+		err = archive(logs)
+
+		if err != nil {
+			return err
+		}
+
+		for _, hour := range model.RollUpLoopData(logs) {
+
+			err = mergeLoopDataHourly(tx, hour)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, table := range []string{"ZTK_Loop_Data_Channel", "ZTK_Loop_Data_Calibration"} {
+
+			_, err = deleteIds(tx, table, "ZTK_Loop_Data_id", ids)
+
+			if err != nil {
+				return err
+			}
+		}
+
+		count, err = deleteIds(tx, "ZTK_Loop_Data", "id", ids)
+
+		return err
+	})
+
+	return count, err
+}
+
+// Hourly Loop_Data of the hours at or after start and before end, oldest
+// first. A zero start or end leaves that side open.
+func (s *Store) ListLoopDataHourly(start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error) {
+
+	return s.queryLoopDataHourly(" where 1=1", nil, start, end)
+}
+
+// Hourly Loop_Data of the chamber between start and end, as
+// ListLoopDataHourly
+func (s *Store) ListChamberLoopDataHourly(chamberId int, start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error) {
+
+	return s.queryLoopDataHourly(" where ZTK_Chamber_id = ?", []interface{}{chamberId}, start, end)
+}
+
+func (s *Store) queryLoopDataHourly(where string, args []interface{}, start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error) {
+
+	hours := []model.Loop_Data_Hourly{}
+
+	if !start.IsZero() {
+		where += " and hour >= ?"
+		args = append(args, start)
+	}
+
+	if !end.IsZero() {
+		where += " and hour < ?"
+		args = append(args, end)
+	}
+
+	rows, err := s.db.Query("select "+hourlyColumns+" from ZTK_Loop_Data_Hourly"+where+" order by hour,ZTK_Chamber_id", args...)
+
+	if err != nil {
+		return hours, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		hour, err := scanLoopDataHourly(rows)
+		if err != nil {
+			return hours, err
+		}
+		hours = append(hours, hour)
+	}
+
+	return hours, rows.Err()
+}
+
+// Date time of the oldest row of ZTK_Activity_Log
+func (s *Store) OldestActivity() (model.Time, error) {
+
+	return oldest(s.db, "select min(created) from ZTK_Activity_Log")
+}
+
+// Rows of ZTK_Activity_Log created at or after start and before end, oldest
+// first. A zero start or end leaves that side open.
+func (s *Store) ListActivities(start model.Time, end model.Time) ([]store.Activity, error) {
+
+	return queryActivities(s.db, start, end)
+}
+
+// Archive the rows of ZTK_Activity_Log created at or after start and before
+// end and delete them, returning how many, in one transaction as
+// PruneLoopData
+func (s *Store) PruneActivities(start model.Time, end model.Time, archive func([]store.Activity) error) (int64, error) {
+
+	var count int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		activities, err := queryActivities(tx, start, end)
+
+		if err != nil || len(activities) == 0 {
+			return err
+		}
+
+		err = archive(activities)
+
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int64, len(activities))
+
+		for i, activity := range activities {
+			ids[i] = activity.Id
+		}
+
+		count, err = deleteIds(tx, "ZTK_Activity_Log", "id", ids)
+
+		return err
+	})
+
+	return count, err
+}
+
+func queryActivities(q queryer, start model.Time, end model.Time) ([]store.Activity, error) {
+
+	activities := []store.Activity{}
+
+	where := " where 1=1"
+	var args []interface{}
+
+	if !start.IsZero() {
+		where += " and created >= ?"
+		args = append(args, start)
+	}
+
+	if !end.IsZero() {
+		where += " and created < ?"
+		args = append(args, end)
+	}
+
+	rows, err := q.Query("select id,ZTK_Table_Id,action_type,new_value,ZTK_Users_Id,created from ZTK_Activity_Log"+where+" order by created,id", args...)
+
+	if err != nil {
+		return activities, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var activity store.Activity
+		err = rows.Scan(&activity.Id, &activity.TableId, &activity.ActionType, &activity.NewValue, &activity.UserId, &activity.Created)
+		if err != nil {
+			return activities, err
+		}
+		activities = append(activities, activity)
+	}
+
+	return activities, rows.Err()
+}
+
+// Delete the rows of the table whose column is one of the ids, a batch of
+// ids at a time, returning how many
+func deleteIds(q queryer, table string, column string, ids []int64) (int64, error) {
+
+	const batch = 500
+
+	var count int64
+
+	for len(ids) > 0 {
+
+		n := len(ids)
+
+		if n > batch {
+			n = batch
+		}
+
+		args := make([]interface{}, n)
+
+		for i, id := range ids[:n] {
+			args[i] = id
+		}
+
+		result, err := q.Exec("delete from "+table+" where "+column+" in (?"+strings.Repeat(",?", n-1)+")", args...)
+
+		if err != nil {
+			return count, err
+		}
+
+		deleted, err := result.RowsAffected()
+
+		count += deleted
+
+		if err != nil {
+			return count, err
+		}
+
+		ids = ids[n:]
+	}
+
+	return count, nil
+}
+
+// Read the smallest date time selected by query, ErrNotFound when the table
+// is empty
+func oldest(q queryer, query string) (model.Time, error) {
+
+	var at model.Time
+
+	err := q.QueryRow(query).Scan(&at)
+
+	if err == nil && at.IsZero() {
+		err = store.ErrNotFound
+	}
+
+	return at, err
+}
+
+// Store the roll-up of an hour, merged into the one stored for the hour
+func mergeLoopDataHourly(q queryer, hour model.Loop_Data_Hourly) error {
+
+	stored, err := scanLoopDataHourly(q.QueryRow("select "+hourlyColumns+" from ZTK_Loop_Data_Hourly where ZTK_Chamber_id = ? and hour = ?", hour.Hchamberid, hour.Hhour))
+
+	if err == nil {
+
+		_, err = q.Exec("delete from ZTK_Loop_Data_Hourly where ZTK_Chamber_id = ? and hour = ?", hour.Hchamberid, hour.Hhour)
+
+		stored.Merge(hour)
+
+		hour = stored
+	}
+
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	_, err = q.Exec("insert into ZTK_Loop_Data_Hourly ("+hourlyColumns+" ) values(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?);", hour.Hchamberid, hour.Hhour, hour.Hsamples, hour.Htsp, hour.Htpv, hour.Htpvmin, hour.Htpvmax, hour.Hhsp, hour.Hhpv, hour.Hhpvmin, hour.Hhpvmax, hour.Hpsp, hour.Hppv, hour.Hppvmin, hour.Hppvmax)
+
+	return err
+}
+
+func scanLoopDataHourly(row scanner) (model.Loop_Data_Hourly, error) {
+
+	var hour model.Loop_Data_Hourly
+
+	err := row.Scan(&hour.Hchamberid, &hour.Hhour, &hour.Hsamples, &hour.Htsp, &hour.Htpv, &hour.Htpvmin, &hour.Htpvmax, &hour.Hhsp, &hour.Hhpv, &hour.Hhpvmin, &hour.Hhpvmax, &hour.Hpsp, &hour.Hppv, &hour.Hppvmin, &hour.Hppvmax)
+
+	return hour, err
+}
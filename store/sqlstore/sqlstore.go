@@ -0,0 +1,155 @@
+// Package sqlstore stores the log tables in a SQL DB. MySQL is used by the
+// chamber PCs running a DB server, SQLite lets the log server run standalone
+// on small controllers with the DB in a local file.
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/go-sql-driver/mysql"
+	"github.com/mattn/go-sqlite3"
+)
+
+// SQL dialect of the DB, named after its database/sql driver
+
+type Dialect string
+
+const (
+	MySQL  Dialect = "mysql"
+	SQLite Dialect = "sqlite3"
+)
+
+// Struct to hold a store.Store backed by a SQL DB
+
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// Statement runner satisfied by both *sql.DB and *sql.Tx
+
+type queryer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// Open the DB configured in DBConfig and ensure that the connection is
+// available. Date times are read and written in the zone of DBTimeZone.
+func Open(dbConfiguration config.DBConfig) (*Store, error) {
+
+	dialect := Dialect(dbConfiguration.Driver())
+
+	if dialect != MySQL && dialect != SQLite {
+		return nil, fmt.Errorf("unknown DBDriver %q, use mysql or sqlite3", dialect)
+	}
+
+	loc, err := dbConfiguration.Location()
+
+	if err != nil {
+		return nil, fmt.Errorf("DBTimeZone %q: %s", dbConfiguration.DBTimeZone, err.Error())
+	}
+
+	model.SetDBLocation(loc)
+
+	db, err := sql.Open(string(dialect), dbConfiguration.ConnectString())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if dialect == SQLite {
+		// A single connection serialises the writers of the DB file.
+		db.SetMaxOpenConns(1)
+	}
+
+	err = db.Ping()
+
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return New(db, dialect), nil
+}
+
+// Create a Store using an open DB
+func New(db *sql.DB, dialect Dialect) *Store {
+
+	return &Store{db: db, dialect: dialect}
+}
+
+// DB the store runs on
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Dialect of the DB the store runs on
+func (s *Store) Dialect() Dialect {
+	return s.dialect
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Row lock taken when reading a value that is about to be incremented. SQLite
+// locks the whole DB for the write transaction instead.
+func (s *Store) forUpdate() string {
+
+	if s.dialect == MySQL {
+		return " for update"
+	}
+
+	return ""
+}
+
+// Report whether err rejects a row whose unique key another row holds, and
+// the clause a select in the same transaction needs to read that row: MySQL
+// reads the snapshot the transaction started with unless the select locks,
+// SQLite reads the rows committed.
+func duplicateKey(err error) (bool, string) {
+
+	var mysqlErr *mysql.MySQLError
+
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062, " lock in share mode"
+	}
+
+	var sqliteErr sqlite3.Error
+
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique, ""
+	}
+
+	return false, ""
+}
+
+// Run fn in a transaction, committing when it succeeds.
+func (s *Store) inTx(fn func(tx *sql.Tx) error) error {
+
+	tx, err := s.db.Begin()
+
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	err = fn(tx)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Current time of the server
+func now() model.Time {
+	return model.Now()
+}
@@ -0,0 +1,823 @@
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ramcharanpakala/goprojectes/config"
+	"github.com/Ramcharanpakala/goprojectes/migrate"
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+// Open a SQLite store in a temporary file with the schema migrated up
+func newTestStore(t *testing.T) *Store {
+
+	t.Helper()
+
+	s, err := Open(config.DBConfig{DBDriver: "sqlite3", DBPath: filepath.Join(t.TempDir(), "klima_chamber.db")})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { s.Close() })
+
+	_, err = migrate.Up(s.DB(), string(s.Dialect()), 0)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestLegacyTimeZone(t *testing.T) {
+
+	s, err := Open(config.DBConfig{DBDriver: "sqlite3", DBPath: filepath.Join(t.TempDir(), "klima_chamber.db"), DBTimeZone: "Europe/Berlin"})
+
+	t.Cleanup(func() { model.SetDBLocation(time.UTC) })
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer s.Close()
+
+	if _, err = migrate.Up(s.DB(), string(s.Dialect()), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A sample stored by a release before date times were kept in UTC, in
+	// the zone of the chamber
+	_, err = s.DB().Exec("insert into ZTK_Loop_Data (temp_pv,date_time) values(20,'2019-01-15 07:05:40')")
+
+	if err == nil {
+		_, err = s.InsertLoopData(&model.Loop_Data{Dtpv: 21, Ddatatime: model.NewTime(time.Date(2019, 1, 15, 6, 10, 0, 0, time.UTC))}, false)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := s.ListLoopDataBetween(model.NewTime(time.Date(2019, 1, 15, 6, 0, 0, 0, time.UTC)), model.Time{})
+
+	if err != nil || len(samples) != 2 || !samples[0].Ddatatime.Equal(time.Date(2019, 1, 15, 6, 5, 40, 0, time.UTC)) {
+		t.Fatalf("samples %+v %v", samples, err)
+	}
+
+	var stored string
+
+	s.DB().QueryRow("select date_time from ZTK_Loop_Data where temp_pv = 21").Scan(&stored)
+
+	if stored != "2019-01-15 07:10:00" {
+		t.Fatalf("stored %q, want the zone of the DB", stored)
+	}
+}
+
+func TestTypesAndLogs(t *testing.T) {
+
+	s := newTestStore(t)
+
+	id, err := s.InsertEventType(&model.Logs_Event_Type{Levents: "trips", Lcreated1: model.NewTime(time.Date(2019, 1, 1, 4, 0, 55, 0, time.UTC))})
+
+	if err != nil || id != 1 {
+		t.Fatalf("InsertEventType: %d %v", id, err)
+	}
+
+	// Names are compared without case, as in MySQL
+	for _, name := range []string{"trips", "Trips"} {
+
+		_, err = s.InsertEventType(&model.Logs_Event_Type{Levents: name})
+
+		if !store.IsInvalid(err) {
+			t.Fatalf("duplicate InsertEventType %q: %v", name, err)
+		}
+	}
+
+	programDate := model.NewTime(time.Date(2019, 1, 3, 4, 25, 20, 0, time.UTC))
+
+	event := model.Logs_Event{Lid: "TE001", Pname: "ABC", Pdatetime: programDate, Etypename: "TRIPS"}
+
+	_, err = s.InsertEventLog(&event, false)
+
+	if err != nil || event.Etypeid != 1 {
+		t.Fatalf("InsertEventLog: %v, type id %d", err, event.Etypeid)
+	}
+
+	_, err = s.InsertEventLog(&model.Logs_Event{Lid: "TE002", Etypename: "alarm"}, false)
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertEventLog of unknown type: %v", err)
+	}
+
+	err = s.RetireEventType(1)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.InsertEventLog(&model.Logs_Event{Lid: "TE003", Etypeid: 1}, false)
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertEventLog of retired type: %v", err)
+	}
+
+	if err = s.RetireEventType(9); err != store.ErrNotFound {
+		t.Fatalf("RetireEventType of unknown id: %v", err)
+	}
+
+	logs, err := s.ListEventLogs()
+
+	if err != nil || len(logs) != 1 || !logs[0].Pdatetime.Equal(programDate.Time) || !logs[0].Ecreated.IsZero() || logs[0].Etypename != "trips" {
+		t.Fatalf("ListEventLogs: %v %v", logs, err)
+	}
+}
+
+func TestAutoCreateTypeConcurrently(t *testing.T) {
+
+	s := newTestStore(t)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			_, err := s.InsertEventLog(&model.Logs_Event{Lid: "TE001", Pname: "ABC", Etypename: "trips"}, true)
+
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if types, _ := s.ListEventTypes(); len(types) != 1 {
+		t.Fatalf("event types %v, want 1", types)
+	}
+
+	// The type is created by another writer between the lookup and the
+	// insert of this one
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		id, err := testTypes.resolve(&racingQueryer{queryer: tx}, "soak", 0, true, 0, model.Time{})
+
+		if err == nil && id != 1 {
+			t.Errorf("resolve of a type created meanwhile: id %d, want 1", id)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		t.Fatalf("resolve of a type created meanwhile: %v", err)
+	}
+}
+
+// Struct to hold a queryer that inserts every row twice, as when another
+// writer inserts it first
+type racingQueryer struct {
+	queryer
+}
+
+func (q *racingQueryer) Exec(query string, args ...interface{}) (sql.Result, error) {
+
+	if strings.HasPrefix(query, "insert ") {
+		q.queryer.Exec(query, args...)
+	}
+
+	return q.queryer.Exec(query, args...)
+}
+
+func TestInsertAllRollsBack(t *testing.T) {
+
+	s := newTestStore(t)
+
+	all := model.Logs_All{
+		Event:       &model.Logs_Event{Lid: "TE001", LogEventType: &model.Logs_Event_Type{Levents: "trips"}},
+		Maintenance: &model.Logs_Maintenance{Mname: "compressor"},
+		Test:        &model.Logs_Test{Tid: "TE001", Ttypename: "XYZ"},
+	}
+
+	_, err := s.InsertAll(&all, false)
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertAll with unknown test type: %v", err)
+	}
+
+	types, _ := s.ListEventTypes()
+	logs, _ := s.ListEventLogs()
+	maintenance, _ := s.ListMaintenanceLogs()
+
+	if len(types) != 0 || len(logs) != 0 || len(maintenance) != 0 {
+		t.Fatalf("rejected InsertAll stored %v %v %v", types, logs, maintenance)
+	}
+
+	ids, err := s.InsertAll(&all, true)
+
+	if err != nil || ids.EventId != 1 || ids.TestTypeId != 1 || ids.MaintenanceId != 1 {
+		t.Fatalf("InsertAll: %+v %v", ids, err)
+	}
+}
+
+func TestTestProfileVersions(t *testing.T) {
+
+	s := newTestStore(t)
+
+	_, _, err := s.InsertTestProfile(&model.Logs_Test_Profile{Ptypeid: 1})
+
+	if err != store.ErrNotFound {
+		t.Fatalf("InsertTestProfile of unknown type: %v", err)
+	}
+
+	s.InsertTestType(&model.Logs_Test_Type{Ltesttype: "XYZ"})
+
+	steps := []model.Logs_Test_Profile_Step{{Stype: "soak", Stsp: 25, Sduration: 10}}
+
+	for want := 1; want <= 2; want++ {
+
+		_, version, err := s.InsertTestProfile(&model.Logs_Test_Profile{Ptypeid: 1, Psteps: steps})
+
+		if err != nil || version != want {
+			t.Fatalf("InsertTestProfile: version %d %v, want %d", version, err, want)
+		}
+	}
+
+	profile, err := s.GetTestProfile(1, 0)
+
+	if err != nil || profile.Pversion != 2 || len(profile.Psteps) != 1 || profile.Psteps[0].Sno != 1 {
+		t.Fatalf("GetTestProfile: %+v %v", profile, err)
+	}
+
+	if _, err = s.GetTestProfile(1, 3); err != store.ErrNotFound {
+		t.Fatalf("GetTestProfile of unknown version: %v", err)
+	}
+}
+
+func TestTestVerdictOfRerun(t *testing.T) {
+
+	s := newTestStore(t)
+
+	s.InsertTestType(&model.Logs_Test_Type{Ltesttype: "XYZ"})
+
+	profileId, _, err := s.InsertTestProfile(&model.Logs_Test_Profile{Ptypeid: 1, Psteps: []model.Logs_Test_Profile_Step{{Stype: "soak", Stsp: 25, Sduration: 10}}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A re-run records a second test with the log id of the first
+	var ids []int64
+
+	for _, verdict := range []string{"PASS", "FAIL"} {
+
+		_, err = s.InsertTestLog(&model.Logs_Test{Tid: "SIM", Tname: "soak", Ttypeid: 1}, false)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		id, _, err := s.GetTestLog("SIM")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = s.GetTestVerdict(id); err != store.ErrNotFound {
+			t.Fatalf("GetTestVerdict of the %s test before it is evaluated: %v", verdict, err)
+		}
+
+		err = s.SetTestVerdict(id, profileId, verdict, verdict)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if ids[0] == ids[1] {
+		t.Fatalf("GetTestLog returned row %d for both tests", ids[0])
+	}
+
+	for i, want := range []string{"PASS", "FAIL"} {
+
+		if detail, err := s.GetTestVerdict(ids[i]); err != nil || detail != want {
+			t.Fatalf("GetTestVerdict of test %d: %q %v, want %q", i+1, detail, err, want)
+		}
+	}
+}
+
+func TestIdempotentResponses(t *testing.T) {
+
+	s := newTestStore(t)
+
+	created := model.NewTime(time.Date(2019, 1, 3, 4, 25, 20, 0, time.UTC))
+
+	response := store.IdempotentResponse{UserId: 7, Key: "TE001-1", Method: "POST", Path: "/Logs_Event", RequestHash: "ab", StatusCode: 201, Body: []byte(`{"status":"ok","id":1}`), Created: created}
+
+	if err := s.InsertIdempotentResponse(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.InsertIdempotentResponse(&response); !store.IsInvalid(err) {
+		t.Fatalf("key stored twice: %v", err)
+	}
+
+	if _, err := s.GetIdempotentResponse(9, "TE001-1"); err != store.ErrNotFound {
+		t.Fatalf("key of another user: %v", err)
+	}
+
+	stored, err := s.GetIdempotentResponse(7, "TE001-1")
+
+	if err != nil || string(stored.Body) != string(response.Body) || stored.StatusCode != 201 || stored.Created != created {
+		t.Fatalf("GetIdempotentResponse: %+v %v", stored, err)
+	}
+
+	if deleted, err := s.DeleteIdempotentResponses(created); deleted != 0 || err != nil {
+		t.Fatalf("deleted %d %v before the key was created", deleted, err)
+	}
+
+	if deleted, err := s.DeleteIdempotentResponses(model.NewTime(created.Add(time.Second))); deleted != 1 || err != nil {
+		t.Fatalf("deleted %d %v", deleted, err)
+	}
+}
+
+func TestChambers(t *testing.T) {
+
+	s := newTestStore(t)
+
+	id, err := s.InsertChamber(&model.Chamber{Cname: "KL-1", Ctimezone: "Europe/Berlin", Ciocards: []string{"cfab001", "cfab002"}})
+
+	if err != nil || id != 1 {
+		t.Fatalf("InsertChamber: %d %v", id, err)
+	}
+
+	if chamber, err := s.GetChamber(1); err != nil || chamber.Ctimezone != "Europe/Berlin" {
+		t.Fatalf("GetChamber: %+v %v", chamber, err)
+	}
+
+	_, err = s.InsertChamber(&model.Chamber{Cname: "KL-2", Ciocards: []string{"cfab002"}})
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertChamber with a fitted card: %v", err)
+	}
+
+	if chambers, _ := s.ListChambers(); len(chambers) != 1 {
+		t.Fatalf("rejected InsertChamber stored %v", chambers)
+	}
+
+	_, err = s.InsertLoopData(&model.Loop_Data{Dtpv: 20, Ddatatime: model.NewTime(time.Date(2019, 1, 15, 6, 5, 40, 0, time.UTC)), Dchamberid: 2}, false)
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertLoopData of unknown chamber: %v", err)
+	}
+
+	at := model.NewTime(time.Date(2019, 1, 15, 6, 5, 40, 0, time.UTC))
+
+	for _, chamber := range []int{0, 1} {
+
+		_, err = s.InsertLoopData(&model.Loop_Data{Dtpv: float64(20 + chamber), Ddatatime: at, Dchamberid: chamber}, false)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = s.UpdateLoopData(&model.Loop_Data{Dtpv: 30, Ddatatime: at, Dchamberid: 1}, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := s.ListChamberLoopData(1, at, model.Time{})
+
+	if err != nil || len(samples) != 1 || samples[0].Dtpv != 30 || samples[0].Dchamberid != 1 {
+		t.Fatalf("ListChamberLoopData: %v %v", samples, err)
+	}
+
+	_, err = s.InsertLoopData(&model.Loop_Data{Dtpv: 40, Ddatatime: at}, false)
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertLoopData of a date time stored already: %v", err)
+	}
+
+	for _, want := range []bool{false, true} {
+
+		sample := model.Loop_Data{Dtpv: 31, Ddatatime: at, Dchamberid: 1, Dchannels: map[string]float64{"co2": 410}}
+
+		if want {
+			sample.Ddatatime = model.NewTime(at.Add(time.Second))
+		}
+
+		_, created, err := s.UpsertLoopData(&sample, true)
+
+		if err != nil || created != want {
+			t.Fatalf("UpsertLoopData at %s: %t %v, want %t", sample.Ddatatime, created, err, want)
+		}
+	}
+
+	if samples, _ = s.ListChamberLoopData(1, at, model.Time{}); len(samples) != 2 || samples[0].Dtpv != 31 || samples[0].Dchannels["co2"] != 410 {
+		t.Fatalf("ListChamberLoopData after UpsertLoopData: %+v", samples)
+	}
+
+	err = s.UpdateChamber(1, &model.Chamber{Cname: "KL-1", Clocation: "hall B", Ciocards: []string{"cfab002"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chamber, err := s.GetChamber(1)
+
+	if err != nil || chamber.Clocation != "hall B" || len(chamber.Ciocards) != 1 || chamber.Ciocards[0] != "cfab002" {
+		t.Fatalf("GetChamber: %+v %v", chamber, err)
+	}
+
+	if err = s.UpdateChamber(2, &model.Chamber{Cname: "KL-2"}); err != store.ErrNotFound {
+		t.Fatalf("UpdateChamber of unknown id: %v", err)
+	}
+
+	if _, err = s.GetChamber(2); err != store.ErrNotFound {
+		t.Fatalf("GetChamber of unknown id: %v", err)
+	}
+}
+
+func TestLoopDataChannels(t *testing.T) {
+
+	s := newTestStore(t)
+
+	maximum := 5000.0
+
+	minimum := 6000.0
+
+	if _, err := s.InsertChannel(&model.Channel{Chname: "co2", Chminimum: &minimum, Chmaximum: &maximum}); !store.IsInvalid(err) {
+		t.Fatalf("InsertChannel with minimum above maximum: %v", err)
+	}
+
+	_, err := s.InsertChannel(&model.Channel{Chname: "co2", Chunit: "ppm", Chmaximum: &maximum})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := model.NewTime(time.Date(2019, 1, 15, 6, 5, 40, 0, time.UTC))
+
+	_, err = s.InsertLoopData(&model.Loop_Data{Ddatatime: at, Dchannels: map[string]float64{"co2": 6000}}, false)
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertLoopData above the maximum: %v", err)
+	}
+
+	if samples, _ := s.ListLoopData(); len(samples) != 0 {
+		t.Fatalf("rejected InsertLoopData stored %v", samples)
+	}
+
+	_, err = s.InsertLoopData(&model.Loop_Data{Dtpv: 20, Ddatatime: at, Dchannels: map[string]float64{"co2": 412.5, "tc_1": 23.1}}, true)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.InsertLoopData(&model.Loop_Data{Dtpv: 21, Ddatatime: model.NewTime(at.Add(10 * time.Second))}, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.UpdateLoopData(&model.Loop_Data{Dtpv: 20, Ddatatime: at, Dchannels: map[string]float64{"tc_1": 23.4}}, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := s.ListLoopDataBetween(at, model.Time{})
+
+	if err != nil || len(samples) != 2 || len(samples[0].Dchannels) != 1 || samples[0].Dchannels["tc_1"] != 23.4 || samples[1].Dchannels != nil {
+		t.Fatalf("ListLoopDataBetween: %v %v", samples, err)
+	}
+
+	channel, err := s.GetChannelByName("co2")
+
+	if err != nil || channel.Chmaximum == nil || *channel.Chmaximum != 5000 || channel.Chminimum != nil || channel.Chactive != 1 {
+		t.Fatalf("GetChannelByName: %+v %v", channel, err)
+	}
+
+	if err = s.RetireChannel(2); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.InsertLoopData(&model.Loop_Data{Ddatatime: model.NewTime(at.Add(20 * time.Second)), Dchannels: map[string]float64{"tc_1": 23}}, true)
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertLoopData of a retired channel: %v", err)
+	}
+
+	if err = s.UpdateLoopData(&model.Loop_Data{Ddatatime: model.NewTime(at.Add(time.Hour))}, false); err != store.ErrNotFound {
+		t.Fatalf("UpdateLoopData of a missing sample: %v", err)
+	}
+}
+
+func TestCalibratedLoopData(t *testing.T) {
+
+	s := newTestStore(t)
+
+	_, err := s.InsertCalibration(&model.Calibration{Calchannel: "co2", Calgain: 1})
+
+	if !store.IsInvalid(err) {
+		t.Fatalf("InsertCalibration of an unknown channel: %v", err)
+	}
+
+	_, err = s.InsertChannel(&model.Channel{Chname: "co2"})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calibrated := model.NewTime(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, channel := range []string{"co2", "temp_pv"} {
+
+		_, err = s.InsertCalibration(&model.Calibration{Calchannel: channel, Caloffset: 1, Calgain: 2, Caldate: calibrated, Calduedate: model.NewTime(calibrated.AddDate(1, 0, 0)), Calcertificate: "K-0815"})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	calibrations, err := s.ListChamberCalibrations(0)
+
+	if err != nil || len(calibrations) != 2 || calibrations[1].Calchannel != "temp_pv" || !calibrations[1].Caldate.Equal(calibrated.Time) {
+		t.Fatalf("ListChamberCalibrations: %+v %v", calibrations, err)
+	}
+
+	sample := model.Loop_Data{Dtpv: 20, Ddatatime: model.NewTime(time.Date(2019, 1, 15, 6, 5, 40, 0, time.UTC)), Dchannels: map[string]float64{"co2": 400}}
+
+	sample.Calibrate(calibrations)
+
+	_, err = s.InsertLoopData(&sample, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := s.ListLoopData()
+
+	if err != nil || len(samples) != 1 || samples[0].Dtpv != 41 || samples[0].Dchannels["co2"] != 801 {
+		t.Fatalf("ListLoopData: %+v %v", samples, err)
+	}
+
+	if raw := samples[0].Dcalibrated["co2"]; len(samples[0].Dcalibrated) != 2 || raw.Vraw != 400 || raw.Vcalibrationid != 1 {
+		t.Fatalf("calibrated values %+v", samples[0].Dcalibrated)
+	}
+
+	sample.Dcalibrated = nil
+
+	err = s.UpdateLoopData(&sample, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if samples, _ = s.ListLoopData(); samples[0].Dcalibrated != nil {
+		t.Fatalf("calibrated values not replaced %+v", samples[0].Dcalibrated)
+	}
+}
+
+func TestPruneLoopData(t *testing.T) {
+
+	s := newTestStore(t)
+
+	hour := time.Date(2019, 1, 15, 6, 0, 0, 0, time.UTC)
+
+	for i, pv := range []float64{20, 22, 24} {
+
+		_, err := s.InsertLoopData(&model.Loop_Data{Dtsp: 22, Dtpv: pv, Ddatatime: model.NewTime(hour.Add(time.Duration(i) * 20 * time.Minute)), Dchannels: map[string]float64{"co2": 400}}, true)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := s.InsertLoopData(&model.Loop_Data{Dtpv: 30, Ddatatime: model.NewTime(hour.Add(time.Hour))}, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldest, err := s.OldestLoopData()
+
+	if err != nil || !oldest.Equal(hour) {
+		t.Fatalf("OldestLoopData: %s %v", oldest, err)
+	}
+
+	var archived []model.Loop_Data
+
+	archive := func(logs []model.Loop_Data) error {
+		archived = logs
+		return nil
+	}
+
+	count, err := s.PruneLoopData(model.NewTime(hour), model.NewTime(hour.Add(time.Hour)), archive)
+
+	if err != nil || count != 3 {
+		t.Fatalf("PruneLoopData: %d %v", count, err)
+	}
+
+	if len(archived) != 3 || archived[2].Dtpv != 24 || archived[0].Dchannels["co2"] != 400 {
+		t.Fatalf("archived %+v", archived)
+	}
+
+	// A sample that arrives late is merged into the hour rolled up
+	_, err = s.InsertLoopData(&model.Loop_Data{Dtsp: 22, Dtpv: 18, Ddatatime: model.NewTime(hour.Add(50 * time.Minute))}, false)
+
+	if err == nil {
+		_, err = s.PruneLoopData(model.Time{}, model.NewTime(hour.Add(time.Hour)), archive)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hours, err := s.ListLoopDataHourly(model.Time{}, model.Time{})
+
+	if err != nil || len(hours) != 1 || !hours[0].Hhour.Equal(hour) || hours[0].Hsamples != 4 || hours[0].Htpv != 21 || hours[0].Htpvmin != 18 || hours[0].Htpvmax != 24 || hours[0].Htsp != 22 {
+		t.Fatalf("ListLoopDataHourly: %+v %v", hours, err)
+	}
+
+	if hours, _ = s.ListChamberLoopDataHourly(1, model.Time{}, model.Time{}); len(hours) != 0 {
+		t.Fatalf("ListChamberLoopDataHourly of another chamber: %+v", hours)
+	}
+
+	samples, err := s.ListLoopData()
+
+	if err != nil || len(samples) != 1 || samples[0].Dtpv != 30 {
+		t.Fatalf("ListLoopData after pruning: %+v %v", samples, err)
+	}
+
+	var values int
+
+	s.DB().QueryRow("select count(*) from ZTK_Loop_Data_Channel").Scan(&values)
+
+	if values != 0 {
+		t.Fatalf("%d channel values of pruned samples left", values)
+	}
+}
+
+func TestPruneLoopDataLeavesLateSamples(t *testing.T) {
+
+	s := newTestStore(t)
+
+	day := time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	_, err := s.InsertLoopData(&model.Loop_Data{Dtpv: 20, Ddatatime: model.NewTime(day.Add(time.Hour))}, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed archive prunes nothing
+	failed := errors.New("disk full")
+
+	count, err := s.PruneLoopData(model.NewTime(day), model.NewTime(day.AddDate(0, 0, 1)), func([]model.Loop_Data) error { return failed })
+
+	if err != failed || count != 0 {
+		t.Fatalf("PruneLoopData with a failed archive: %d %v", count, err)
+	}
+
+	// A sample of the day stored while the day is archived is kept
+	late := make(chan error, 1)
+
+	count, err = s.PruneLoopData(model.NewTime(day), model.NewTime(day.AddDate(0, 0, 1)), func(archived []model.Loop_Data) error {
+
+		if len(archived) != 1 {
+			t.Errorf("archived %+v", archived)
+		}
+
+		go func() {
+			_, err := s.InsertLoopData(&model.Loop_Data{Dtpv: 21, Ddatatime: model.NewTime(day.Add(2 * time.Hour))}, false)
+			late <- err
+		}()
+
+		return nil
+	})
+
+	if err != nil || count != 1 {
+		t.Fatalf("PruneLoopData: %d %v", count, err)
+	}
+
+	if err = <-late; err != nil {
+		t.Fatal(err)
+	}
+
+	if samples, _ := s.ListLoopData(); len(samples) != 1 || samples[0].Dtpv != 21 {
+		t.Fatalf("samples after pruning %+v, want the late one", samples)
+	}
+}
+
+func TestPruneActivities(t *testing.T) {
+
+	s := newTestStore(t)
+
+	if _, err := s.OldestActivity(); err != store.ErrNotFound {
+		t.Fatalf("OldestActivity of an empty log: %v", err)
+	}
+
+	old := model.NewTime(time.Date(2019, 1, 15, 6, 0, 0, 0, time.UTC))
+
+	_, err := s.DB().Exec("insert into ZTK_Activity_Log (ZTK_Table_Id,action_type,new_value,ZTK_Users_Id,created) values(5,'INSERT','{}',3,?)", old)
+
+	if err == nil {
+		err = s.InsertActivity(5, "UPDATE", "{}", 3)
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldest, err := s.OldestActivity()
+
+	if err != nil || !oldest.Equal(old.Time) {
+		t.Fatalf("OldestActivity: %s %v", oldest, err)
+	}
+
+	activities, err := s.ListActivities(model.Time{}, model.NewTime(old.AddDate(0, 0, 1)))
+
+	if err != nil || len(activities) != 1 || activities[0].ActionType != "INSERT" || activities[0].UserId != 3 {
+		t.Fatalf("ListActivities: %+v %v", activities, err)
+	}
+
+	failed := errors.New("disk full")
+
+	count, err := s.PruneActivities(model.Time{}, model.NewTime(old.AddDate(0, 0, 1)), func([]store.Activity) error { return failed })
+
+	if err != failed || count != 0 {
+		t.Fatalf("PruneActivities with a failed archive: %d %v", count, err)
+	}
+
+	count, err = s.PruneActivities(model.Time{}, model.NewTime(old.AddDate(0, 0, 1)), func(archived []store.Activity) error {
+		if len(archived) != 1 || archived[0].ActionType != "INSERT" {
+			t.Errorf("archived %+v", archived)
+		}
+		return nil
+	})
+
+	if err != nil || count != 1 {
+		t.Fatalf("PruneActivities: %d %v", count, err)
+	}
+
+	if activities, _ = s.ListActivities(model.Time{}, model.Time{}); len(activities) != 1 || activities[0].ActionType != "UPDATE" {
+		t.Fatalf("ListActivities after deleting: %+v", activities)
+	}
+}
+
+func TestUpsertLoopDataConcurrently(t *testing.T) {
+
+	s := newTestStore(t)
+
+	at := model.NewTime(time.Date(2019, 1, 15, 6, 5, 40, 0, time.UTC))
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	created := 0
+
+	for i := 0; i < 8; i++ {
+
+		wg.Add(1)
+
+		go func(pv float64) {
+
+			defer wg.Done()
+
+			_, ok, err := s.UpsertLoopData(&model.Loop_Data{Dtpv: pv, Ddatatime: at}, false)
+
+			if err != nil {
+				t.Error(err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if ok {
+				created++
+			}
+		}(float64(20 + i))
+	}
+
+	wg.Wait()
+
+	if samples, _ := s.ListLoopData(); len(samples) != 1 || created != 1 {
+		t.Fatalf("%d samples, %d created, want 1", len(samples), created)
+	}
+}
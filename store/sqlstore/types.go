@@ -0,0 +1,372 @@
+package sqlstore
+
+import (
+	"database/sql"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+	"github.com/Ramcharanpakala/goprojectes/store"
+)
+
+// Struct to hold the table and name column of a type table
+
+type typeTable struct {
+	table  string
+	column string
+}
+
+var eventTypes = typeTable{"ZTK_Logs_Event_Type", "events_type"}
+
+var testTypes = typeTable{"ZTK_Logs_Test_Type", "test_type"}
+
+// Struct to hold the columns of a type row shared by both type tables
+
+type typeRow struct {
+	name           string
+	createdBy      int
+	modifiedBy     int
+	created        model.Time
+	modified       model.Time
+	deviceCreated  model.Time
+	deviceModified model.Time
+}
+
+func eventTypeRow(t *model.Logs_Event_Type) typeRow {
+
+	return typeRow{t.Levents, t.Lcreated, t.Lmodified, t.Lcreated1, t.Lmodified2, t.Ldevicecreated, t.Ldevicemodified}
+}
+
+func testTypeRow(t *model.Logs_Test_Type) typeRow {
+
+	return typeRow{t.Ltesttype, t.Tcreatedby1, t.Tmodifiedby2, t.Tcreated1, t.Tmodified2, t.Tdevicecreated1, t.Tdevicemodified2}
+}
+
+func (s *Store) InsertEventType(t *model.Logs_Event_Type) (int64, error) {
+
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		err := eventTypes.checkName(tx, t.Levents, 0)
+
+		if err == nil {
+			id, err = eventTypes.insert(tx, eventTypeRow(t))
+		}
+
+		return err
+	})
+
+	return id, err
+}
+
+func (s *Store) ListEventTypes() ([]model.Logs_Event_Type, error) {
+
+	logs := []model.Logs_Event_Type{}
+
+	rows, err := s.db.Query("select id,active,events_type,created_by,modified_by,coalesce(created,''),coalesce(modified,''),coalesce(device_created,''),coalesce(device_modified,'') from ZTK_Logs_Event_Type order by id")
+
+	if err != nil {
+		return logs, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var log model.Logs_Event_Type
+		err = rows.Scan(&log.Lid, &log.Lactive, &log.Levents, &log.Lcreated, &log.Lmodified, &log.Lcreated1, &log.Lmodified2, &log.Ldevicecreated, &log.Ldevicemodified)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+func (s *Store) GetEventTypeByName(name string) (model.Logs_Event_Type, error) {
+
+	var log model.Logs_Event_Type
+
+	row := s.db.QueryRow("select id,active,events_type,created_by,modified_by,coalesce(created,''),coalesce(modified,''),coalesce(device_created,''),coalesce(device_modified,'') from ZTK_Logs_Event_Type where "+eventTypes.nameIs(), name)
+
+	err := row.Scan(&log.Lid, &log.Lactive, &log.Levents, &log.Lcreated, &log.Lmodified, &log.Lcreated1, &log.Lmodified2, &log.Ldevicecreated, &log.Ldevicemodified)
+
+	if err == sql.ErrNoRows {
+		return log, store.ErrNotFound
+	}
+
+	return log, err
+}
+
+func (s *Store) UpdateEventType(id int, t *model.Logs_Event_Type) error {
+
+	return s.inTx(func(tx *sql.Tx) error {
+		return eventTypes.update(tx, id, eventTypeRow(t))
+	})
+}
+
+func (s *Store) RetireEventType(id int) error {
+
+	return eventTypes.retire(s.db, id)
+}
+
+func (s *Store) InsertTestType(t *model.Logs_Test_Type) (int64, error) {
+
+	var id int64
+
+	err := s.inTx(func(tx *sql.Tx) error {
+
+		err := testTypes.checkName(tx, t.Ltesttype, 0)
+
+		if err == nil {
+			id, err = testTypes.insert(tx, testTypeRow(t))
+		}
+
+		return err
+	})
+
+	return id, err
+}
+
+func (s *Store) ListTestTypes() ([]model.Logs_Test_Type, error) {
+
+	logs := []model.Logs_Test_Type{}
+
+	rows, err := s.db.Query("select id,active,test_type,coalesce(created,''),coalesce(modified,''),created_by,modified_by,coalesce(device_created,''),coalesce(device_modified,'') from ZTK_Logs_Test_Type order by id")
+
+	if err != nil {
+		return logs, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var log model.Logs_Test_Type
+		err = rows.Scan(&log.Lid, &log.Lactive, &log.Ltesttype, &log.Tcreated1, &log.Tmodified2, &log.Tcreatedby1, &log.Tmodifiedby2, &log.Tdevicecreated1, &log.Tdevicemodified2)
+		if err != nil {
+			return logs, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, rows.Err()
+}
+
+func (s *Store) GetTestTypeByName(name string) (model.Logs_Test_Type, error) {
+
+	var log model.Logs_Test_Type
+
+	row := s.db.QueryRow("select id,active,test_type,coalesce(created,''),coalesce(modified,''),created_by,modified_by,coalesce(device_created,''),coalesce(device_modified,'') from ZTK_Logs_Test_Type where "+testTypes.nameIs(), name)
+
+	err := row.Scan(&log.Lid, &log.Lactive, &log.Ltesttype, &log.Tcreated1, &log.Tmodified2, &log.Tcreatedby1, &log.Tmodifiedby2, &log.Tdevicecreated1, &log.Tdevicemodified2)
+
+	if err == sql.ErrNoRows {
+		return log, store.ErrNotFound
+	}
+
+	return log, err
+}
+
+func (s *Store) UpdateTestType(id int, t *model.Logs_Test_Type) error {
+
+	return s.inTx(func(tx *sql.Tx) error {
+		return testTypes.update(tx, id, testTypeRow(t))
+	})
+}
+
+func (s *Store) RetireTestType(id int) error {
+
+	return testTypes.retire(s.db, id)
+}
+
+// Check that name is set and not used by another row of the type table.
+// exceptId is the id of the row being renamed, or 0 for a new row.
+func (t typeTable) checkName(q queryer, name string, exceptId int) error {
+
+	var count int
+
+	if name == "" {
+		return store.Invalid("%s is required", t.column)
+	}
+
+	err := q.QueryRow("select count(id) from "+t.table+" where "+t.nameIs()+" and id <> ?", name, exceptId).Scan(&count)
+
+	if err != nil {
+		return err
+	}
+
+	if count != 0 {
+		return store.Invalid("%s %q already exists", t.column, name)
+	}
+
+	return nil
+}
+
+// Condition matching the row named by the next argument. Names are compared
+// without case, as the unique key of the MySQL collation and of the SQLite
+// index does.
+func (t typeTable) nameIs() string {
+
+	return "lower(" + t.column + ") = lower(?)"
+}
+
+func (t typeTable) insert(q queryer, row typeRow) (int64, error) {
+
+	result, err := q.Exec("insert into "+t.table+" ("+t.column+",created_by,modified_by,created,modified,device_created,device_modified,active ) values(?,?,?,?,?,?,?,1);", row.name, row.createdBy, row.modifiedBy, row.created, row.modified, row.deviceCreated, row.deviceModified)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return result.LastInsertId()
+}
+
+func (t typeTable) exists(q queryer, id int) error {
+
+	var count int
+
+	err := q.QueryRow("select count(id) from "+t.table+" where id = ?", id).Scan(&count)
+
+	if err == nil && count == 0 {
+		err = store.ErrNotFound
+	}
+
+	return err
+}
+
+// Rename a type. Renaming to the name of another type is rejected.
+func (t typeTable) update(q queryer, id int, row typeRow) error {
+
+	err := t.exists(q, id)
+
+	if err == nil {
+		err = t.checkName(q, row.name, id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec("update "+t.table+" set "+t.column+"=?,modified_by=?,modified=?,device_modified=? where id = ?", row.name, row.modifiedBy, row.modified, row.deviceModified, id)
+
+	return err
+}
+
+func (t typeTable) retire(q queryer, id int) error {
+
+	err := t.exists(q, id)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = q.Exec("update "+t.table+" set active=0 where id = ?", id)
+
+	return err
+}
+
+// Resolve a type given by name in a log to its id. When name is empty the
+// given id is returned unchanged. An unknown name is created as a new type,
+// stamped like the log, if autoCreate is set, otherwise it is rejected, as is
+// a name that does not match an id also given in the log.
+func (t typeTable) resolve(q queryer, name string, id int, autoCreate bool, createdBy int, created model.Time) (int, error) {
+
+	var typeId int
+
+	if name == "" {
+		return id, nil
+	}
+
+	err := q.QueryRow("select id from "+t.table+" where "+t.nameIs(), name).Scan(&typeId)
+
+	if err == sql.ErrNoRows && autoCreate {
+
+		newId, err := t.insert(q, typeRow{name: name, createdBy: createdBy, modifiedBy: createdBy, created: created, modified: created})
+
+		if duplicate, lock := duplicateKey(err); duplicate {
+			return t.createdMeanwhile(q, name, lock)
+		}
+
+		return int(newId), err
+	}
+
+	if err == sql.ErrNoRows {
+		return 0, store.Invalid("%s %q does not exist", t.column, name)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if id != 0 && id != typeId {
+		return 0, store.Invalid("%s %q does not match %s_id %d", t.column, name, t.table, id)
+	}
+
+	return typeId, nil
+}
+
+// Id of the type named name that another log created after it was looked up
+// and before it could be inserted, so both logs get the same type
+func (t typeTable) createdMeanwhile(q queryer, name string, lock string) (int, error) {
+
+	var typeId int
+
+	err := q.QueryRow("select id from "+t.table+" where "+t.nameIs()+lock, name).Scan(&typeId)
+
+	return typeId, err
+}
+
+// Check that a type id referenced by a log exists and has not been retired.
+func (t typeTable) check(q queryer, id int) error {
+
+	var active int
+
+	err := q.QueryRow("select active from "+t.table+" where id = ?", id).Scan(&active)
+
+	if err == sql.ErrNoRows {
+		return store.Invalid("%s_id %d does not exist", t.table, id)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if active == 0 {
+		return store.Invalid("%s_id %d is retired", t.table, id)
+	}
+
+	return nil
+}
+
+// Resolve a type embedded in a Logs_All document to its id, creating it
+// from the embedded fields when no type of that name exists yet.
+func (t typeTable) resolveNested(q queryer, row typeRow) (int, error) {
+
+	var typeId, active int
+
+	if row.name == "" {
+		return 0, store.Invalid("%s is required", t.column)
+	}
+
+	err := q.QueryRow("select id,active from "+t.table+" where "+t.nameIs(), row.name).Scan(&typeId, &active)
+
+	if err == sql.ErrNoRows {
+
+		newId, err := t.insert(q, row)
+
+		if duplicate, lock := duplicateKey(err); duplicate {
+			return t.createdMeanwhile(q, row.name, lock)
+		}
+
+		return int(newId), err
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if active == 0 {
+		return 0, store.Invalid("%s %q is retired", t.column, row.name)
+	}
+
+	return typeId, nil
+}
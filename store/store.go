@@ -0,0 +1,188 @@
+// Package store defines the storage of the klima_chamber log tables used by
+// the NGCS Local Log Server. Implementations live in the sub packages.
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Ramcharanpakala/goprojectes/model"
+)
+
+// Returned when the requested record does not exist
+var ErrNotFound = errors.New("not found")
+
+// Returned when a record is rejected because of the data it holds, e.g. a
+// duplicate type name or a reference to a retired type.
+
+type InvalidError struct {
+	Message string
+}
+
+func (e *InvalidError) Error() string {
+	return e.Message
+}
+
+// Create an InvalidError with a formatted message
+func Invalid(format string, args ...interface{}) error {
+	return &InvalidError{Message: fmt.Sprintf(format, args...)}
+}
+
+// Report whether err rejects the data of a record
+func IsInvalid(err error) bool {
+
+	var invalid *InvalidError
+
+	return errors.As(err, &invalid)
+}
+
+// Struct to hold the ids generated for a Logs_All document
+
+type AllIds struct {
+	EventTypeId   int   `json:"ZTK_Logs_Event_Type_id,omitempty"`
+	EventId       int64 `json:"ZTK_Logs_Event_id,omitempty"`
+	TestTypeId    int   `json:"ZTK_Logs_Test_Type_id,omitempty"`
+	TestId        int64 `json:"ZTK_Logs_Test_id,omitempty"`
+	MaintenanceId int64 `json:"ZTK_Logs_Maintenance_id,omitempty"`
+}
+
+// Struct to hold a row of ZTK_Activity_Log
+
+type Activity struct {
+	Id         int64      `json:"id"`
+	TableId    int        `json:"ZTK_Table_Id"`
+	ActionType string     `json:"action_type"`
+	NewValue   string     `json:"new_value"`
+	UserId     int        `json:"ZTK_Users_Id"`
+	Created    model.Time `json:"created"`
+}
+
+// Struct to hold the response to a request sent with an Idempotency-Key, so
+// the request can be answered the same when it is sent again. Keys are per
+// user: RequestHash tells a retry from another request reusing the key.
+
+type IdempotentResponse struct {
+	UserId      int
+	Key         string
+	Method      string
+	Path        string
+	RequestHash string
+	StatusCode  int
+	Body        []byte
+	Created     model.Time
+}
+
+// Storage of the log tables.
+//
+// Inserting a Logs_Event or Logs_Test resolves a type given by name to its
+// id, creating the type when autoCreate is set, and rejects ids of unknown or
+// retired types with an InvalidError. Lookups return ErrNotFound when there
+// is no such record. Inserting an IdempotentResponse whose user and key are
+// stored already fails with an InvalidError. ListLoopDataBetween, ListActivities
+// and the Hourly methods leave the side of a zero start or end open.
+//
+// Records referring to a chamber that is not registered are rejected with an
+// InvalidError, as are chambers whose name or IO card serial number is used
+// by another chamber. Loop_Data is identified by its chamber and date time:
+// inserting a second sample of a chamber at a date time fails with an
+// InvalidError, UpsertLoopData replaces it atomically and reports whether the
+// sample was created. The ListChamber methods return the records of one
+// chamber, chamber 0 being the records not assigned to one.
+//
+// The values of a Loop_Data sample's channels are checked against the
+// channel catalogue: a channel that is unknown, unless autoCreate is set, or
+// retired, or a value outside its range, rejects the sample with an
+// InvalidError, see CheckChannel. Channel names are unique and the names of
+// the Loop_Data columns cannot be catalogued. UpdateLoopData replaces the
+// channel values of the sample, or returns ErrNotFound when the chamber has
+// no sample at its date time.
+//
+// A calibration must be of a registered chamber, or 0, and of a process
+// value column or a catalogued channel. The Calibrated values of a Loop_Data
+// sample are stored with it. ListChamberCalibrations returns the
+// calibrations of one chamber, oldest first.
+//
+// PruneLoopData passes the samples captured at or after start and before end
+// to archive, rolls them up into Loop_Data_Hourly, merging them into the
+// hours rolled up already, and deletes them with their channel and
+// calibrated values in one transaction. Only the samples archived are
+// deleted; an archive error prunes nothing. PruneActivities archives and
+// deletes Activity Log rows the same way. The Oldest methods return
+// ErrNotFound when the table is empty.
+
+type Store interface {
+	InsertEventLog(log *model.Logs_Event, autoCreate bool) (int64, error)
+	ListEventLogs() ([]model.Logs_Event, error)
+
+	InsertEventType(t *model.Logs_Event_Type) (int64, error)
+	ListEventTypes() ([]model.Logs_Event_Type, error)
+	GetEventTypeByName(name string) (model.Logs_Event_Type, error)
+	UpdateEventType(id int, t *model.Logs_Event_Type) error
+	RetireEventType(id int) error
+
+	InsertTestLog(log *model.Logs_Test, autoCreate bool) (int64, error)
+	ListTestLogs() ([]model.Logs_Test, error)
+	GetTestLog(logId string) (int64, model.Logs_Test, error)
+	SetTestVerdict(id int64, profileId int, verdict string, detail string) error
+	GetTestVerdict(id int64) (string, error)
+
+	InsertTestType(t *model.Logs_Test_Type) (int64, error)
+	ListTestTypes() ([]model.Logs_Test_Type, error)
+	GetTestTypeByName(name string) (model.Logs_Test_Type, error)
+	UpdateTestType(id int, t *model.Logs_Test_Type) error
+	RetireTestType(id int) error
+
+	InsertTestProfile(profile *model.Logs_Test_Profile) (int, int, error)
+	GetTestProfile(typeId int, version int) (model.Logs_Test_Profile, error)
+	ListTestProfiles(typeId int) ([]model.Logs_Test_Profile, error)
+
+	InsertMaintenanceLog(log *model.Logs_Maintenance) (int64, error)
+	ListMaintenanceLogs() ([]model.Logs_Maintenance, error)
+
+	InsertLoopData(log *model.Loop_Data, autoCreate bool) (int64, error)
+	UpdateLoopData(log *model.Loop_Data, autoCreate bool) error
+	UpsertLoopData(log *model.Loop_Data, autoCreate bool) (int64, bool, error)
+	ListLoopData() ([]model.Loop_Data, error)
+	ListLoopDataBetween(start model.Time, end model.Time) ([]model.Loop_Data, error)
+	OldestLoopData() (model.Time, error)
+	PruneLoopData(start model.Time, end model.Time, archive func([]model.Loop_Data) error) (int64, error)
+	ListLoopDataHourly(start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error)
+
+	InsertChannel(channel *model.Channel) (int64, error)
+	ListChannels() ([]model.Channel, error)
+	GetChannelByName(name string) (model.Channel, error)
+	UpdateChannel(id int, channel *model.Channel) error
+	RetireChannel(id int) error
+
+	InsertCalibration(calibration *model.Calibration) (int64, error)
+	ListCalibrations() ([]model.Calibration, error)
+	ListChamberCalibrations(chamberId int) ([]model.Calibration, error)
+
+	InsertIocardinfo(log *model.Io_card_Info) (int64, error)
+	ListIocardinfo() ([]model.Io_card_Info, error)
+
+	InsertAll(log *model.Logs_All, autoCreate bool) (AllIds, error)
+
+	InsertChamber(chamber *model.Chamber) (int64, error)
+	ListChambers() ([]model.Chamber, error)
+	GetChamber(id int) (model.Chamber, error)
+	UpdateChamber(id int, chamber *model.Chamber) error
+
+	ListChamberEventLogs(chamberId int) ([]model.Logs_Event, error)
+	ListChamberTestLogs(chamberId int) ([]model.Logs_Test, error)
+	ListChamberMaintenanceLogs(chamberId int) ([]model.Logs_Maintenance, error)
+	ListChamberLoopData(chamberId int, start model.Time, end model.Time) ([]model.Loop_Data, error)
+	ListChamberLoopDataHourly(chamberId int, start model.Time, end model.Time) ([]model.Loop_Data_Hourly, error)
+	ListChamberIocardinfo(chamberId int) ([]model.Io_card_Info, error)
+
+	InsertActivity(tableId int, actionType string, newvalue string, userId int) error
+	OldestActivity() (model.Time, error)
+	ListActivities(start model.Time, end model.Time) ([]Activity, error)
+	PruneActivities(start model.Time, end model.Time, archive func([]Activity) error) (int64, error)
+
+	GetIdempotentResponse(userId int, key string) (IdempotentResponse, error)
+	InsertIdempotentResponse(response *IdempotentResponse) error
+	DeleteIdempotentResponses(before model.Time) (int64, error)
+
+	Close() error
+}